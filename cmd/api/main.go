@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 
 	_ "github.com/arman300s/uni-portal/cmd/api/docs"
 	"github.com/arman300s/uni-portal/internal/core/repositories"
@@ -13,9 +14,12 @@ import (
 	"github.com/arman300s/uni-portal/internal/http/controllers"
 	"github.com/arman300s/uni-portal/internal/models"
 	"github.com/arman300s/uni-portal/internal/seeder"
+	"github.com/arman300s/uni-portal/pkg/auth"
 	"github.com/arman300s/uni-portal/pkg/cache"
 	"github.com/arman300s/uni-portal/pkg/db"
+	"github.com/arman300s/uni-portal/pkg/pdf"
 	"github.com/arman300s/uni-portal/pkg/queue"
+	"github.com/arman300s/uni-portal/pkg/storage"
 )
 
 func main() {
@@ -30,36 +34,120 @@ func main() {
 
 	db.Connect()
 
+	if err := auth.Init(); err != nil {
+		log.Fatalf("failed to load jwt keys: %v", err)
+	}
+
 	if err := cache.Init(); err != nil {
 		log.Fatalf("failed to init redis: %v", err)
 	}
 
+	appCache, err := cache.NewFromEnv()
+	if err != nil {
+		log.Fatalf("failed to init cache: %v", err)
+	}
+
+	blobStore, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("failed to init storage: %v", err)
+	}
+
+	pdfFonts, err := pdf.FontsFromEnv()
+	if err != nil {
+		log.Fatalf("failed to load PDF fonts: %v", err)
+	}
+
 	redisAddr := os.Getenv("REDIS_HOST") + ":" + os.Getenv("REDIS_PORT")
 	queue.Init(redisAddr)
 
-	if err := db.DB.AutoMigrate(&models.Role{}, &models.User{}, &models.Subject{}); err != nil {
+	if err := db.DB.SetupJoinTable(&models.Role{}, "Permissions", &models.RolePermission{}); err != nil {
+		log.Fatalf("failed to set up role permissions: %v", err)
+	}
+
+	// Users created before email verification existed count as verified.
+	// This only runs when the column is first added, so that later signups
+	// still have to verify.
+	backfillVerified := !db.DB.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt")
+
+	if err := db.DB.AutoMigrate(&models.Permission{}, &models.Role{}, &models.User{}, &models.Term{}, &models.Subject{}, &models.CourseSection{}, &models.Building{}, &models.Room{}, &models.SectionMeeting{}, &models.Enrollment{}, &models.RequisiteGroup{}, &models.RequisiteOption{}, &models.AssessmentComponent{}, &models.AssessmentScore{}, &models.GradeBand{}, &models.TranscriptRecord{}, &models.ClassSession{}, &models.AttendanceRecord{}, &models.Rubric{}, &models.RubricCriterion{}, &models.RubricLevel{}, &models.Assignment{}, &models.AssignmentExtension{}, &models.Submission{}, &models.SubmissionFile{}, &models.RubricScore{}, &models.SimilarityReport{}, &models.SimilarityMatch{}, &models.SimilaritySpan{}, &models.Quiz{}, &models.QuizQuestion{}, &models.QuizOption{}, &models.QuizAttempt{}, &models.QuizAnswer{}, &models.RefreshToken{}, &models.UserToken{}, &models.RecoveryCode{}); err != nil {
 		log.Fatalf("failed to migrate: %v", err)
 	}
+	if backfillVerified {
+		if err := db.DB.Model(&models.User{}).Where("email_verified_at IS NULL").Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			log.Fatalf("failed to backfill email verification: %v", err)
+		}
+	}
 
 	seeder.SeedRoles(db.DB)
+	seeder.SeedPermissions(db.DB)
 	seeder.SeedAdmin(db.DB)
 	seeder.SeedTeachers(db.DB)
 	seeder.SeedSubjects(db.DB)
+	seeder.SeedGradeScale(db.DB)
 
 	userRepo := repositories.NewUserRepository(db.DB)
 	roleRepo := repositories.NewRoleRepository(db.DB)
 	subjectRepo := repositories.NewSubjectRepository(db.DB)
-
-	authService := services.NewAuthService(userRepo, roleRepo)
-	userService := services.NewUserService(userRepo, roleRepo)
-	subjectService := services.NewSubjectService(subjectRepo, userRepo)
+	permissionRepo := repositories.NewPermissionRepository(db.DB)
+	termRepo := repositories.NewTermRepository(db.DB)
+	sectionRepo := repositories.NewSectionRepository(db.DB)
+	enrollmentRepo := repositories.NewEnrollmentRepository(db.DB)
+	gradebookRepo := repositories.NewGradebookRepository(db.DB)
+	gradeScaleRepo := repositories.NewGradeScaleRepository(db.DB)
+	transcriptRepo := repositories.NewTranscriptRepository(db.DB)
+	attendanceRepo := repositories.NewAttendanceRepository(db.DB)
+	roomRepo := repositories.NewRoomRepository(db.DB)
+	timetableRepo := repositories.NewTimetableRepository(db.DB)
+	assignmentRepo := repositories.NewAssignmentRepository(db.DB)
+	rubricRepo := repositories.NewRubricRepository(db.DB)
+	similarityRepo := repositories.NewSimilarityRepository(db.DB)
+	quizRepo := repositories.NewQuizRepository(db.DB)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db.DB)
+	userTokenRepo := repositories.NewUserTokenRepository(db.DB)
+	recoveryCodeRepo := repositories.NewRecoveryCodeRepository(db.DB)
+
+	auth.SetVersionStore(services.NewTokenVersionStore(userRepo))
+
+	authService := services.NewAuthService(userRepo, roleRepo, refreshTokenRepo, userTokenRepo, recoveryCodeRepo, appCache)
+	userService := services.NewUserService(userRepo, roleRepo, refreshTokenRepo, appCache)
+	subjectService := services.NewSubjectService(subjectRepo, userRepo, termRepo, enrollmentRepo, appCache)
+	roleService := services.NewRoleService(roleRepo, permissionRepo, refreshTokenRepo, appCache)
+	termService := services.NewTermService(termRepo, appCache)
+	roomService := services.NewRoomService(roomRepo)
+	timetableService := services.NewTimetableService(timetableRepo, sectionRepo, roomRepo, enrollmentRepo, termRepo)
+	sectionService := services.NewSectionService(sectionRepo, subjectRepo, termRepo, userRepo, enrollmentRepo, timetableService)
+	enrollmentService := services.NewEnrollmentService(enrollmentRepo, termRepo, subjectService, timetableService)
+	gradebookService := services.NewGradebookService(gradebookRepo, enrollmentRepo, gradeScaleRepo, termRepo)
+	rubricService := services.NewRubricService(rubricRepo)
+	assignmentService := services.NewAssignmentService(assignmentRepo, sectionRepo, subjectRepo, termRepo, blobStore, rubricRepo, gradebookService)
+	similarityService := services.NewSimilarityService(similarityRepo, assignmentRepo, blobStore)
+	quizService := services.NewQuizService(quizRepo, sectionRepo, subjectRepo, termRepo)
+	gradeScaleService := services.NewGradeScaleService(gradeScaleRepo)
+	transcriptService := services.NewTranscriptService(enrollmentRepo, userRepo, gradeScaleRepo, transcriptRepo, pdfFonts)
+	attendanceService := services.NewAttendanceService(attendanceRepo, sectionRepo, enrollmentRepo, termRepo)
 
 	routeDeps := RouteDeps{
+		Cache:        appCache,
 		Auth:         controllers.NewAuthController(authService),
+		MFA:          controllers.NewMFAController(authService),
 		User:         controllers.NewUserController(userService),
+		Role:         controllers.NewRoleController(roleService),
+		Term:         controllers.NewTermController(termService),
+		AdminSection: controllers.NewAdminSectionController(sectionService),
+		Enrollment:   controllers.NewEnrollmentController(enrollmentService),
+		Gradebook:    controllers.NewGradebookController(gradebookService),
+		GradeScale:   controllers.NewGradeScaleController(gradeScaleService),
+		Transcript:   controllers.NewTranscriptController(transcriptService),
+		Attendance:   controllers.NewAttendanceController(attendanceService),
+		Room:         controllers.NewRoomController(roomService),
+		Timetable:    controllers.NewTimetableController(timetableService),
+		Assignment:   controllers.NewAssignmentController(assignmentService),
+		Rubric:       controllers.NewRubricController(rubricService),
+		Similarity:   controllers.NewSimilarityController(similarityService),
+		Quiz:         controllers.NewQuizController(quizService),
 		AdminSubject: controllers.NewAdminSubjectController(subjectService),
 		Student:      controllers.NewStudentController(subjectService),
-		Teacher:      controllers.NewTeacherController(subjectService),
+		Teacher:      controllers.NewTeacherController(sectionService),
 	}
 
 	r := mux.NewRouter()
@@ -7,58 +7,210 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	"github.com/arman300s/uni-portal/internal/http/controllers"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/cache"
 	"github.com/arman300s/uni-portal/pkg/middleware"
 )
 
-// RouteDeps groups all controllers required by the router.
+// RouteDeps groups all controllers required by the router, and the cache
+// that permission checks read through.
 type RouteDeps struct {
+	Cache cache.Cache
+
 	Auth         *controllers.AuthController
+	MFA          *controllers.MFAController
 	User         *controllers.UserController
+	Role         *controllers.RoleController
+	Term         *controllers.TermController
+	AdminSection *controllers.AdminSectionController
+	Enrollment   *controllers.EnrollmentController
+	Gradebook    *controllers.GradebookController
+	GradeScale   *controllers.GradeScaleController
+	Transcript   *controllers.TranscriptController
+	Attendance   *controllers.AttendanceController
+	Room         *controllers.RoomController
+	Timetable    *controllers.TimetableController
+	Assignment   *controllers.AssignmentController
+	Rubric       *controllers.RubricController
+	Similarity   *controllers.SimilarityController
+	Quiz         *controllers.QuizController
 	AdminSubject *controllers.AdminSubjectController
 	Student      *controllers.StudentController
 	Teacher      *controllers.TeacherController
 }
 
 func SetupRoutes(r *mux.Router, deps RouteDeps) {
+	// can wraps a handler so that it only runs for users holding permission.
+	can := func(permission string, h http.HandlerFunc) http.Handler {
+		return middleware.RequirePermission(deps.Cache, permission)(h)
+	}
+
 	// Swagger docs
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
 	// Public routes
+	r.HandleFunc("/.well-known/jwks.json", deps.Auth.JWKS).Methods("GET")
 	r.HandleFunc("/signup", deps.Auth.Signup).Methods("POST")
 	r.HandleFunc("/login", deps.Auth.Login).Methods("POST")
+	r.HandleFunc("/login/mfa", deps.MFA.Login).Methods("POST")
+	r.HandleFunc("/auth/refresh", deps.Auth.Refresh).Methods("POST")
+	r.HandleFunc("/password/forgot", deps.Auth.ForgotPassword).Methods("POST")
+	r.HandleFunc("/password/reset", deps.Auth.ResetPassword).Methods("POST")
+	r.HandleFunc("/verify-email", deps.Auth.VerifyEmail).Methods("GET")
+	r.HandleFunc("/verify/transcript/{hash}", deps.Transcript.VerifyTranscript).Methods("GET")
+	r.Handle("/verify-email/resend", middleware.JWTAuth(http.HandlerFunc(deps.Auth.ResendVerification))).Methods("POST")
 	r.Handle("/me", middleware.JWTAuth(http.HandlerFunc(deps.User.Me))).Methods("GET")
+	r.Handle("/logout", middleware.JWTAuth(http.HandlerFunc(deps.Auth.Logout))).Methods("POST")
+
+	// Two-factor enrollment
+	mfa := r.PathPrefix("/mfa").Subrouter()
+	mfa.Use(middleware.JWTAuth)
+	mfa.HandleFunc("/totp/enroll", deps.MFA.EnrollTOTP).Methods("POST")
+	mfa.HandleFunc("/totp/confirm", deps.MFA.ConfirmTOTP).Methods("POST")
+	mfa.HandleFunc("/totp", deps.MFA.DisableTOTP).Methods("DELETE")
+	mfa.HandleFunc("/recovery-codes", deps.MFA.RegenerateRecoveryCodes).Methods("POST")
 
 	// Admin routes
 	admin := r.PathPrefix("/admin").Subrouter()
 	admin.Use(middleware.JWTAuth)
 	admin.Use(middleware.LoadUserMiddleware)
-	admin.Use(middleware.RequireRole("admin"))
+	admin.Use(middleware.RequireMFA)
 
 	// User management
-	admin.HandleFunc("/users", deps.User.ListUsers).Methods("GET")
-	admin.HandleFunc("/users/{id}", deps.User.GetUser).Methods("GET")
-	admin.HandleFunc("/users/{id}", deps.User.UpdateUser).Methods("PUT")
-	admin.HandleFunc("/users/{id}", deps.User.DeleteUser).Methods("DELETE")
-	admin.HandleFunc("/users/create", deps.User.CreateUser).Methods("POST")
+	admin.Handle("/users", can(models.PermUsersRead, deps.User.ListUsers)).Methods("GET")
+	admin.Handle("/users/{id}", can(models.PermUsersRead, deps.User.GetUser)).Methods("GET")
+	admin.Handle("/users/{id}", can(models.PermUsersWrite, deps.User.UpdateUser)).Methods("PUT")
+	admin.Handle("/users/{id}", can(models.PermUsersWrite, deps.User.DeleteUser)).Methods("DELETE")
+	admin.Handle("/users/create", can(models.PermUsersWrite, deps.User.CreateUser)).Methods("POST")
+	admin.Handle("/users/{id}/sessions/revoke", can(models.PermUsersWrite, deps.User.RevokeSessions)).Methods("POST")
+	admin.Handle("/users/{id}/login-attempts", can(models.PermUsersRead, deps.Auth.LoginStatus)).Methods("GET")
+	admin.Handle("/users/{id}/unlock", can(models.PermUsersWrite, deps.Auth.UnlockAccount)).Methods("POST")
+	admin.Handle("/users/{id}/transcript", can(models.PermTranscriptsRead, deps.Transcript.GetUserTranscript)).Methods("GET")
+
+	// Role management
+	admin.Handle("/permissions", can(models.PermRolesManage, deps.Role.ListPermissions)).Methods("GET")
+	admin.Handle("/roles", can(models.PermRolesManage, deps.Role.ListRoles)).Methods("GET")
+	admin.Handle("/roles/{id}", can(models.PermRolesManage, deps.Role.GetRole)).Methods("GET")
+	admin.Handle("/roles", can(models.PermRolesManage, deps.Role.CreateRole)).Methods("POST")
+	admin.Handle("/roles/{id}", can(models.PermRolesManage, deps.Role.UpdateRole)).Methods("PUT")
+	admin.Handle("/roles/{id}", can(models.PermRolesManage, deps.Role.DeleteRole)).Methods("DELETE")
+
+	// Term management
+	admin.Handle("/terms", can(models.PermTermsRead, deps.Term.ListTerms)).Methods("GET")
+	admin.Handle("/terms/current", can(models.PermTermsRead, deps.Term.CurrentTerm)).Methods("GET")
+	admin.Handle("/terms/{id}", can(models.PermTermsRead, deps.Term.GetTerm)).Methods("GET")
+	admin.Handle("/terms", can(models.PermTermsWrite, deps.Term.CreateTerm)).Methods("POST")
+	admin.Handle("/terms/{id}", can(models.PermTermsWrite, deps.Term.UpdateTerm)).Methods("PUT")
+	admin.Handle("/terms/{id}", can(models.PermTermsWrite, deps.Term.DeleteTerm)).Methods("DELETE")
+
+	// Course sections
+	admin.Handle("/sections", can(models.PermSectionsRead, deps.AdminSection.ListSections)).Methods("GET")
+	admin.Handle("/sections/{id}", can(models.PermSectionsRead, deps.AdminSection.GetSection)).Methods("GET")
+	admin.Handle("/sections", can(models.PermSectionsWrite, deps.AdminSection.CreateSection)).Methods("POST")
+	admin.Handle("/sections/{id}", can(models.PermSectionsWrite, deps.AdminSection.UpdateSection)).Methods("PUT")
+	admin.Handle("/sections/{id}", can(models.PermSectionsWrite, deps.AdminSection.DeleteSection)).Methods("DELETE")
+	admin.Handle("/sections/{id}/meetings", can(models.PermSectionsRead, deps.Timetable.GetMeetings)).Methods("GET")
+	admin.Handle("/sections/{id}/meetings", can(models.PermSectionsWrite, deps.Timetable.ReplaceMeetings)).Methods("PUT")
+	admin.Handle("/timetable/conflicts", can(models.PermSectionsRead, deps.Timetable.ConflictReport)).Methods("GET")
+
+	// Buildings and rooms
+	admin.Handle("/buildings", can(models.PermRoomsManage, deps.Room.ListBuildings)).Methods("GET")
+	admin.Handle("/buildings", can(models.PermRoomsManage, deps.Room.CreateBuilding)).Methods("POST")
+	admin.Handle("/buildings/{id}", can(models.PermRoomsManage, deps.Room.UpdateBuilding)).Methods("PUT")
+	admin.Handle("/buildings/{id}", can(models.PermRoomsManage, deps.Room.DeleteBuilding)).Methods("DELETE")
+	admin.Handle("/rooms", can(models.PermRoomsManage, deps.Room.ListRooms)).Methods("GET")
+	admin.Handle("/rooms/{id}", can(models.PermRoomsManage, deps.Room.GetRoom)).Methods("GET")
+	admin.Handle("/rooms", can(models.PermRoomsManage, deps.Room.CreateRoom)).Methods("POST")
+	admin.Handle("/rooms/{id}", can(models.PermRoomsManage, deps.Room.UpdateRoom)).Methods("PUT")
+	admin.Handle("/rooms/{id}", can(models.PermRoomsManage, deps.Room.DeleteRoom)).Methods("DELETE")
+
+	// Grading
+	admin.Handle("/grade-scale", can(models.PermGradeScale, deps.GradeScale.GetScale)).Methods("GET")
+	admin.Handle("/grade-scale", can(models.PermGradeScale, deps.GradeScale.ReplaceScale)).Methods("PUT")
 
 	// Subject management
-	admin.HandleFunc("/subjects", deps.AdminSubject.ListSubjects).Methods("GET")
-	admin.HandleFunc("/subjects/{id}", deps.AdminSubject.GetSubject).Methods("GET")
-	admin.HandleFunc("/subjects", deps.AdminSubject.CreateSubject).Methods("POST")
-	admin.HandleFunc("/subjects/{id}", deps.AdminSubject.UpdateSubject).Methods("PUT")
-	admin.HandleFunc("/subjects/{id}", deps.AdminSubject.DeleteSubject).Methods("DELETE")
+	admin.Handle("/subjects", can(models.PermSubjectsRead, deps.AdminSubject.ListSubjects)).Methods("GET")
+	admin.Handle("/subjects/{id}", can(models.PermSubjectsRead, deps.AdminSubject.GetSubject)).Methods("GET")
+	admin.Handle("/subjects", can(models.PermSubjectsWrite, deps.AdminSubject.CreateSubject)).Methods("POST")
+	admin.Handle("/subjects/{id}", can(models.PermSubjectsWrite, deps.AdminSubject.UpdateSubject)).Methods("PUT")
+	admin.Handle("/subjects/{id}", can(models.PermSubjectsWrite, deps.AdminSubject.DeleteSubject)).Methods("DELETE")
+	admin.Handle("/subjects/{id}/requisites", can(models.PermSubjectsRead, deps.AdminSubject.GetRequisites)).Methods("GET")
+	admin.Handle("/subjects/{id}/requisites", can(models.PermSubjectsWrite, deps.AdminSubject.ReplaceRequisites)).Methods("PUT")
+	admin.Handle("/subjects/{id}/eligibility", can(models.PermSubjectsRead, deps.AdminSubject.CheckEligibility)).Methods("GET")
 
-	// Student routes
+	// Student routes. Each needs a permission granted by default to
+	// students only, so that other roles cannot use the student endpoints.
 	student := r.PathPrefix("/student").Subrouter()
 	student.Use(middleware.JWTAuth)
 	student.Use(middleware.LoadUserMiddleware)
-	student.Use(middleware.RequireRole("student"))
-	student.HandleFunc("/subjects", deps.Student.ListSubjects).Methods("GET")
+	student.Use(middleware.RequireMFA)
+	student.Use(middleware.RequireVerifiedEmail)
+	student.Handle("/subjects", can(models.PermSubjectsSelf, deps.Student.ListSubjects)).Methods("GET")
+	student.Handle("/subjects/{id}/eligibility", can(models.PermSubjectsSelf, deps.Student.CheckEligibility)).Methods("GET")
+	student.Handle("/enrollments", can(models.PermEnrollSelf, deps.Enrollment.ListEnrollments)).Methods("GET")
+	student.Handle("/enrollments", can(models.PermEnrollSelf, deps.Enrollment.Enroll)).Methods("POST")
+	student.Handle("/enrollments/{id}", can(models.PermEnrollSelf, deps.Enrollment.Drop)).Methods("DELETE")
+	student.Handle("/grades", can(models.PermGradesSelf, deps.Gradebook.ListMyGrades)).Methods("GET")
+	student.Handle("/transcript", can(models.PermTranscriptSelf, deps.Transcript.GetMyTranscript)).Methods("GET")
+	student.Handle("/attendance", can(models.PermAttendanceSelf, deps.Attendance.MyAttendance)).Methods("GET")
+	student.Handle("/attendance/check-in", can(models.PermAttendanceSelf, deps.Attendance.CheckIn)).Methods("POST")
+	student.Handle("/timetable", can(models.PermTimetableSelf, deps.Timetable.StudentTimetable)).Methods("GET")
+	student.Handle("/assignments", can(models.PermAssignmentsSelf, deps.Assignment.ListStudentAssignments)).Methods("GET")
+	student.Handle("/assignments/{id}", can(models.PermAssignmentsSelf, deps.Assignment.GetStudentAssignment)).Methods("GET")
+	student.Handle("/assignments/{id}/submissions", can(models.PermAssignmentsSelf, deps.Assignment.Submit)).Methods("POST")
+	student.Handle("/submission-files/{id}", can(models.PermAssignmentsSelf, deps.Assignment.DownloadStudentFile)).Methods("GET")
+	student.Handle("/quizzes", can(models.PermQuizzesSelf, deps.Quiz.ListStudentQuizzes)).Methods("GET")
+	student.Handle("/quizzes/{id}", can(models.PermQuizzesSelf, deps.Quiz.GetStudentQuiz)).Methods("GET")
+	student.Handle("/quizzes/{id}/attempts", can(models.PermQuizzesSelf, deps.Quiz.StartAttempt)).Methods("POST")
+	student.Handle("/quiz-attempts/{id}", can(models.PermQuizzesSelf, deps.Quiz.GetStudentAttempt)).Methods("GET")
+	student.Handle("/quiz-attempts/{id}/answers", can(models.PermQuizzesSelf, deps.Quiz.SaveAnswers)).Methods("PUT")
+	student.Handle("/quiz-attempts/{id}/submit", can(models.PermQuizzesSelf, deps.Quiz.SubmitAttempt)).Methods("POST")
 
-	// Teacher routes
+	// Teacher routes, likewise guarded by teacher-only permissions.
 	teacher := r.PathPrefix("/teacher").Subrouter()
 	teacher.Use(middleware.JWTAuth)
 	teacher.Use(middleware.LoadUserMiddleware)
-	teacher.Use(middleware.RequireRole("teacher"))
-	teacher.HandleFunc("/subjects", deps.Teacher.ListMySubjects).Methods("GET")
+	teacher.Use(middleware.RequireMFA)
+	teacher.Handle("/subjects", can(models.PermSubjectsTeach, deps.Teacher.ListMySubjects)).Methods("GET")
+	teacher.Handle("/timetable", can(models.PermTimetableTeach, deps.Timetable.TeacherTimetable)).Methods("GET")
+	teacher.Handle("/sections/{id}/components", can(models.PermGradesWrite, deps.Gradebook.ListComponents)).Methods("GET")
+	teacher.Handle("/sections/{id}/components", can(models.PermGradesWrite, deps.Gradebook.ReplaceComponents)).Methods("PUT")
+	teacher.Handle("/sections/{id}/gradebook", can(models.PermGradesWrite, deps.Gradebook.GetGradebook)).Methods("GET")
+	teacher.Handle("/sections/{id}/scores", can(models.PermGradesWrite, deps.Gradebook.RecordScores)).Methods("PUT")
+	teacher.Handle("/sections/{id}/sessions", can(models.PermAttendanceManage, deps.Attendance.ListSessions)).Methods("GET")
+	teacher.Handle("/sections/{id}/sessions", can(models.PermAttendanceManage, deps.Attendance.OpenSession)).Methods("POST")
+	teacher.Handle("/sections/{id}/attendance", can(models.PermAttendanceManage, deps.Attendance.SectionReport)).Methods("GET")
+	teacher.Handle("/sessions/{id}/close", can(models.PermAttendanceManage, deps.Attendance.CloseSession)).Methods("POST")
+	teacher.Handle("/sessions/{id}/qr", can(models.PermAttendanceManage, deps.Attendance.CheckInCode)).Methods("GET")
+	teacher.Handle("/sessions/{id}/attendance", can(models.PermAttendanceManage, deps.Attendance.SessionAttendance)).Methods("GET")
+	teacher.Handle("/sessions/{id}/attendance", can(models.PermAttendanceManage, deps.Attendance.OverrideAttendance)).Methods("PUT")
+	teacher.Handle("/assignments", can(models.PermAssignmentsManage, deps.Assignment.ListTeacherAssignments)).Methods("GET")
+	teacher.Handle("/assignments", can(models.PermAssignmentsManage, deps.Assignment.CreateAssignment)).Methods("POST")
+	teacher.Handle("/assignments/{id}", can(models.PermAssignmentsManage, deps.Assignment.GetTeacherAssignment)).Methods("GET")
+	teacher.Handle("/assignments/{id}", can(models.PermAssignmentsManage, deps.Assignment.UpdateAssignment)).Methods("PUT")
+	teacher.Handle("/assignments/{id}", can(models.PermAssignmentsManage, deps.Assignment.DeleteAssignment)).Methods("DELETE")
+	teacher.Handle("/assignments/{id}/submissions", can(models.PermAssignmentsManage, deps.Assignment.ListSubmissions)).Methods("GET")
+	teacher.Handle("/assignments/{id}/extensions", can(models.PermAssignmentsManage, deps.Assignment.ListExtensions)).Methods("GET")
+	teacher.Handle("/assignments/{id}/extensions", can(models.PermAssignmentsManage, deps.Assignment.GrantExtension)).Methods("POST")
+	teacher.Handle("/assignments/{id}/release", can(models.PermAssignmentsManage, deps.Assignment.ReleaseGrades)).Methods("POST")
+	teacher.Handle("/assignments/{id}/similarity", can(models.PermAssignmentsManage, deps.Similarity.GetReport)).Methods("GET")
+	teacher.Handle("/assignments/{id}/similarity", can(models.PermAssignmentsManage, deps.Similarity.RequestCheck)).Methods("POST")
+	teacher.Handle("/submissions/{id}/grade", can(models.PermAssignmentsManage, deps.Assignment.GradeSubmission)).Methods("PUT")
+	teacher.Handle("/submissions/{id}/rubric", can(models.PermAssignmentsManage, deps.Assignment.GradeWithRubric)).Methods("PUT")
+	teacher.Handle("/submission-files/{id}", can(models.PermAssignmentsManage, deps.Assignment.DownloadTeacherFile)).Methods("GET")
+	teacher.Handle("/rubrics", can(models.PermAssignmentsManage, deps.Rubric.ListRubrics)).Methods("GET")
+	teacher.Handle("/rubrics", can(models.PermAssignmentsManage, deps.Rubric.CreateRubric)).Methods("POST")
+	teacher.Handle("/rubrics/{id}", can(models.PermAssignmentsManage, deps.Rubric.GetRubric)).Methods("GET")
+	teacher.Handle("/rubrics/{id}", can(models.PermAssignmentsManage, deps.Rubric.UpdateRubric)).Methods("PUT")
+	teacher.Handle("/rubrics/{id}", can(models.PermAssignmentsManage, deps.Rubric.DeleteRubric)).Methods("DELETE")
+	teacher.Handle("/quizzes", can(models.PermQuizzesManage, deps.Quiz.ListTeacherQuizzes)).Methods("GET")
+	teacher.Handle("/quizzes", can(models.PermQuizzesManage, deps.Quiz.CreateQuiz)).Methods("POST")
+	teacher.Handle("/quizzes/{id}", can(models.PermQuizzesManage, deps.Quiz.GetTeacherQuiz)).Methods("GET")
+	teacher.Handle("/quizzes/{id}", can(models.PermQuizzesManage, deps.Quiz.UpdateQuiz)).Methods("PUT")
+	teacher.Handle("/quizzes/{id}", can(models.PermQuizzesManage, deps.Quiz.DeleteQuiz)).Methods("DELETE")
+	teacher.Handle("/quizzes/{id}/questions", can(models.PermQuizzesManage, deps.Quiz.ReplaceQuestions)).Methods("PUT")
+	teacher.Handle("/quizzes/{id}/attempts", can(models.PermQuizzesManage, deps.Quiz.ListAttempts)).Methods("GET")
+	teacher.Handle("/quizzes/{id}/statistics", can(models.PermQuizzesManage, deps.Quiz.Statistics)).Methods("GET")
+	teacher.Handle("/quiz-attempts/{id}", can(models.PermQuizzesManage, deps.Quiz.GetTeacherAttempt)).Methods("GET")
+	teacher.Handle("/quiz-answers/{id}/grade", can(models.PermQuizzesManage, deps.Quiz.GradeAnswer)).Methods("PUT")
 }
@@ -1,32 +1,90 @@
-package main
-
-import (
-	"context"
-	"encoding/json"
-	"log"
-	"os"
-
-	"github.com/arman300s/uni-portal/pkg/tasks"
-	"github.com/hibiken/asynq"
-)
-
-func main() {
-	redisAddr := os.Getenv("REDIS_HOST") + ":" + os.Getenv("REDIS_PORT")
-	srv := asynq.NewServer(
-		asynq.RedisClientOpt{Addr: redisAddr},
-		asynq.Config{Concurrency: 10},
-	)
-
-	mux := asynq.NewServeMux()
-	mux.HandleFunc(tasks.TypeSendWelcomeEmail, func(ctx context.Context, t *asynq.Task) error {
-		var p tasks.SendWelcomeEmailPayload
-		if err := json.Unmarshal(t.Payload(), &p); err != nil {
-			return err
-		}
-		return tasks.ExecuteSendWelcomeEmail(ctx, p)
-	})
-
-	if err := srv.Run(mux); err != nil {
-		log.Fatalf("could not run worker: %v", err)
-	}
-}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/db"
+	"github.com/arman300s/uni-portal/pkg/mailer"
+	"github.com/arman300s/uni-portal/pkg/storage"
+	"github.com/arman300s/uni-portal/pkg/tasks"
+	"github.com/hibiken/asynq"
+)
+
+func main() {
+	db.Connect()
+	blobStore, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("failed to init storage: %v", err)
+	}
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("failed to init mailer: %v", err)
+	}
+	// Password resets write no cached data, so the worker runs without a
+	// cache.
+	authService := services.NewAuthService(
+		repositories.NewUserRepository(db.DB),
+		repositories.NewRoleRepository(db.DB),
+		repositories.NewRefreshTokenRepository(db.DB),
+		repositories.NewUserTokenRepository(db.DB),
+		repositories.NewRecoveryCodeRepository(db.DB),
+		nil,
+	)
+	similarityService := services.NewSimilarityService(repositories.NewSimilarityRepository(db.DB), repositories.NewAssignmentRepository(db.DB), blobStore)
+
+	redisAddr := os.Getenv("REDIS_HOST") + ":" + os.Getenv("REDIS_PORT")
+	srv := asynq.NewServer(
+		asynq.RedisClientOpt{Addr: redisAddr},
+		asynq.Config{Concurrency: 10},
+	)
+
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(tasks.TypeSendWelcomeEmail, func(ctx context.Context, t *asynq.Task) error {
+		var p tasks.SendWelcomeEmailPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		return tasks.ExecuteSendWelcomeEmail(ctx, mail, p)
+	})
+	mux.HandleFunc(tasks.TypeRequestPasswordReset, func(ctx context.Context, t *asynq.Task) error {
+		var p tasks.RequestPasswordResetPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		email, err := authService.PasswordResetEmail(ctx, p.Email)
+		if err != nil || email == nil {
+			return err
+		}
+		return tasks.ExecuteSendPasswordResetEmail(ctx, mail, *email)
+	})
+	mux.HandleFunc(tasks.TypeSendVerificationEmail, func(ctx context.Context, t *asynq.Task) error {
+		var p tasks.SendVerificationEmailPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		return tasks.ExecuteSendVerificationEmail(ctx, mail, p)
+	})
+	mux.HandleFunc(tasks.TypeSendWaitlistPromotion, func(ctx context.Context, t *asynq.Task) error {
+		var p tasks.SendWaitlistPromotionPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		return tasks.ExecuteSendWaitlistPromotion(ctx, mail, p)
+	})
+	mux.HandleFunc(tasks.TypeCheckSimilarity, func(ctx context.Context, t *asynq.Task) error {
+		var p tasks.CheckSimilarityPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return err
+		}
+		return similarityService.Run(ctx, p)
+	})
+
+	if err := srv.Run(mux); err != nil {
+		log.Fatalf("could not run worker: %v", err)
+	}
+}
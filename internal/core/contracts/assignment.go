@@ -0,0 +1,162 @@
+package contracts
+
+import (
+	"io"
+	"time"
+)
+
+// LatePolicyDTO configures late submissions. Work is accepted without
+// penalty for GraceMinutes after the deadline. With a CutoffAt it is then
+// accepted until the cutoff, losing PenaltyPercentPerDay of the score for
+// every started day past the grace period; without one the assignment
+// closes when the grace period ends, so a penalty requires a cutoff.
+type LatePolicyDTO struct {
+	GraceMinutes         int        `json:"grace_minutes"`
+	PenaltyPercentPerDay float64    `json:"penalty_percent_per_day"`
+	CutoffAt             *time.Time `json:"cutoff_at,omitempty"`
+}
+
+// AssignmentInput creates an assignment for one section, or for every
+// section of a subject in a term. Exactly one of SectionID and SubjectID
+// must be set; Term (an ID or "current", the default) only applies to
+// subject-wide assignments. AllowedTypes lists file extensions such as
+// "pdf"; empty allows any file.
+type AssignmentInput struct {
+	SectionID    *uint         `json:"section_id"`
+	SubjectID    *uint         `json:"subject_id"`
+	Term         string        `json:"term"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	DueAt        time.Time     `json:"due_at"`
+	MaxPoints    float64       `json:"max_points"`
+	AllowedTypes []string      `json:"allowed_types"`
+	LatePolicy   LatePolicyDTO `json:"late_policy"`
+	Grading      GradingInput  `json:"grading"`
+}
+
+// GradingInput sets how an assignment is graded. RubricID is one of the
+// teacher's rubrics; GradebookComponentID is a gradebook component of the
+// assignment's section that released scores are copied into.
+type GradingInput struct {
+	RubricID             *uint `json:"rubric_id"`
+	GradebookComponentID *uint `json:"gradebook_component_id"`
+}
+
+// UpdateAssignmentInput replaces the editable fields of an assignment; who
+// it is for cannot change.
+type UpdateAssignmentInput struct {
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	DueAt        time.Time     `json:"due_at"`
+	MaxPoints    float64       `json:"max_points"`
+	AllowedTypes []string      `json:"allowed_types"`
+	LatePolicy   LatePolicyDTO `json:"late_policy"`
+	Grading      GradingInput  `json:"grading"`
+}
+
+type AssignmentDTO struct {
+	ID           uint          `json:"id"`
+	SubjectID    uint          `json:"subject_id"`
+	SubjectName  string        `json:"subject_name"`
+	TermID       uint          `json:"term_id"`
+	SectionID    *uint         `json:"section_id,omitempty"`
+	SectionCode  string        `json:"section_code,omitempty"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	DueAt        time.Time     `json:"due_at"`
+	MaxPoints    float64       `json:"max_points"`
+	AllowedTypes []string      `json:"allowed_types"`
+	LatePolicy   LatePolicyDTO `json:"late_policy"`
+	// ExtendedDueAt is the student's own due date when they were granted an
+	// extension; ClosesAt and Open take it into account.
+	ExtendedDueAt *time.Time `json:"extended_due_at,omitempty"`
+	ClosesAt      time.Time  `json:"closes_at"`
+	Open          bool       `json:"open"`
+	// Rubric is the rubric submissions are graded with, if any.
+	Rubric               *RubricDTO `json:"rubric,omitempty"`
+	GradebookComponentID *uint      `json:"gradebook_component_id,omitempty"`
+	GradesReleasedAt     *time.Time `json:"grades_released_at,omitempty"`
+	// Submission is the caller's own submission; only set for students.
+	Submission *SubmissionDTO `json:"submission,omitempty"`
+}
+
+type SubmissionFileDTO struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+}
+
+type SubmissionDTO struct {
+	ID           uint                `json:"id"`
+	AssignmentID uint                `json:"assignment_id"`
+	StudentID    uint                `json:"student_id"`
+	StudentName  string              `json:"student_name,omitempty"`
+	Version      int                 `json:"version"`
+	SubmittedAt  time.Time           `json:"submitted_at"`
+	Late         bool                `json:"late"`
+	DaysLate     int                 `json:"days_late"`
+	Files        []SubmissionFileDTO `json:"files"`
+	Grade        *SubmissionGradeDTO `json:"grade,omitempty"`
+}
+
+// SubmissionGradeDTO shows the late penalty next to the raw score. The
+// penalty is worked out when the grade is recorded: Penalty points are
+// PenaltyPercent of RawScore, and Score is what is left. Rubric lists the
+// criterion scores when the submission was graded with a rubric; their
+// total is scaled to the assignment's max points to give RawScore.
+type SubmissionGradeDTO struct {
+	RawScore       float64             `json:"raw_score"`
+	DaysLate       int                 `json:"days_late"`
+	PenaltyPercent float64             `json:"penalty_percent"`
+	Penalty        float64             `json:"penalty"`
+	Score          float64             `json:"score"`
+	Feedback       string              `json:"feedback,omitempty"`
+	GradedAt       time.Time           `json:"graded_at"`
+	Rubric         []CriterionScoreDTO `json:"rubric,omitempty"`
+}
+
+// GradeSubmissionInput records the raw score of a submission, before any
+// late penalty.
+type GradeSubmissionInput struct {
+	Score    float64 `json:"score"`
+	Feedback string  `json:"feedback"`
+}
+
+// ExtensionInput moves one student's due date. The late policy moves along
+// with it.
+type ExtensionInput struct {
+	StudentID uint      `json:"student_id"`
+	DueAt     time.Time `json:"due_at"`
+	Reason    string    `json:"reason"`
+}
+
+type ExtensionDTO struct {
+	ID            uint      `json:"id"`
+	AssignmentID  uint      `json:"assignment_id"`
+	StudentID     uint      `json:"student_id"`
+	StudentName   string    `json:"student_name,omitempty"`
+	DueAt         time.Time `json:"due_at"`
+	Reason        string    `json:"reason,omitempty"`
+	GrantedByID   *uint     `json:"granted_by_id,omitempty"`
+	GrantedByName string    `json:"granted_by_name,omitempty"`
+	GrantedAt     time.Time `json:"granted_at"`
+}
+
+// UploadedFile is a file received in a request. Open may be called more
+// than once and each call reads the content from the start.
+type UploadedFile struct {
+	Name string
+	Size int64
+	Open func() (io.ReadCloser, error)
+}
+
+// FileContent is a stored file being sent back to a client. The caller
+// must close Body.
+type FileContent struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
@@ -0,0 +1,100 @@
+package contracts
+
+import "time"
+
+// OpenSessionInput opens a class session. A zero rotation uses the default
+// of 15 seconds; leaving out late_after_minutes uses the default of 10
+// minutes, while 0 marks every check-in after the start as late.
+type OpenSessionInput struct {
+	RotationSeconds  int  `json:"rotation_seconds"`
+	LateAfterMinutes *int `json:"late_after_minutes"`
+}
+
+type ClassSessionDTO struct {
+	ID               uint       `json:"id"`
+	SectionID        uint       `json:"section_id"`
+	StartedAt        time.Time  `json:"started_at"`
+	ClosedAt         *time.Time `json:"closed_at,omitempty"`
+	Open             bool       `json:"open"`
+	RotationSeconds  int        `json:"rotation_seconds"`
+	LateAfterMinutes int        `json:"late_after_minutes"`
+}
+
+// CheckInCodeDTO is the payload to show as a QR code. It stops being
+// accepted shortly after ExpiresAt; clients should fetch a new one every
+// RefreshIn seconds.
+type CheckInCodeDTO struct {
+	SessionID uint      `json:"session_id"`
+	Payload   string    `json:"payload"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RefreshIn int       `json:"refresh_in"`
+}
+
+type CheckInInput struct {
+	Code string `json:"code"`
+}
+
+type CheckInDTO struct {
+	SessionID   uint      `json:"session_id"`
+	SectionID   uint      `json:"section_id"`
+	Status      string    `json:"status"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
+
+type AttendanceOverrideInput struct {
+	StudentID uint   `json:"student_id"`
+	Status    string `json:"status"`
+}
+
+// AttendanceOverridesInput sets the status of students at a session. Status
+// is present, late, excused or absent.
+type AttendanceOverridesInput struct {
+	Records []AttendanceOverrideInput `json:"records"`
+}
+
+// SessionAttendanceDTO is a student's status at one session. Students who
+// have not checked in are listed as absent.
+type SessionAttendanceDTO struct {
+	StudentID   uint       `json:"student_id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"`
+	Source      string     `json:"source,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// AttendanceCounts summarizes closed sessions. Percentage is the share of
+// sessions attended (present or late), leaving excused sessions out; it is
+// nil until there is a session to count.
+type AttendanceCounts struct {
+	Sessions   int      `json:"sessions"`
+	Present    int      `json:"present"`
+	Late       int      `json:"late"`
+	Excused    int      `json:"excused"`
+	Absent     int      `json:"absent"`
+	Percentage *float64 `json:"percentage"`
+}
+
+type StudentAttendanceReportDTO struct {
+	StudentID uint   `json:"student_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	AttendanceCounts
+}
+
+type SectionAttendanceReportDTO struct {
+	SectionID   uint                         `json:"section_id"`
+	SectionCode string                       `json:"section_code"`
+	SubjectName string                       `json:"subject_name"`
+	TermName    string                       `json:"term_name"`
+	Students    []StudentAttendanceReportDTO `json:"students"`
+}
+
+// MyAttendanceDTO is a student's attendance in one of their sections.
+type MyAttendanceDTO struct {
+	SectionID   uint   `json:"section_id"`
+	SectionCode string `json:"section_code"`
+	SubjectName string `json:"subject_name"`
+	TermName    string `json:"term_name"`
+	AttendanceCounts
+}
@@ -1,5 +1,7 @@
 package contracts
 
+import "time"
+
 type SignupInput struct {
 	Name     string
 	Email    string
@@ -9,9 +11,47 @@ type SignupInput struct {
 type LoginInput struct {
 	Email    string
 	Password string
+	IP       string `json:"-"`
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email"`
 }
 
+type ResetPasswordInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// AuthResponse is returned by every successful authentication step. When the
+// account has two-factor authentication enabled, Login only returns MFAToken,
+// which must be exchanged at /login/mfa for the actual tokens.
 type AuthResponse struct {
-	Token  string `json:"token"`
-	UserID uint   `json:"id"`
+	Token                 string `json:"token,omitempty"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	UserID                uint   `json:"id"`
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+}
+
+type LoginAttemptDTO struct {
+	IP string    `json:"ip"`
+	At time.Time `json:"at"`
+}
+
+type LoginStatusDTO struct {
+	UserID            uint              `json:"user_id"`
+	Locked            bool              `json:"locked"`
+	RetryAfterSeconds int               `json:"retry_after_seconds"`
+	FailedAttempts    int64             `json:"failed_attempts"`
+	RecentFailures    []LoginAttemptDTO `json:"recent_failures"`
 }
@@ -0,0 +1,20 @@
+package contracts
+
+import "time"
+
+type EnrollmentInput struct {
+	SectionID uint `json:"section_id"`
+}
+
+type EnrollmentDTO struct {
+	ID          uint   `json:"id"`
+	SectionID   uint   `json:"section_id"`
+	SectionCode string `json:"section_code"`
+	SubjectName string `json:"subject_name"`
+	TermName    string `json:"term_name"`
+	Status      string `json:"status"`
+	// WaitlistPosition is the 1-based place on the waitlist, set only while
+	// the enrollment is waitlisted.
+	WaitlistPosition int64      `json:"waitlist_position,omitempty"`
+	EnrolledAt       *time.Time `json:"enrolled_at,omitempty"`
+}
@@ -1,12 +1,106 @@
 package contracts
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrEmailInUse         = errors.New("email already in use")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrRoleNotFound       = errors.New("role not found")
+	ErrRoleExists         = errors.New("role already exists")
+	ErrSystemRole         = errors.New("built-in role cannot be changed")
+	ErrRoleInUse          = errors.New("role still has users assigned")
 	ErrSubjectNotFound    = errors.New("subject not found")
+	ErrSubjectInUse       = errors.New("subject still has course sections, assignments or quizzes, or is required by another subject")
+	ErrTermNotFound       = errors.New("term not found")
+	ErrTermExists         = errors.New("term already exists")
+	ErrNoCurrentTerm      = errors.New("no term is currently in session")
+	ErrTermInUse          = errors.New("term still has course sections")
+	ErrSectionNotFound    = errors.New("course section not found")
+	ErrSectionExists      = errors.New("section code already used for this subject and term")
+	ErrSectionInUse       = errors.New("section still has enrollments")
+	ErrEnrollmentNotFound = errors.New("enrollment not found")
+	ErrAlreadyEnrolled    = errors.New("already enrolled or waitlisted in this section")
+	ErrRegistrationClosed = errors.New("registration for this term is closed")
+	ErrNotSectionTeacher  = errors.New("you do not teach this section")
+	ErrTranscriptNotFound = errors.New("no transcript was issued with this hash")
+	ErrUnprintableText    = errors.New("transcript contains characters the PDF font cannot show")
+	ErrSessionNotFound    = errors.New("class session not found")
+	ErrSessionOpen        = errors.New("section already has an open class session")
+	ErrSessionClosed      = errors.New("class session is closed")
+	ErrInvalidCheckIn     = errors.New("invalid or expired check-in code")
+	ErrNotEnrolled        = errors.New("you are not enrolled in this section")
+	ErrBuildingNotFound   = errors.New("building not found")
+	ErrBuildingExists     = errors.New("building code already exists")
+	ErrBuildingInUse      = errors.New("building still has rooms")
+	ErrRoomNotFound       = errors.New("room not found")
+	ErrRoomExists         = errors.New("room code already used in this building")
+	ErrRoomInUse          = errors.New("room is still booked by course sections")
+	ErrNotSubjectTeacher  = errors.New("you do not teach this subject")
+	ErrAssignmentNotFound = errors.New("assignment not found")
+	ErrAssignmentInUse    = errors.New("assignment already has submissions")
+	ErrComponentLinked    = errors.New("gradebook component is already fed by another assignment")
+	ErrDeadlinePassed     = errors.New("the assignment deadline has passed")
+	ErrSubmissionNotFound = errors.New("submission not found")
+	ErrFileNotFound       = errors.New("file not found")
+	ErrFileTooLarge       = errors.New("file exceeds the upload size limit")
+	ErrRubricNotFound     = errors.New("rubric not found")
+	ErrRubricInUse        = errors.New("rubric is attached to an assignment")
+	ErrRubricGraded       = errors.New("rubric has already been used for grading")
+	ErrNoSimilarityReport = errors.New("submissions have not been checked for similarity yet")
+	ErrQuizNotFound       = errors.New("quiz not found")
+	ErrQuizInUse          = errors.New("quiz already has attempts")
+	ErrQuizAttempted      = errors.New("questions cannot change once the quiz has been attempted")
+	ErrQuizNotOpen        = errors.New("quiz is not open")
+	ErrNoAttemptsLeft     = errors.New("no attempts left for this quiz")
+	ErrAttemptInProgress  = errors.New("a quiz attempt is already in progress")
+	ErrAttemptNotFound    = errors.New("quiz attempt not found")
+	ErrAttemptSubmitted   = errors.New("quiz attempt has already been submitted")
+	ErrTimeUp             = errors.New("time is up for this quiz attempt")
+	ErrAnswerNotFound     = errors.New("quiz answer not found")
 	ErrForbidden          = errors.New("forbidden")
+	ErrInvalidRefresh     = errors.New("invalid refresh token")
+	ErrRefreshReused      = errors.New("refresh token reuse detected")
+	ErrInvalidResetToken  = errors.New("invalid or expired reset token")
+	ErrInvalidVerifyToken = errors.New("invalid or expired verification token")
+	ErrEmailVerified      = errors.New("email already verified")
+	ErrInvalidMFAToken    = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrMFANotStarted      = errors.New("two-factor enrollment has not been started")
+	ErrMFARequired        = errors.New("two-factor authentication is required for your role")
 )
+
+// RateLimitError is returned when the caller has to wait before retrying.
+type RateLimitError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// EligibilityError is returned when a student has not met the prerequisites
+// of a subject.
+type EligibilityError struct {
+	Missing []MissingRequisiteDTO
+}
+
+func (e *EligibilityError) Error() string {
+	return "prerequisites not met"
+}
+
+// ScheduleConflictError is returned when a write would double-book a room,
+// a teacher or a student.
+type ScheduleConflictError struct {
+	Conflicts []ConflictDTO
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return "schedule conflict"
+}
@@ -0,0 +1,92 @@
+package contracts
+
+// ComponentInput describes one assessment component. Passing the ID of an
+// existing component keeps it, and its scores, while updating its fields.
+type ComponentInput struct {
+	ID        uint    `json:"id,omitempty"`
+	Name      string  `json:"name"`
+	Weight    float64 `json:"weight"`
+	MaxPoints float64 `json:"max_points"`
+}
+
+// ComponentsInput replaces every component of a section. Weights are percent
+// and must add up to 100.
+type ComponentsInput struct {
+	Components []ComponentInput `json:"components"`
+}
+
+type ComponentDTO struct {
+	ID        uint    `json:"id"`
+	Name      string  `json:"name"`
+	Weight    float64 `json:"weight"`
+	MaxPoints float64 `json:"max_points"`
+}
+
+// ScoreEntryInput sets a student's points in a component. A null points
+// value clears the score.
+type ScoreEntryInput struct {
+	StudentID   uint     `json:"student_id"`
+	ComponentID uint     `json:"component_id"`
+	Points      *float64 `json:"points"`
+}
+
+type BulkScoresInput struct {
+	Scores []ScoreEntryInput `json:"scores"`
+}
+
+type ComponentScoreDTO struct {
+	ComponentID uint     `json:"component_id"`
+	Points      *float64 `json:"points"`
+}
+
+// StudentGradeDTO is one row of a section's gradebook. CurrentScore is the
+// weighted mark over the graded components only; FinalScore and Letter are
+// set once every component is graded.
+type StudentGradeDTO struct {
+	StudentID    uint                `json:"student_id"`
+	Name         string              `json:"name"`
+	Email        string              `json:"email"`
+	Scores       []ComponentScoreDTO `json:"scores"`
+	CurrentScore *float64            `json:"current_score"`
+	FinalScore   *float64            `json:"final_score"`
+	Letter       string              `json:"letter,omitempty"`
+}
+
+type GradebookDTO struct {
+	SectionID   uint              `json:"section_id"`
+	SectionCode string            `json:"section_code"`
+	SubjectName string            `json:"subject_name"`
+	TermName    string            `json:"term_name"`
+	Components  []ComponentDTO    `json:"components"`
+	Students    []StudentGradeDTO `json:"students"`
+}
+
+type GradedComponentDTO struct {
+	ComponentDTO
+	Points *float64 `json:"points"`
+}
+
+// SectionGradeDTO is a student's own marks in one section.
+type SectionGradeDTO struct {
+	SectionID    uint                 `json:"section_id"`
+	SectionCode  string               `json:"section_code"`
+	SubjectName  string               `json:"subject_name"`
+	TermName     string               `json:"term_name"`
+	Components   []GradedComponentDTO `json:"components"`
+	CurrentScore *float64             `json:"current_score"`
+	FinalScore   *float64             `json:"final_score"`
+	Letter       string               `json:"letter,omitempty"`
+}
+
+type GradeBandDTO struct {
+	Letter      string  `json:"letter"`
+	MinScore    float64 `json:"min_score"`
+	GradePoints float64 `json:"grade_points"`
+}
+
+// GradeScaleInput replaces the grade scale. A final mark gets the letter of
+// the band with the highest MinScore not above it, so one band must start
+// at 0.
+type GradeScaleInput struct {
+	Bands []GradeBandDTO `json:"bands"`
+}
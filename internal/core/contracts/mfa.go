@@ -0,0 +1,28 @@
+package contracts
+
+type MFALoginInput struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+	IP           string `json:"-"`
+}
+
+type MFACodeInput struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+	IP           string `json:"-"`
+}
+
+type TOTPEnrollmentDTO struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TOTPConfirmResponse struct {
+	AuthResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,201 @@
+package contracts
+
+import "time"
+
+// QuizSettingsDTO holds when and how a quiz can be taken. OpensAt and
+// ClosesAt bound when attempts can start; an attempt ends TimeLimitMinutes
+// after it starts or when the quiz closes, whichever is first. Zero
+// TimeLimitMinutes leaves attempts untimed and zero MaxAttempts allows any
+// number of attempts.
+type QuizSettingsDTO struct {
+	OpensAt          *time.Time `json:"opens_at,omitempty"`
+	ClosesAt         *time.Time `json:"closes_at,omitempty"`
+	TimeLimitMinutes int        `json:"time_limit_minutes"`
+	MaxAttempts      int        `json:"max_attempts"`
+	ShuffleQuestions bool       `json:"shuffle_questions"`
+	ShuffleOptions   bool       `json:"shuffle_options"`
+}
+
+// QuizInput creates a quiz for one section, or for every section of a
+// subject in a term. Exactly one of SectionID and SubjectID must be set;
+// Term (an ID or "current", the default) only applies to subject-wide
+// quizzes.
+type QuizInput struct {
+	SectionID   *uint               `json:"section_id"`
+	SubjectID   *uint               `json:"subject_id"`
+	Term        string              `json:"term"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Settings    QuizSettingsDTO     `json:"settings"`
+	Questions   []QuizQuestionInput `json:"questions"`
+}
+
+// UpdateQuizInput replaces the title, description and settings of a quiz.
+// Questions are replaced separately.
+type UpdateQuizInput struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Settings    QuizSettingsDTO `json:"settings"`
+}
+
+// QuizQuestionsInput replaces the questions of a quiz nobody has attempted
+// yet. Questions and options are kept in the given order.
+type QuizQuestionsInput struct {
+	Questions []QuizQuestionInput `json:"questions"`
+}
+
+// QuizQuestionInput is one question. What else is needed depends on Type:
+//   - single_choice: Options with exactly one correct
+//   - multi_choice: Options with at least one correct
+//   - true_false: Correct, the right answer
+//   - numeric: Answer, and Tolerance for answers that are close enough
+//   - short_answer: AcceptedAnswers, or none to grade answers by hand
+type QuizQuestionInput struct {
+	Type            string            `json:"type"`
+	Prompt          string            `json:"prompt"`
+	Points          float64           `json:"points"`
+	Options         []QuizOptionInput `json:"options"`
+	Correct         *bool             `json:"correct"`
+	Answer          *float64          `json:"answer"`
+	Tolerance       float64           `json:"tolerance"`
+	AcceptedAnswers []string          `json:"accepted_answers"`
+}
+
+type QuizOptionInput struct {
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+}
+
+type QuizDTO struct {
+	ID          uint            `json:"id"`
+	SubjectID   uint            `json:"subject_id"`
+	SubjectName string          `json:"subject_name"`
+	TermID      uint            `json:"term_id"`
+	SectionID   *uint           `json:"section_id,omitempty"`
+	SectionCode string          `json:"section_code,omitempty"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Settings    QuizSettingsDTO `json:"settings"`
+	Open        bool            `json:"open"`
+	MaxPoints   float64         `json:"max_points"`
+	// Questions are only listed for teachers; students see them once they
+	// start an attempt.
+	Questions     []QuizQuestionDTO `json:"questions,omitempty"`
+	QuestionCount int               `json:"question_count"`
+	// Attempts are the caller's own attempts; only set for students.
+	Attempts     []QuizAttemptSummaryDTO `json:"attempts,omitempty"`
+	AttemptsLeft *int                    `json:"attempts_left,omitempty"`
+	// BestScore is the student's best graded attempt.
+	BestScore *float64 `json:"best_score,omitempty"`
+}
+
+// QuizQuestionDTO is a question with its answer key; the key is left out
+// when students take the quiz.
+type QuizQuestionDTO struct {
+	ID              uint            `json:"id"`
+	Type            string          `json:"type"`
+	Prompt          string          `json:"prompt"`
+	Points          float64         `json:"points"`
+	Options         []QuizOptionDTO `json:"options,omitempty"`
+	Answer          *float64        `json:"answer,omitempty"`
+	Tolerance       float64         `json:"tolerance,omitempty"`
+	AcceptedAnswers []string        `json:"accepted_answers,omitempty"`
+}
+
+type QuizOptionDTO struct {
+	ID      uint   `json:"id"`
+	Text    string `json:"text"`
+	Correct *bool  `json:"correct,omitempty"`
+}
+
+type QuizAttemptSummaryDTO struct {
+	ID            uint       `json:"id"`
+	QuizID        uint       `json:"quiz_id"`
+	StudentID     uint       `json:"student_id"`
+	StudentName   string     `json:"student_name,omitempty"`
+	Number        int        `json:"number"`
+	StartedAt     time.Time  `json:"started_at"`
+	DeadlineAt    *time.Time `json:"deadline_at,omitempty"`
+	SubmittedAt   *time.Time `json:"submitted_at,omitempty"`
+	AutoSubmitted bool       `json:"auto_submitted"`
+	// Score is set once the attempt is submitted and every answer graded.
+	Score     *float64 `json:"score,omitempty"`
+	MaxPoints float64  `json:"max_points"`
+}
+
+// QuizAttemptDTO is an attempt with its questions in the order the student
+// sees them. Points of answers are shown once the attempt is submitted.
+type QuizAttemptDTO struct {
+	QuizAttemptSummaryDTO
+	Questions []AttemptQuestionDTO `json:"questions"`
+}
+
+type AttemptQuestionDTO struct {
+	QuizQuestionDTO
+	Response AttemptAnswerDTO `json:"response"`
+}
+
+type AttemptAnswerDTO struct {
+	ID         uint       `json:"id"`
+	OptionIDs  []uint     `json:"option_ids"`
+	Number     *float64   `json:"number,omitempty"`
+	Text       string     `json:"text,omitempty"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	Points     *float64   `json:"points,omitempty"`
+}
+
+// QuizAnswersInput saves answers of an attempt in progress. Questions left
+// out keep their saved answer.
+type QuizAnswersInput struct {
+	Answers []QuizAnswerInput `json:"answers"`
+}
+
+// QuizAnswerInput answers one question: OptionIDs for choice and
+// true/false questions, Number for numeric ones and Text for short
+// answers.
+type QuizAnswerInput struct {
+	QuestionID uint     `json:"question_id"`
+	OptionIDs  []uint   `json:"option_ids"`
+	Number     *float64 `json:"number"`
+	Text       string   `json:"text"`
+}
+
+// GradeAnswerInput grades a short answer by hand.
+type GradeAnswerInput struct {
+	Points float64 `json:"points"`
+}
+
+// QuizStatisticsDTO analyses how the questions of a quiz performed, using
+// each student's first submitted attempt.
+type QuizStatisticsDTO struct {
+	QuizID      uint                    `json:"quiz_id"`
+	Students    int                     `json:"students"`
+	MeanPercent *float64                `json:"mean_percent,omitempty"`
+	Items       []QuizItemStatisticsDTO `json:"items"`
+}
+
+// QuizItemStatisticsDTO describes one question. Difficulty is the mean share
+// of the points students earned, so higher means easier. Discrimination is
+// the difficulty among the top 27% of students by total score minus that
+// among the bottom 27%; questions that strong students get right and weak
+// ones get wrong score close to 1. Both are left out until enough answers
+// are graded.
+type QuizItemStatisticsDTO struct {
+	QuestionID     uint                      `json:"question_id"`
+	Type           string                    `json:"type"`
+	Prompt         string                    `json:"prompt"`
+	Points         float64                   `json:"points"`
+	Responses      int                       `json:"responses"`
+	Ungraded       int                       `json:"ungraded"`
+	Difficulty     *float64                  `json:"difficulty,omitempty"`
+	Discrimination *float64                  `json:"discrimination,omitempty"`
+	Options        []QuizOptionStatisticsDTO `json:"options,omitempty"`
+}
+
+// QuizOptionStatisticsDTO counts how many students picked an option.
+type QuizOptionStatisticsDTO struct {
+	OptionID uint   `json:"option_id"`
+	Text     string `json:"text"`
+	Correct  bool   `json:"correct"`
+	Picked   int    `json:"picked"`
+}
@@ -0,0 +1,51 @@
+package contracts
+
+// RequisitesInput replaces every requirement of a subject. Groups are ANDed
+// and the options within a group are ORed.
+type RequisitesInput struct {
+	Groups []RequisiteGroupInput `json:"groups"`
+}
+
+type RequisiteGroupInput struct {
+	Kind    string                 `json:"kind"`
+	Options []RequisiteOptionInput `json:"options"`
+}
+
+// RequisiteOptionInput names a subject that satisfies its group. MinScore is
+// the lowest final mark in percent that counts; omit it to accept any
+// passing mark.
+type RequisiteOptionInput struct {
+	SubjectID uint     `json:"subject_id"`
+	MinScore  *float64 `json:"min_score,omitempty"`
+}
+
+type RequisitesDTO struct {
+	SubjectID uint                `json:"subject_id"`
+	Groups    []RequisiteGroupDTO `json:"groups"`
+}
+
+type RequisiteGroupDTO struct {
+	Kind    string               `json:"kind"`
+	Options []RequisiteOptionDTO `json:"options"`
+}
+
+type RequisiteOptionDTO struct {
+	SubjectID   uint     `json:"subject_id"`
+	SubjectName string   `json:"subject_name"`
+	MinScore    *float64 `json:"min_score,omitempty"`
+}
+
+// EligibilityDTO explains whether a student may take a subject. Missing lists
+// every requirement group the student has not met yet.
+type EligibilityDTO struct {
+	StudentID uint                  `json:"student_id"`
+	SubjectID uint                  `json:"subject_id"`
+	Eligible  bool                  `json:"eligible"`
+	Missing   []MissingRequisiteDTO `json:"missing"`
+}
+
+type MissingRequisiteDTO struct {
+	Kind        string               `json:"kind"`
+	Description string               `json:"description"`
+	Options     []RequisiteOptionDTO `json:"options"`
+}
@@ -0,0 +1,21 @@
+package contracts
+
+// RoleInput creates or updates a role. On update an empty Name keeps the
+// current name and omitted Permissions keep the current permission set.
+type RoleInput struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleDTO struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	System      bool     `json:"system"`
+	Permissions []string `json:"permissions"`
+	UserCount   int64    `json:"user_count"`
+}
+
+type PermissionDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,72 @@
+package contracts
+
+import "time"
+
+// RubricInput creates a rubric or replaces one that has not been used for
+// grading yet. Criteria and their levels are kept in the given order.
+type RubricInput struct {
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Criteria    []RubricCriterionInput `json:"criteria"`
+}
+
+type RubricCriterionInput struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Levels      []RubricLevelInput `json:"levels"`
+}
+
+type RubricLevelInput struct {
+	Points     float64 `json:"points"`
+	Descriptor string  `json:"descriptor"`
+}
+
+type RubricDTO struct {
+	ID          uint                 `json:"id"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	MaxPoints   float64              `json:"max_points"`
+	Criteria    []RubricCriterionDTO `json:"criteria"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type RubricCriterionDTO struct {
+	ID          uint             `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	MaxPoints   float64          `json:"max_points"`
+	Levels      []RubricLevelDTO `json:"levels"`
+}
+
+type RubricLevelDTO struct {
+	ID         uint    `json:"id"`
+	Points     float64 `json:"points"`
+	Descriptor string  `json:"descriptor"`
+}
+
+// RubricGradeInput grades a submission with its assignment's rubric. Every
+// criterion must be scored once.
+type RubricGradeInput struct {
+	Scores   []CriterionScoreInput `json:"scores"`
+	Feedback string                `json:"feedback"`
+}
+
+// CriterionScoreInput scores one criterion. Picking a level gives its
+// points unless Points adjusts them; without a level Points is required.
+type CriterionScoreInput struct {
+	CriterionID uint     `json:"criterion_id"`
+	LevelID     *uint    `json:"level_id"`
+	Points      *float64 `json:"points"`
+	Comment     string   `json:"comment"`
+}
+
+type CriterionScoreDTO struct {
+	CriterionID     uint    `json:"criterion_id"`
+	CriterionTitle  string  `json:"criterion_title"`
+	LevelID         *uint   `json:"level_id,omitempty"`
+	LevelDescriptor string  `json:"level_descriptor,omitempty"`
+	Points          float64 `json:"points"`
+	MaxPoints       float64 `json:"max_points"`
+	Comment         string  `json:"comment,omitempty"`
+}
@@ -0,0 +1,37 @@
+package contracts
+
+type SectionInput struct {
+	SubjectID    uint   `json:"subject_id"`
+	TermID       uint   `json:"term_id"`
+	Code         string `json:"code"`
+	Capacity     int    `json:"capacity"`
+	DeliveryMode string `json:"delivery_mode"`
+	TeacherIDs   []uint `json:"teacher_ids"`
+}
+
+// UpdateSectionInput replaces the mutable fields of a section. A section
+// cannot be moved to another subject or term.
+type UpdateSectionInput struct {
+	Code         string `json:"code"`
+	Capacity     int    `json:"capacity"`
+	DeliveryMode string `json:"delivery_mode"`
+	TeacherIDs   []uint `json:"teacher_ids"`
+}
+
+type TeacherDTO struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type SectionDTO struct {
+	ID           uint         `json:"id"`
+	SubjectID    uint         `json:"subject_id"`
+	SubjectName  string       `json:"subject_name"`
+	TermID       uint         `json:"term_id"`
+	TermName     string       `json:"term_name"`
+	Code         string       `json:"code"`
+	Capacity     int          `json:"capacity"`
+	DeliveryMode string       `json:"delivery_mode"`
+	Teachers     []TeacherDTO `json:"teachers"`
+}
@@ -0,0 +1,56 @@
+package contracts
+
+import "time"
+
+// SimilarityReportDTO is the latest check of an assignment's submissions
+// for copied work. Matches are ordered by score, highest first, and only
+// pairs scoring at least the configured minimum are listed.
+type SimilarityReportDTO struct {
+	AssignmentID uint                 `json:"assignment_id"`
+	Status       string               `json:"status"`
+	Error        string               `json:"error,omitempty"`
+	Submissions  int                  `json:"submissions"`
+	SkippedFiles int                  `json:"skipped_files"`
+	RequestedAt  time.Time            `json:"requested_at"`
+	StartedAt    *time.Time           `json:"started_at,omitempty"`
+	FinishedAt   *time.Time           `json:"finished_at,omitempty"`
+	Matches      []SimilarityMatchDTO `json:"matches"`
+}
+
+// SimilarityMatchDTO is a pair of similar submissions. For text the score
+// is the estimated overlap of five-word phrases; for code it is the share
+// of the smaller submission's fingerprints found in the other.
+type SimilarityMatchDTO struct {
+	ID           uint                 `json:"id"`
+	Kind         string               `json:"kind"`
+	ScorePercent float64              `json:"score_percent"`
+	SubmissionA  SimilarSubmissionDTO `json:"submission_a"`
+	SubmissionB  SimilarSubmissionDTO `json:"submission_b"`
+	Spans        []SimilaritySpanDTO  `json:"spans"`
+}
+
+// SimilarSubmissionDTO identifies one side of a match. Resubmitted is set
+// when the student handed in again after the check started, so the match
+// may be out of date.
+type SimilarSubmissionDTO struct {
+	SubmissionID uint   `json:"submission_id"`
+	StudentID    uint   `json:"student_id"`
+	StudentName  string `json:"student_name,omitempty"`
+	Resubmitted  bool   `json:"resubmitted"`
+}
+
+// SimilaritySpanDTO is a passage found in both submissions.
+type SimilaritySpanDTO struct {
+	A SpanLocationDTO `json:"a"`
+	B SpanLocationDTO `json:"b"`
+}
+
+// SpanLocationDTO locates a passage in a submitted file, in bytes of the
+// file's text; for .docx files that is the extracted text.
+type SpanLocationDTO struct {
+	FileID   uint   `json:"file_id"`
+	FileName string `json:"file_name"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Excerpt  string `json:"excerpt"`
+}
@@ -3,12 +3,18 @@ package contracts
 type SubjectInput struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	TeacherIDs  []uint `json:"teacher_ids"`
+	// CreditHours defaults to 3 on create; zero keeps the current value on
+	// update.
+	CreditHours float64 `json:"credit_hours"`
+	TeacherIDs  []uint  `json:"teacher_ids"`
+	TermIDs     []uint  `json:"term_ids"`
 }
 
 type SubjectDTO struct {
 	ID          uint     `json:"id"`
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
+	CreditHours float64  `json:"credit_hours"`
 	Teachers    []string `json:"teachers"`
+	Terms       []string `json:"terms"`
 }
@@ -0,0 +1,29 @@
+package contracts
+
+import "time"
+
+// TermInput creates or replaces an academic term. Every range includes its
+// start and excludes its end.
+type TermInput struct {
+	Name              string    `json:"name"`
+	StartDate         time.Time `json:"start_date"`
+	EndDate           time.Time `json:"end_date"`
+	RegistrationStart time.Time `json:"registration_start"`
+	RegistrationEnd   time.Time `json:"registration_end"`
+	AddDropStart      time.Time `json:"add_drop_start"`
+	AddDropEnd        time.Time `json:"add_drop_end"`
+}
+
+type TermDTO struct {
+	ID                uint      `json:"id"`
+	Name              string    `json:"name"`
+	StartDate         time.Time `json:"start_date"`
+	EndDate           time.Time `json:"end_date"`
+	RegistrationStart time.Time `json:"registration_start"`
+	RegistrationEnd   time.Time `json:"registration_end"`
+	AddDropStart      time.Time `json:"add_drop_start"`
+	AddDropEnd        time.Time `json:"add_drop_end"`
+	Current           bool      `json:"current"`
+	RegistrationOpen  bool      `json:"registration_open"`
+	AddDropOpen       bool      `json:"add_drop_open"`
+}
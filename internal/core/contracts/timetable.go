@@ -0,0 +1,88 @@
+package contracts
+
+type BuildingInput struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type BuildingDTO struct {
+	ID   uint   `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type RoomInput struct {
+	BuildingID uint   `json:"building_id"`
+	Code       string `json:"code"`
+	Capacity   int    `json:"capacity"`
+}
+
+type RoomDTO struct {
+	ID           uint   `json:"id"`
+	BuildingID   uint   `json:"building_id"`
+	BuildingCode string `json:"building_code"`
+	Code         string `json:"code"`
+	Label        string `json:"label"`
+	Capacity     int    `json:"capacity"`
+}
+
+// MeetingInput is one weekly meeting. Day is an English weekday name such as
+// "monday" or "mon"; times are "HH:MM" and End is exclusive. RoomID may only
+// be omitted for online sections.
+type MeetingInput struct {
+	Day    string `json:"day"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	RoomID *uint  `json:"room_id"`
+}
+
+// MeetingsInput replaces the weekly meeting pattern of a section.
+type MeetingsInput struct {
+	Meetings []MeetingInput `json:"meetings"`
+}
+
+type MeetingDTO struct {
+	ID     uint   `json:"id"`
+	Day    string `json:"day"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	RoomID *uint  `json:"room_id,omitempty"`
+	Room   string `json:"room,omitempty"`
+}
+
+// TimetableEntryDTO is one weekly meeting on a personal timetable.
+type TimetableEntryDTO struct {
+	SectionID    uint   `json:"section_id"`
+	SectionCode  string `json:"section_code"`
+	SubjectName  string `json:"subject_name"`
+	DeliveryMode string `json:"delivery_mode"`
+	MeetingDTO
+}
+
+type ConflictSectionDTO struct {
+	SectionID   uint   `json:"section_id"`
+	SectionCode string `json:"section_code"`
+	SubjectName string `json:"subject_name"`
+}
+
+// ConflictDTO is a clash between two sections meeting at the same time. Kind
+// is room, teacher or student and says what is double-booked; Start and End
+// bound the overlap.
+type ConflictDTO struct {
+	Kind        string               `json:"kind"`
+	Day         string               `json:"day"`
+	Start       string               `json:"start"`
+	End         string               `json:"end"`
+	Sections    []ConflictSectionDTO `json:"sections"`
+	RoomID      uint                 `json:"room_id,omitempty"`
+	Room        string               `json:"room,omitempty"`
+	TeacherID   uint                 `json:"teacher_id,omitempty"`
+	TeacherName string               `json:"teacher_name,omitempty"`
+	StudentIDs  []uint               `json:"student_ids,omitempty"`
+}
+
+type ConflictReportDTO struct {
+	TermID    uint          `json:"term_id"`
+	TermName  string        `json:"term_name"`
+	Conflicts []ConflictDTO `json:"conflicts"`
+}
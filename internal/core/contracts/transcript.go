@@ -0,0 +1,57 @@
+package contracts
+
+import "time"
+
+type TranscriptStudentDTO struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// TranscriptCourseDTO is one attempt at a subject. Counted is false for
+// attempts superseded under the retake policy; they still appear in their
+// term's GPA but not in the cumulative GPA.
+type TranscriptCourseDTO struct {
+	SubjectID   uint     `json:"subject_id"`
+	SubjectName string   `json:"subject_name"`
+	SectionCode string   `json:"section_code"`
+	CreditHours float64  `json:"credit_hours"`
+	FinalScore  *float64 `json:"final_score"`
+	Letter      string   `json:"letter,omitempty"`
+	GradePoints *float64 `json:"grade_points"`
+	InProgress  bool     `json:"in_progress"`
+	Counted     bool     `json:"counted"`
+}
+
+type TranscriptTermDTO struct {
+	TermID  uint                  `json:"term_id"`
+	Name    string                `json:"name"`
+	Courses []TranscriptCourseDTO `json:"courses"`
+	// GPA is nil until the term has a graded course.
+	GPA           *float64 `json:"gpa"`
+	CreditsEarned float64  `json:"credits_earned"`
+}
+
+type TranscriptDTO struct {
+	Student          TranscriptStudentDTO `json:"student"`
+	RetakePolicy     string               `json:"retake_policy"`
+	Terms            []TranscriptTermDTO  `json:"terms"`
+	CumulativeGPA    *float64             `json:"cumulative_gpa"`
+	CreditsAttempted float64              `json:"credits_attempted"`
+	CreditsEarned    float64              `json:"credits_earned"`
+	// VerificationHash can be checked at /verify/transcript/{hash}. It and
+	// IssuedAt are only set on transcripts issued as a PDF.
+	VerificationHash string     `json:"verification_hash,omitempty"`
+	IssuedAt         *time.Time `json:"issued_at,omitempty"`
+}
+
+// TranscriptVerificationDTO confirms that a transcript was issued. Current
+// is false when the student's record has changed since.
+type TranscriptVerificationDTO struct {
+	Hash          string    `json:"hash"`
+	StudentName   string    `json:"student_name"`
+	IssuedAt      time.Time `json:"issued_at"`
+	CumulativeGPA *float64  `json:"cumulative_gpa"`
+	CreditsEarned float64   `json:"credits_earned"`
+	Current       bool      `json:"current"`
+}
@@ -1,10 +1,11 @@
 package contracts
 
 type UserDTO struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
 }
 
 type CreateUserInput struct {
@@ -18,3 +19,22 @@ type UpdateUserInput struct {
 	Email    string `json:"email"`
 	RoleName string `json:"role"`
 }
+
+// UserListQuery holds the raw query parameters of GET /admin/users.
+type UserListQuery struct {
+	Role        string
+	Search      string
+	CreatedFrom string
+	CreatedTo   string
+	Sort        string
+	Order       string
+	Cursor      string
+	Limit       string
+}
+
+// UserListDTO is a single page of users. NextCursor is empty on the last page.
+type UserListDTO struct {
+	Items      []UserDTO `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Total      int64     `json:"total"`
+}
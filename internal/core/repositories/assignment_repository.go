@@ -0,0 +1,332 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssignmentFilter narrows assignment listings. TeacherID keeps the
+// assignments of sections or subjects the teacher teaches; StudentID keeps
+// those handed out to sections the student is enrolled in.
+type AssignmentFilter struct {
+	TermID    uint
+	TeacherID uint
+	StudentID uint
+}
+
+// AssignmentRepository exposes persistence operations for assignments and
+// their submissions.
+type AssignmentRepository interface {
+	Transaction(ctx context.Context, fn func(tx AssignmentRepository) error) error
+	// Gradebook returns a repository on the same connection, so that inside
+	// Transaction grades reach the gradebook in the same transaction.
+	Gradebook() GradebookRepository
+
+	Create(ctx context.Context, assignment *models.Assignment) error
+	FindByID(ctx context.Context, id uint) (*models.Assignment, error)
+	List(ctx context.Context, filter AssignmentFilter) ([]models.Assignment, error)
+	Save(ctx context.Context, assignment *models.Assignment) error
+	Delete(ctx context.Context, id uint) error
+	IsEnrolled(ctx context.Context, studentID uint, assignment *models.Assignment) (bool, error)
+
+	// UpsertSubmission creates the student's submission or, if there is one,
+	// bumps its version and clears its grade and rubric scores, and returns
+	// its ID.
+	UpsertSubmission(ctx context.Context, assignmentID, studentID uint, at time.Time) (uint, error)
+	ReplaceFiles(ctx context.Context, submissionID uint, files []models.SubmissionFile) error
+	FindSubmission(ctx context.Context, assignmentID, studentID uint) (*models.Submission, error)
+	ListSubmissions(ctx context.Context, assignmentID uint) ([]models.Submission, error)
+	ListStudentSubmissions(ctx context.Context, studentID uint, assignmentIDs []uint) ([]models.Submission, error)
+	FindSubmissionByID(ctx context.Context, id uint) (*models.Submission, error)
+	SaveGrade(ctx context.Context, submission *models.Submission) error
+	// ReplaceRubricScores swaps the rubric scores of a submission.
+	ReplaceRubricScores(ctx context.Context, submissionID uint, scores []models.RubricScore) error
+	// HasRubricScores reports whether any submission to the assignment has
+	// been graded with a rubric.
+	HasRubricScores(ctx context.Context, assignmentID uint) (bool, error)
+	FindFile(ctx context.Context, id uint) (*models.SubmissionFile, *models.Submission, error)
+
+	CreateExtension(ctx context.Context, extension *models.AssignmentExtension) error
+	ListExtensions(ctx context.Context, assignmentID uint) ([]models.AssignmentExtension, error)
+	// LatestExtensions returns the extension in force for every student of
+	// the given assignments, or only for one student when studentID is
+	// non-zero.
+	LatestExtensions(ctx context.Context, assignmentIDs []uint, studentID uint) ([]models.AssignmentExtension, error)
+}
+
+type assignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAssignmentRepository(db *gorm.DB) AssignmentRepository {
+	return &assignmentRepository{db: db}
+}
+
+func (r *assignmentRepository) Transaction(ctx context.Context, fn func(tx AssignmentRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&assignmentRepository{db: tx})
+	})
+}
+
+func (r *assignmentRepository) Gradebook() GradebookRepository {
+	return &gradebookRepository{db: r.db}
+}
+
+func (r *assignmentRepository) Create(ctx context.Context, assignment *models.Assignment) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(assignment).Error
+}
+
+func (r *assignmentRepository) FindByID(ctx context.Context, id uint) (*models.Assignment, error) {
+	var assignment models.Assignment
+	if err := r.preload(r.db.WithContext(ctx)).First(&assignment, id).Error; err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *assignmentRepository) List(ctx context.Context, filter AssignmentFilter) ([]models.Assignment, error) {
+	query := r.db.WithContext(ctx).Model(&models.Assignment{})
+	if filter.TermID != 0 {
+		query = query.Where("assignments.term_id = ?", filter.TermID)
+	}
+	if filter.TeacherID != 0 {
+		query = query.Where("assignments.section_id IN (?) OR assignments.subject_id IN (?)",
+			r.db.Table("section_teachers").Select("course_section_id").Where("user_id = ?", filter.TeacherID),
+			r.db.Table("subject_teachers").Select("subject_id").Where("user_id = ?", filter.TeacherID))
+	}
+	if filter.StudentID != 0 {
+		enrolled := r.db.Model(&models.Enrollment{}).
+			Joins("JOIN course_sections ON course_sections.id = enrollments.section_id").
+			Where("enrollments.student_id = ? AND enrollments.status = ?", filter.StudentID, models.EnrollmentEnrolled)
+		query = query.Where(
+			"assignments.section_id IN (?) OR (assignments.section_id IS NULL AND (assignments.subject_id, assignments.term_id) IN (?))",
+			enrolled.Session(&gorm.Session{}).Select("enrollments.section_id"),
+			enrolled.Session(&gorm.Session{}).Select("course_sections.subject_id, course_sections.term_id"))
+	}
+
+	var assignments []models.Assignment
+	if err := r.preload(query).Order("assignments.due_at, assignments.id").Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (r *assignmentRepository) Save(ctx context.Context, assignment *models.Assignment) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(assignment).Error
+}
+
+func (r *assignmentRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.Assignment{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsEnrolled reports whether the student is enrolled in the assignment's
+// section or, for subject-wide assignments, in any section of the subject in
+// the assignment's term.
+func (r *assignmentRepository) IsEnrolled(ctx context.Context, studentID uint, assignment *models.Assignment) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.Enrollment{}).
+		Joins("JOIN course_sections ON course_sections.id = enrollments.section_id").
+		Where("enrollments.student_id = ? AND enrollments.status = ?", studentID, models.EnrollmentEnrolled)
+	if assignment.SectionID != nil {
+		query = query.Where("enrollments.section_id = ?", *assignment.SectionID)
+	} else {
+		query = query.Where("course_sections.subject_id = ? AND course_sections.term_id = ?", assignment.SubjectID, assignment.TermID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *assignmentRepository) UpsertSubmission(ctx context.Context, assignmentID, studentID uint, at time.Time) (uint, error) {
+	submission := models.Submission{AssignmentID: assignmentID, StudentID: studentID, Version: 1, SubmittedAt: at}
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "assignment_id"}, {Name: "student_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"version":         gorm.Expr("submissions.version + 1"),
+			"submitted_at":    at,
+			"updated_at":      at,
+			"raw_score":       nil,
+			"days_late":       0,
+			"penalty_percent": 0,
+			"score":           nil,
+			"feedback":        "",
+			"graded_at":       nil,
+			"graded_by_id":    nil,
+		}),
+	}).Create(&submission).Error
+	if err != nil {
+		return 0, err
+	}
+	if err := r.ReplaceRubricScores(ctx, submission.ID, nil); err != nil {
+		return 0, err
+	}
+	return submission.ID, nil
+}
+
+func (r *assignmentRepository) ReplaceFiles(ctx context.Context, submissionID uint, files []models.SubmissionFile) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("submission_id = ?", submissionID).Delete(&models.SubmissionFile{}).Error; err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	for i := range files {
+		files[i].SubmissionID = submissionID
+	}
+	return db.Create(&files).Error
+}
+
+func (r *assignmentRepository) FindSubmission(ctx context.Context, assignmentID, studentID uint) (*models.Submission, error) {
+	var submission models.Submission
+	if err := r.db.WithContext(ctx).
+		Where("assignment_id = ? AND student_id = ?", assignmentID, studentID).
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("RubricScores").
+		Take(&submission).Error; err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *assignmentRepository) ListSubmissions(ctx context.Context, assignmentID uint) ([]models.Submission, error) {
+	var submissions []models.Submission
+	if err := r.db.WithContext(ctx).
+		Joins("Student").
+		Where("submissions.assignment_id = ?", assignmentID).
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("RubricScores").
+		Order(`"Student".name, submissions.id`).
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+func (r *assignmentRepository) ListStudentSubmissions(ctx context.Context, studentID uint, assignmentIDs []uint) ([]models.Submission, error) {
+	var submissions []models.Submission
+	if len(assignmentIDs) == 0 {
+		return submissions, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("student_id = ? AND assignment_id IN ?", studentID, assignmentIDs).
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("RubricScores").
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+func (r *assignmentRepository) FindSubmissionByID(ctx context.Context, id uint) (*models.Submission, error) {
+	var submission models.Submission
+	if err := r.db.WithContext(ctx).
+		Preload("Student").
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("RubricScores").
+		First(&submission, id).Error; err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *assignmentRepository) SaveGrade(ctx context.Context, submission *models.Submission) error {
+	return r.db.WithContext(ctx).Model(submission).
+		Select("raw_score", "days_late", "penalty_percent", "score", "feedback", "graded_at", "graded_by_id").
+		Updates(submission).Error
+}
+
+func (r *assignmentRepository) ReplaceRubricScores(ctx context.Context, submissionID uint, scores []models.RubricScore) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("submission_id = ?", submissionID).Delete(&models.RubricScore{}).Error; err != nil {
+		return err
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	for i := range scores {
+		scores[i].SubmissionID = submissionID
+	}
+	return db.Omit(clause.Associations).Create(&scores).Error
+}
+
+func (r *assignmentRepository) HasRubricScores(ctx context.Context, assignmentID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RubricScore{}).
+		Joins("JOIN submissions ON submissions.id = rubric_scores.submission_id").
+		Where("submissions.assignment_id = ?", assignmentID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindFile returns a submission file together with the submission it
+// belongs to.
+func (r *assignmentRepository) FindFile(ctx context.Context, id uint) (*models.SubmissionFile, *models.Submission, error) {
+	db := r.db.WithContext(ctx)
+
+	var file models.SubmissionFile
+	if err := db.First(&file, id).Error; err != nil {
+		return nil, nil, err
+	}
+	var submission models.Submission
+	if err := db.First(&submission, file.SubmissionID).Error; err != nil {
+		return nil, nil, err
+	}
+	return &file, &submission, nil
+}
+
+func (r *assignmentRepository) CreateExtension(ctx context.Context, extension *models.AssignmentExtension) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(extension).Error
+}
+
+func (r *assignmentRepository) ListExtensions(ctx context.Context, assignmentID uint) ([]models.AssignmentExtension, error) {
+	var extensions []models.AssignmentExtension
+	if err := r.db.WithContext(ctx).
+		Where("assignment_id = ?", assignmentID).
+		Preload("Student").
+		Preload("GrantedBy").
+		Order("created_at DESC, id DESC").
+		Find(&extensions).Error; err != nil {
+		return nil, err
+	}
+	return extensions, nil
+}
+
+func (r *assignmentRepository) LatestExtensions(ctx context.Context, assignmentIDs []uint, studentID uint) ([]models.AssignmentExtension, error) {
+	var extensions []models.AssignmentExtension
+	if len(assignmentIDs) == 0 {
+		return extensions, nil
+	}
+	query := r.db.WithContext(ctx).
+		Select("DISTINCT ON (assignment_id, student_id) *").
+		Where("assignment_id IN ?", assignmentIDs)
+	if studentID != 0 {
+		query = query.Where("student_id = ?", studentID)
+	}
+	if err := query.
+		Order("assignment_id, student_id, created_at DESC, id DESC").
+		Find(&extensions).Error; err != nil {
+		return nil, err
+	}
+	return extensions, nil
+}
+
+func (r *assignmentRepository) preload(db *gorm.DB) *gorm.DB {
+	db = db.Preload("Subject.Teachers").Preload("Section.Teachers").Preload("Section.Subject.Teachers")
+	return preloadRubric(db, "Rubric")
+}
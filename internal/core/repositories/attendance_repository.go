@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AttendanceRepository exposes persistence operations for class sessions
+// and attendance records.
+type AttendanceRepository interface {
+	CreateSession(ctx context.Context, session *models.ClassSession) error
+	FindSession(ctx context.Context, id uint) (*models.ClassSession, error)
+	ListSessions(ctx context.Context, sectionIDs []uint) ([]models.ClassSession, error)
+	CloseSession(ctx context.Context, id uint, at time.Time) (bool, error)
+
+	ListEnrolled(ctx context.Context, sectionID uint) ([]models.Enrollment, error)
+	ListRecords(ctx context.Context, sessionIDs []uint) ([]models.AttendanceRecord, error)
+	// CreateRecord stores the record unless the enrollment already has one
+	// for the session, and loads the stored one into record either way.
+	CreateRecord(ctx context.Context, record *models.AttendanceRecord) (bool, error)
+	UpsertRecords(ctx context.Context, records []models.AttendanceRecord) error
+}
+
+type attendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceRepository{db: db}
+}
+
+func (r *attendanceRepository) CreateSession(ctx context.Context, session *models.ClassSession) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(session).Error
+}
+
+func (r *attendanceRepository) FindSession(ctx context.Context, id uint) (*models.ClassSession, error) {
+	var session models.ClassSession
+	if err := r.db.WithContext(ctx).
+		Preload("Section.Subject.Teachers").
+		Preload("Section.Term").
+		Preload("Section.Teachers").
+		First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListSessions returns the sessions of the given sections, oldest first.
+func (r *attendanceRepository) ListSessions(ctx context.Context, sectionIDs []uint) ([]models.ClassSession, error) {
+	var sessions []models.ClassSession
+	if len(sectionIDs) == 0 {
+		return sessions, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("section_id IN ?", sectionIDs).
+		Order("started_at, id").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CloseSession closes an open session and reports whether it was open.
+func (r *attendanceRepository) CloseSession(ctx context.Context, id uint, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.ClassSession{}).
+		Where("id = ? AND closed_at IS NULL", id).
+		Update("closed_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// ListEnrolled returns the section's enrolled students' enrollments, with
+// the students preloaded, ordered by name.
+func (r *attendanceRepository) ListEnrolled(ctx context.Context, sectionID uint) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	if err := r.db.WithContext(ctx).
+		Joins("Student").
+		Where("enrollments.section_id = ? AND enrollments.status = ?", sectionID, models.EnrollmentEnrolled).
+		Order(`"Student"."name", enrollments.id`).
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+func (r *attendanceRepository) ListRecords(ctx context.Context, sessionIDs []uint) ([]models.AttendanceRecord, error) {
+	var records []models.AttendanceRecord
+	if len(sessionIDs) == 0 {
+		return records, nil
+	}
+	if err := r.db.WithContext(ctx).Where("session_id IN ?", sessionIDs).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *attendanceRepository) CreateRecord(ctx context.Context, record *models.AttendanceRecord) (bool, error) {
+	db := r.db.WithContext(ctx)
+	res := db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "session_id"}, {Name: "enrollment_id"}},
+			DoNothing: true,
+		}).
+		Create(record)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+	return false, db.Where("session_id = ? AND enrollment_id = ?", record.SessionID, record.EnrollmentID).First(record).Error
+}
+
+func (r *attendanceRepository) UpsertRecords(ctx context.Context, records []models.AttendanceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "session_id"}, {Name: "enrollment_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "source", "updated_by_id", "updated_at"}),
+		}).
+		Create(&records).Error
+}
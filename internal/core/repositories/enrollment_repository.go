@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnrollmentRepository exposes persistence operations for enrollments.
+//
+// Seat accounting must happen inside Transaction after LockSection, which
+// takes a row lock on the section so that concurrent enrollments and drops
+// for the same section are serialized. Timetable checks then lock the term
+// through Timetable, always after the section.
+type EnrollmentRepository interface {
+	Transaction(ctx context.Context, fn func(tx EnrollmentRepository) error) error
+	LockSection(ctx context.Context, sectionID uint) (*models.CourseSection, error)
+	// Timetable and Sections return repositories on the same connection,
+	// so that inside Transaction they take part in the transaction.
+	Timetable() TimetableRepository
+	Sections() SectionRepository
+
+	FindByID(ctx context.Context, id uint) (*models.Enrollment, error)
+	FindByStudentAndSection(ctx context.Context, studentID, sectionID uint) (*models.Enrollment, error)
+	ListByStudent(ctx context.Context, studentID, termID uint) ([]models.Enrollment, error)
+	CountEnrolled(ctx context.Context, sectionID uint) (int64, error)
+	NextWaitlisted(ctx context.Context, sectionID uint, limit int) ([]models.Enrollment, error)
+	WaitlistPosition(ctx context.Context, enrollment *models.Enrollment) (int64, error)
+	Save(ctx context.Context, enrollment *models.Enrollment) error
+}
+
+type enrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) EnrollmentRepository {
+	return &enrollmentRepository{db: db}
+}
+
+func (r *enrollmentRepository) Transaction(ctx context.Context, fn func(tx EnrollmentRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&enrollmentRepository{db: tx})
+	})
+}
+
+// LockSection takes a FOR NO KEY UPDATE lock, which still lets timetable
+// changes insert meetings referencing the section while they hold the term
+// lock; a FOR UPDATE lock would deadlock with them.
+func (r *enrollmentRepository) LockSection(ctx context.Context, sectionID uint) (*models.CourseSection, error) {
+	var section models.CourseSection
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Preload("Subject").
+		Preload("Term").
+		First(&section, sectionID).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+func (r *enrollmentRepository) Timetable() TimetableRepository {
+	return &timetableRepository{db: r.db}
+}
+
+func (r *enrollmentRepository) Sections() SectionRepository {
+	return &sectionRepository{db: r.db}
+}
+
+func (r *enrollmentRepository) FindByID(ctx context.Context, id uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	if err := r.db.WithContext(ctx).
+		Preload("Section.Subject").
+		Preload("Section.Term").
+		First(&enrollment, id).Error; err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *enrollmentRepository) FindByStudentAndSection(ctx context.Context, studentID, sectionID uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	if err := r.db.WithContext(ctx).
+		Where("student_id = ? AND section_id = ?", studentID, sectionID).
+		First(&enrollment).Error; err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// ListByStudent returns the student's active enrollments, optionally limited
+// to one term.
+func (r *enrollmentRepository) ListByStudent(ctx context.Context, studentID, termID uint) ([]models.Enrollment, error) {
+	query := r.db.WithContext(ctx).
+		Joins("JOIN course_sections ON course_sections.id = enrollments.section_id").
+		Where("enrollments.student_id = ? AND enrollments.status <> ?", studentID, models.EnrollmentDropped)
+	if termID != 0 {
+		query = query.Where("course_sections.term_id = ?", termID)
+	}
+
+	var enrollments []models.Enrollment
+	if err := query.
+		Preload("Section.Subject").
+		Preload("Section.Term").
+		Order("enrollments.id").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+func (r *enrollmentRepository) CountEnrolled(ctx context.Context, sectionID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Enrollment{}).
+		Where("section_id = ? AND status = ?", sectionID, models.EnrollmentEnrolled).
+		Count(&count).Error
+	return count, err
+}
+
+// NextWaitlisted returns up to limit waitlisted enrollments in waitlist order,
+// or all of them when limit is negative, with their students preloaded.
+func (r *enrollmentRepository) NextWaitlisted(ctx context.Context, sectionID uint, limit int) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	if err := r.db.WithContext(ctx).
+		Where("section_id = ? AND status = ?", sectionID, models.EnrollmentWaitlisted).
+		Order("waitlisted_at, id").
+		Limit(limit).
+		Preload("Student").
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// WaitlistPosition returns the 1-based place of a waitlisted enrollment.
+func (r *enrollmentRepository) WaitlistPosition(ctx context.Context, enrollment *models.Enrollment) (int64, error) {
+	var ahead int64
+	err := r.db.WithContext(ctx).Model(&models.Enrollment{}).
+		Where("section_id = ? AND status = ?", enrollment.SectionID, models.EnrollmentWaitlisted).
+		Where("(waitlisted_at < ? OR (waitlisted_at = ? AND id < ?))",
+			enrollment.WaitlistedAt, enrollment.WaitlistedAt, enrollment.ID).
+		Count(&ahead).Error
+	return ahead + 1, err
+}
+
+func (r *enrollmentRepository) Save(ctx context.Context, enrollment *models.Enrollment) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(enrollment).Error
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// GradeScaleRepository exposes persistence operations for the letter grade
+// scale.
+type GradeScaleRepository interface {
+	List(ctx context.Context) ([]models.GradeBand, error)
+	Replace(ctx context.Context, bands []models.GradeBand) error
+}
+
+type gradeScaleRepository struct {
+	db *gorm.DB
+}
+
+func NewGradeScaleRepository(db *gorm.DB) GradeScaleRepository {
+	return &gradeScaleRepository{db: db}
+}
+
+// List returns the bands from the highest minimum score down.
+func (r *gradeScaleRepository) List(ctx context.Context) ([]models.GradeBand, error) {
+	var bands []models.GradeBand
+	if err := r.db.WithContext(ctx).Order("min_score DESC").Find(&bands).Error; err != nil {
+		return nil, err
+	}
+	return bands, nil
+}
+
+func (r *gradeScaleRepository) Replace(ctx context.Context, bands []models.GradeBand) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.GradeBand{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&bands).Error
+	})
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GradebookRepository exposes persistence operations for assessment
+// components and scores.
+//
+// Writes to a section's gradebook happen inside Transaction after
+// LockSection so that final marks are recomputed from a consistent view.
+type GradebookRepository interface {
+	Transaction(ctx context.Context, fn func(tx GradebookRepository) error) error
+	FindSection(ctx context.Context, sectionID uint) (*models.CourseSection, error)
+	LockSection(ctx context.Context, sectionID uint) (*models.CourseSection, error)
+
+	ListComponents(ctx context.Context, sectionIDs []uint) ([]models.AssessmentComponent, error)
+	// ListFedComponents returns the IDs of the section's components that an
+	// assignment feeds.
+	ListFedComponents(ctx context.Context, sectionID uint) ([]uint, error)
+	ReplaceComponents(ctx context.Context, sectionID uint, components []models.AssessmentComponent) error
+
+	ListEnrolled(ctx context.Context, sectionID uint) ([]models.Enrollment, error)
+	ListScores(ctx context.Context, enrollmentIDs []uint) ([]models.AssessmentScore, error)
+	UpsertScore(ctx context.Context, score *models.AssessmentScore) error
+	DeleteScore(ctx context.Context, componentID, enrollmentID uint) error
+	SetFinalScore(ctx context.Context, enrollmentID uint, score *float64) error
+}
+
+type gradebookRepository struct {
+	db *gorm.DB
+}
+
+func NewGradebookRepository(db *gorm.DB) GradebookRepository {
+	return &gradebookRepository{db: db}
+}
+
+func (r *gradebookRepository) Transaction(ctx context.Context, fn func(tx GradebookRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gradebookRepository{db: tx})
+	})
+}
+
+func (r *gradebookRepository) FindSection(ctx context.Context, sectionID uint) (*models.CourseSection, error) {
+	var section models.CourseSection
+	if err := r.preloadSection(r.db.WithContext(ctx)).First(&section, sectionID).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+func (r *gradebookRepository) LockSection(ctx context.Context, sectionID uint) (*models.CourseSection, error) {
+	var section models.CourseSection
+	if err := r.preloadSection(r.db.WithContext(ctx)).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&section, sectionID).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// ListComponents returns the components of the given sections in display
+// order.
+func (r *gradebookRepository) ListComponents(ctx context.Context, sectionIDs []uint) ([]models.AssessmentComponent, error) {
+	var components []models.AssessmentComponent
+	if len(sectionIDs) == 0 {
+		return components, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("section_id IN ?", sectionIDs).
+		Order("section_id, position, id").
+		Find(&components).Error; err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+// ReplaceComponents deletes the section's components that are not in the
+// list, together with their scores, and saves the rest. Components with an
+// ID are updated in place.
+func (r *gradebookRepository) ReplaceComponents(ctx context.Context, sectionID uint, components []models.AssessmentComponent) error {
+	keep := []uint{0}
+	for _, c := range components {
+		if c.ID != 0 {
+			keep = append(keep, c.ID)
+		}
+	}
+
+	db := r.db.WithContext(ctx)
+	if err := db.Where("section_id = ? AND id NOT IN ?", sectionID, keep).
+		Delete(&models.AssessmentComponent{}).Error; err != nil {
+		return err
+	}
+	// Free the names of kept components first so that swapping two names
+	// does not trip the unique index.
+	if err := db.Model(&models.AssessmentComponent{}).
+		Where("section_id = ? AND id IN ?", sectionID, keep).
+		Update("name", gorm.Expr("'#' || id")).Error; err != nil {
+		return err
+	}
+	for i := range components {
+		components[i].SectionID = sectionID
+		if err := db.Omit(clause.Associations).Save(&components[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gradebookRepository) ListFedComponents(ctx context.Context, sectionID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&models.Assignment{}).
+		Where("section_id = ? AND gradebook_component_id IS NOT NULL", sectionID).
+		Pluck("gradebook_component_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListEnrolled returns the section's enrolled students' enrollments, with
+// the students preloaded, ordered by name.
+func (r *gradebookRepository) ListEnrolled(ctx context.Context, sectionID uint) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	if err := r.db.WithContext(ctx).
+		Joins("Student").
+		Where("enrollments.section_id = ? AND enrollments.status = ?", sectionID, models.EnrollmentEnrolled).
+		Order(`"Student"."name", enrollments.id`).
+		Find(&enrollments).Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+func (r *gradebookRepository) ListScores(ctx context.Context, enrollmentIDs []uint) ([]models.AssessmentScore, error) {
+	var scores []models.AssessmentScore
+	if len(enrollmentIDs) == 0 {
+		return scores, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("enrollment_id IN ?", enrollmentIDs).
+		Find(&scores).Error; err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+func (r *gradebookRepository) UpsertScore(ctx context.Context, score *models.AssessmentScore) error {
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "component_id"}, {Name: "enrollment_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"points", "graded_by_id", "updated_at"}),
+		}).
+		Create(score).Error
+}
+
+func (r *gradebookRepository) DeleteScore(ctx context.Context, componentID, enrollmentID uint) error {
+	return r.db.WithContext(ctx).
+		Where("component_id = ? AND enrollment_id = ?", componentID, enrollmentID).
+		Delete(&models.AssessmentScore{}).Error
+}
+
+func (r *gradebookRepository) SetFinalScore(ctx context.Context, enrollmentID uint, score *float64) error {
+	return r.db.WithContext(ctx).Model(&models.Enrollment{}).
+		Where("id = ?", enrollmentID).
+		Update("final_score", score).Error
+}
+
+func (r *gradebookRepository) preloadSection(db *gorm.DB) *gorm.DB {
+	return db.Preload("Subject.Teachers").Preload("Term").Preload("Teachers")
+}
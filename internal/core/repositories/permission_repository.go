@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// PermissionRepository exposes read access to the permission catalog.
+type PermissionRepository interface {
+	List(ctx context.Context) ([]models.Permission, error)
+	FindByNames(ctx context.Context, names []string) ([]models.Permission, error)
+}
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) List(ctx context.Context) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.WithContext(ctx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *permissionRepository) FindByNames(ctx context.Context, names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
@@ -0,0 +1,278 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuizFilter narrows quiz listings. TeacherID keeps the quizzes of sections
+// or subjects the teacher teaches; StudentID keeps those of sections the
+// student is enrolled in.
+type QuizFilter struct {
+	TermID    uint
+	TeacherID uint
+	StudentID uint
+}
+
+// QuizRepository exposes persistence operations for quizzes and attempts.
+type QuizRepository interface {
+	Transaction(ctx context.Context, fn func(tx QuizRepository) error) error
+
+	// Create stores a quiz with its questions and options.
+	Create(ctx context.Context, quiz *models.Quiz) error
+	// FindByID returns a quiz with its questions and options in order, and
+	// the teachers of its section or subject.
+	FindByID(ctx context.Context, id uint) (*models.Quiz, error)
+	List(ctx context.Context, filter QuizFilter) ([]models.Quiz, error)
+	Save(ctx context.Context, quiz *models.Quiz) error
+	// ReplaceQuestions swaps the quiz's questions for quiz.Questions. It
+	// fails with a foreign key violation once the quiz has been attempted.
+	ReplaceQuestions(ctx context.Context, quiz *models.Quiz) error
+	Delete(ctx context.Context, id uint) error
+	IsEnrolled(ctx context.Context, studentID uint, quiz *models.Quiz) (bool, error)
+
+	// CreateAttempt stores an attempt with its unanswered answers.
+	CreateAttempt(ctx context.Context, attempt *models.QuizAttempt) error
+	FindAttempt(ctx context.Context, id uint) (*models.QuizAttempt, error)
+	// LockAttempt takes a row lock on an attempt, held until the
+	// transaction ends, and returns it as FindAttempt does. Answers must
+	// only be saved after it, so that they cannot land after the attempt
+	// was submitted or graded.
+	LockAttempt(ctx context.Context, id uint) (*models.QuizAttempt, error)
+	// ListAttempts returns the attempts at a quiz, of one student unless
+	// studentID is 0, with their answers.
+	ListAttempts(ctx context.Context, quizID, studentID uint) ([]models.QuizAttempt, error)
+	ListStudentAttempts(ctx context.Context, studentID uint, quizIDs []uint) ([]models.QuizAttempt, error)
+	// ExpiredAttempts returns the attempts at a quiz, of one student unless
+	// studentID is 0, that are still open although their deadline was
+	// before the given time.
+	ExpiredAttempts(ctx context.Context, quizID, studentID uint, before time.Time) ([]models.QuizAttempt, error)
+	SaveAnswer(ctx context.Context, answer *models.QuizAnswer) error
+	// FinishAttempt marks an open attempt submitted and records its score.
+	// It reports false when the attempt had already been submitted.
+	FinishAttempt(ctx context.Context, attempt *models.QuizAttempt) (bool, error)
+	SaveScore(ctx context.Context, attemptID uint, score *float64) error
+	FindAnswer(ctx context.Context, id uint) (*models.QuizAnswer, error)
+}
+
+type quizRepository struct {
+	db *gorm.DB
+}
+
+func NewQuizRepository(db *gorm.DB) QuizRepository {
+	return &quizRepository{db: db}
+}
+
+func (r *quizRepository) Transaction(ctx context.Context, fn func(tx QuizRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&quizRepository{db: tx})
+	})
+}
+
+func (r *quizRepository) Create(ctx context.Context, quiz *models.Quiz) error {
+	return r.db.WithContext(ctx).Omit("Subject", "Term", "Section", "CreatedBy").Create(quiz).Error
+}
+
+func (r *quizRepository) FindByID(ctx context.Context, id uint) (*models.Quiz, error) {
+	var quiz models.Quiz
+	if err := r.preload(r.db.WithContext(ctx)).First(&quiz, id).Error; err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (r *quizRepository) List(ctx context.Context, filter QuizFilter) ([]models.Quiz, error) {
+	query := r.db.WithContext(ctx).Model(&models.Quiz{})
+	if filter.TermID != 0 {
+		query = query.Where("quizzes.term_id = ?", filter.TermID)
+	}
+	if filter.TeacherID != 0 {
+		query = query.Where("quizzes.section_id IN (?) OR quizzes.subject_id IN (?)",
+			r.db.Table("section_teachers").Select("course_section_id").Where("user_id = ?", filter.TeacherID),
+			r.db.Table("subject_teachers").Select("subject_id").Where("user_id = ?", filter.TeacherID))
+	}
+	if filter.StudentID != 0 {
+		enrolled := r.db.Model(&models.Enrollment{}).
+			Joins("JOIN course_sections ON course_sections.id = enrollments.section_id").
+			Where("enrollments.student_id = ? AND enrollments.status = ?", filter.StudentID, models.EnrollmentEnrolled)
+		query = query.Where(
+			"quizzes.section_id IN (?) OR (quizzes.section_id IS NULL AND (quizzes.subject_id, quizzes.term_id) IN (?))",
+			enrolled.Session(&gorm.Session{}).Select("enrollments.section_id"),
+			enrolled.Session(&gorm.Session{}).Select("course_sections.subject_id, course_sections.term_id"))
+	}
+
+	var quizzes []models.Quiz
+	if err := r.preload(query).Order("quizzes.closes_at NULLS LAST, quizzes.id").Find(&quizzes).Error; err != nil {
+		return nil, err
+	}
+	return quizzes, nil
+}
+
+func (r *quizRepository) Save(ctx context.Context, quiz *models.Quiz) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(quiz).Error
+}
+
+func (r *quizRepository) ReplaceQuestions(ctx context.Context, quiz *models.Quiz) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("quiz_id = ?", quiz.ID).Delete(&models.QuizQuestion{}).Error; err != nil {
+			return err
+		}
+		for i := range quiz.Questions {
+			quiz.Questions[i].ID = 0
+			quiz.Questions[i].QuizID = quiz.ID
+			for j := range quiz.Questions[i].Options {
+				quiz.Questions[i].Options[j].ID = 0
+			}
+		}
+		if len(quiz.Questions) == 0 {
+			return nil
+		}
+		return tx.Create(&quiz.Questions).Error
+	})
+}
+
+func (r *quizRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.Quiz{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsEnrolled reports whether the student is enrolled in the quiz's section
+// or, for subject-wide quizzes, in any section of the subject in the quiz's
+// term.
+func (r *quizRepository) IsEnrolled(ctx context.Context, studentID uint, quiz *models.Quiz) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.Enrollment{}).
+		Joins("JOIN course_sections ON course_sections.id = enrollments.section_id").
+		Where("enrollments.student_id = ? AND enrollments.status = ?", studentID, models.EnrollmentEnrolled)
+	if quiz.SectionID != nil {
+		query = query.Where("enrollments.section_id = ?", *quiz.SectionID)
+	} else {
+		query = query.Where("course_sections.subject_id = ? AND course_sections.term_id = ?", quiz.SubjectID, quiz.TermID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *quizRepository) CreateAttempt(ctx context.Context, attempt *models.QuizAttempt) error {
+	return r.db.WithContext(ctx).Omit("Quiz", "Student").Create(attempt).Error
+}
+
+func (r *quizRepository) FindAttempt(ctx context.Context, id uint) (*models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+	if err := r.db.WithContext(ctx).
+		Preload("Student").
+		Preload("Answers").
+		First(&attempt, id).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *quizRepository) LockAttempt(ctx context.Context, id uint) (*models.QuizAttempt, error) {
+	var locked models.QuizAttempt
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&locked, id).Error; err != nil {
+		return nil, err
+	}
+	return r.FindAttempt(ctx, id)
+}
+
+func (r *quizRepository) ListAttempts(ctx context.Context, quizID, studentID uint) ([]models.QuizAttempt, error) {
+	query := r.db.WithContext(ctx).
+		Joins("Student").
+		Where("quiz_attempts.quiz_id = ?", quizID)
+	if studentID != 0 {
+		query = query.Where("quiz_attempts.student_id = ?", studentID)
+	}
+
+	var attempts []models.QuizAttempt
+	if err := query.
+		Preload("Answers").
+		Order(`"Student".name, quiz_attempts.student_id, quiz_attempts.number`).
+		Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *quizRepository) ListStudentAttempts(ctx context.Context, studentID uint, quizIDs []uint) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	if len(quizIDs) == 0 {
+		return attempts, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("student_id = ? AND quiz_id IN ?", studentID, quizIDs).
+		Preload("Answers").
+		Order("quiz_id, number").
+		Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *quizRepository) ExpiredAttempts(ctx context.Context, quizID, studentID uint, before time.Time) ([]models.QuizAttempt, error) {
+	query := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND submitted_at IS NULL AND deadline_at < ?", quizID, before)
+	if studentID != 0 {
+		query = query.Where("student_id = ?", studentID)
+	}
+
+	var attempts []models.QuizAttempt
+	if err := query.Preload("Answers").Order("id").Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *quizRepository) SaveAnswer(ctx context.Context, answer *models.QuizAnswer) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(answer).Error
+}
+
+func (r *quizRepository) FinishAttempt(ctx context.Context, attempt *models.QuizAttempt) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.QuizAttempt{}).
+		Where("id = ? AND submitted_at IS NULL", attempt.ID).
+		Updates(map[string]any{
+			"submitted_at":   attempt.SubmittedAt,
+			"auto_submitted": attempt.AutoSubmitted,
+			"score":          attempt.Score,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *quizRepository) SaveScore(ctx context.Context, attemptID uint, score *float64) error {
+	return r.db.WithContext(ctx).Model(&models.QuizAttempt{}).
+		Where("id = ?", attemptID).
+		Update("score", score).Error
+}
+
+func (r *quizRepository) FindAnswer(ctx context.Context, id uint) (*models.QuizAnswer, error) {
+	var answer models.QuizAnswer
+	if err := r.db.WithContext(ctx).First(&answer, id).Error; err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *quizRepository) preload(db *gorm.DB) *gorm.DB {
+	ordered := func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }
+	return db.Preload("Subject.Teachers").Preload("Section.Teachers").Preload("Section.Subject.Teachers").
+		Preload("Questions", ordered).Preload("Questions.Options", ordered)
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository exposes persistence operations for 2FA recovery codes.
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID uint, hashes []string) error
+	Consume(ctx context.Context, userID uint, hash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID uint) error
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// Replace discards the user's existing codes and stores the given hashes.
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID uint, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]models.RecoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: h})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Consume marks a matching unused code as used and reports whether one was found.
+func (r *recoveryCodeRepository) Consume(ctx context.Context, userID uint, hash string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *recoveryCodeRepository) DeleteForUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+}
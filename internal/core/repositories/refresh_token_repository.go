@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository exposes persistence operations for refresh tokens.
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	Revoke(ctx context.Context, id uint, replacedByID *uint) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID uint) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Revoke marks a single token as revoked. It reports false when the token was
+// already revoked, which lets callers detect concurrent reuse.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id uint, replacedByID *uint) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"replaced_by_id": replacedByID,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).
+		Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
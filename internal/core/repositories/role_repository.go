@@ -5,10 +5,20 @@ import (
 
 	"github.com/arman300s/uni-portal/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RoleRepository interface {
 	FindByName(ctx context.Context, name string) (*models.Role, error)
+	FindByID(ctx context.Context, id uint) (*models.Role, error)
+	List(ctx context.Context) ([]models.Role, error)
+	Create(ctx context.Context, role *models.Role) error
+	Save(ctx context.Context, role *models.Role) error
+	Delete(ctx context.Context, id uint) error
+	DeleteAndReassign(ctx context.Context, id, targetID uint) ([]uint, error)
+	CountUsers(ctx context.Context) (map[uint]int64, error)
+	CountUsersForRole(ctx context.Context, id uint) (int64, error)
+	ReplacePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error
 }
 
 type roleRepository struct {
@@ -26,3 +36,89 @@ func (r *roleRepository) FindByName(ctx context.Context, name string) (*models.R
 	}
 	return &role, nil
 }
+
+func (r *roleRepository) FindByID(ctx context.Context, id uint) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *models.Role) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+func (r *roleRepository) Save(ctx context.Context, role *models.Role) error {
+	return r.db.WithContext(ctx).Omit("Permissions").Save(role).Error
+}
+
+func (r *roleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Select("Permissions").Delete(&models.Role{ID: id}).Error
+}
+
+func (r *roleRepository) ReplacePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error {
+	return r.db.WithContext(ctx).Model(role).Association("Permissions").Replace(permissions)
+}
+
+// DeleteAndReassign moves every user of the role to targetID and deletes the
+// role in a single transaction. The role row is locked first, so no user can
+// be assigned to it in between. It returns the IDs of the moved users.
+func (r *roleRepository) DeleteAndReassign(ctx context.Context, id, targetID uint) ([]uint, error) {
+	var moved []models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Role{}, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&moved).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("role_id = ?", id).
+			Update("role_id", targetID).Error; err != nil {
+			return err
+		}
+		return tx.Select("Permissions").Delete(&models.Role{ID: id}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(moved))
+	for i, u := range moved {
+		ids[i] = u.ID
+	}
+	return ids, nil
+}
+
+func (r *roleRepository) CountUsers(ctx context.Context) (map[uint]int64, error) {
+	var rows []struct {
+		RoleID uint
+		Count  int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Select("role_id, COUNT(*) AS count").
+		Where("role_id IS NOT NULL").
+		Group("role_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.RoleID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *roleRepository) CountUsersForRole(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role_id = ?", id).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoomRepository exposes persistence operations for buildings and rooms.
+type RoomRepository interface {
+	CreateBuilding(ctx context.Context, building *models.Building) error
+	FindBuilding(ctx context.Context, id uint) (*models.Building, error)
+	ListBuildings(ctx context.Context) ([]models.Building, error)
+	SaveBuilding(ctx context.Context, building *models.Building) error
+	DeleteBuilding(ctx context.Context, id uint) error
+
+	CreateRoom(ctx context.Context, room *models.Room) error
+	FindRoom(ctx context.Context, id uint) (*models.Room, error)
+	FindRooms(ctx context.Context, ids []uint) ([]models.Room, error)
+	ListRooms(ctx context.Context, buildingID uint) ([]models.Room, error)
+	SaveRoom(ctx context.Context, room *models.Room) error
+	DeleteRoom(ctx context.Context, id uint) error
+}
+
+type roomRepository struct {
+	db *gorm.DB
+}
+
+func NewRoomRepository(db *gorm.DB) RoomRepository {
+	return &roomRepository{db: db}
+}
+
+func (r *roomRepository) CreateBuilding(ctx context.Context, building *models.Building) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(building).Error
+}
+
+func (r *roomRepository) FindBuilding(ctx context.Context, id uint) (*models.Building, error) {
+	var building models.Building
+	if err := r.db.WithContext(ctx).First(&building, id).Error; err != nil {
+		return nil, err
+	}
+	return &building, nil
+}
+
+func (r *roomRepository) ListBuildings(ctx context.Context) ([]models.Building, error) {
+	var buildings []models.Building
+	if err := r.db.WithContext(ctx).Order("code").Find(&buildings).Error; err != nil {
+		return nil, err
+	}
+	return buildings, nil
+}
+
+func (r *roomRepository) SaveBuilding(ctx context.Context, building *models.Building) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(building).Error
+}
+
+func (r *roomRepository) DeleteBuilding(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.Building{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *roomRepository) CreateRoom(ctx context.Context, room *models.Room) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(room).Error
+}
+
+func (r *roomRepository) FindRoom(ctx context.Context, id uint) (*models.Room, error) {
+	var room models.Room
+	if err := r.db.WithContext(ctx).Preload("Building").First(&room, id).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (r *roomRepository) FindRooms(ctx context.Context, ids []uint) ([]models.Room, error) {
+	var rooms []models.Room
+	if len(ids) == 0 {
+		return rooms, nil
+	}
+	if err := r.db.WithContext(ctx).Preload("Building").Where("id IN ?", ids).Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+// ListRooms lists rooms, optionally only those of one building.
+func (r *roomRepository) ListRooms(ctx context.Context, buildingID uint) ([]models.Room, error) {
+	query := r.db.WithContext(ctx).Preload("Building").
+		Joins("JOIN buildings ON buildings.id = rooms.building_id")
+	if buildingID != 0 {
+		query = query.Where("rooms.building_id = ?", buildingID)
+	}
+
+	var rooms []models.Room
+	if err := query.Order("buildings.code, rooms.code").Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func (r *roomRepository) SaveRoom(ctx context.Context, room *models.Room) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(room).Error
+}
+
+func (r *roomRepository) DeleteRoom(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.Room{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RubricRepository exposes persistence operations for rubrics. Rubrics are
+// always loaded with their criteria and levels, in order.
+type RubricRepository interface {
+	Create(ctx context.Context, rubric *models.Rubric) error
+	FindByID(ctx context.Context, id uint) (*models.Rubric, error)
+	ListByOwner(ctx context.Context, ownerID uint) ([]models.Rubric, error)
+	// Replace saves the rubric's fields and swaps its criteria for the
+	// given ones. It fails with gorm.ErrForeignKeyViolated when a criterion
+	// has been scored.
+	Replace(ctx context.Context, rubric *models.Rubric) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type rubricRepository struct {
+	db *gorm.DB
+}
+
+func NewRubricRepository(db *gorm.DB) RubricRepository {
+	return &rubricRepository{db: db}
+}
+
+func (r *rubricRepository) Create(ctx context.Context, rubric *models.Rubric) error {
+	return r.db.WithContext(ctx).Omit("Owner").Create(rubric).Error
+}
+
+func (r *rubricRepository) FindByID(ctx context.Context, id uint) (*models.Rubric, error) {
+	var rubric models.Rubric
+	if err := preloadRubric(r.db.WithContext(ctx), "").First(&rubric, id).Error; err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+func (r *rubricRepository) ListByOwner(ctx context.Context, ownerID uint) ([]models.Rubric, error) {
+	var rubrics []models.Rubric
+	if err := preloadRubric(r.db.WithContext(ctx), "").
+		Where("owner_id = ?", ownerID).
+		Order("title, id").
+		Find(&rubrics).Error; err != nil {
+		return nil, err
+	}
+	return rubrics, nil
+}
+
+func (r *rubricRepository) Replace(ctx context.Context, rubric *models.Rubric) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(rubric).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("rubric_id = ?", rubric.ID).Delete(&models.RubricCriterion{}).Error; err != nil {
+			return err
+		}
+		for i := range rubric.Criteria {
+			rubric.Criteria[i].ID = 0
+			rubric.Criteria[i].RubricID = rubric.ID
+			for j := range rubric.Criteria[i].Levels {
+				rubric.Criteria[i].Levels[j].ID = 0
+			}
+		}
+		if len(rubric.Criteria) == 0 {
+			return nil
+		}
+		return tx.Create(&rubric.Criteria).Error
+	})
+}
+
+func (r *rubricRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.Rubric{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// preloadRubric preloads the criteria and levels, in order, of the rubric
+// found at path, such as "Rubric", or of the queried rubrics when path is
+// empty.
+func preloadRubric(db *gorm.DB, path string) *gorm.DB {
+	prefix := ""
+	if path != "" {
+		prefix = path + "."
+	}
+	ordered := func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }
+	return db.Preload(prefix+"Criteria", ordered).Preload(prefix+"Criteria.Levels", ordered)
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// SectionRepository exposes persistence operations for course sections.
+type SectionRepository interface {
+	Create(ctx context.Context, section *models.CourseSection) error
+	FindByID(ctx context.Context, id uint) (*models.CourseSection, error)
+	List(ctx context.Context, filter SectionFilter) ([]models.CourseSection, error)
+	Save(ctx context.Context, section *models.CourseSection) error
+	Delete(ctx context.Context, id uint) error
+	ReplaceTeachers(ctx context.Context, section *models.CourseSection, teachers []models.User) error
+}
+
+// SectionFilter narrows section listings. Zero values match everything.
+type SectionFilter struct {
+	TermID    uint
+	SubjectID uint
+	TeacherID uint
+}
+
+type sectionRepository struct {
+	db *gorm.DB
+}
+
+func NewSectionRepository(db *gorm.DB) SectionRepository {
+	return &sectionRepository{db: db}
+}
+
+func (r *sectionRepository) Create(ctx context.Context, section *models.CourseSection) error {
+	return r.db.WithContext(ctx).Create(section).Error
+}
+
+func (r *sectionRepository) FindByID(ctx context.Context, id uint) (*models.CourseSection, error) {
+	var section models.CourseSection
+	if err := r.preload(r.db.WithContext(ctx)).First(&section, id).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+func (r *sectionRepository) List(ctx context.Context, filter SectionFilter) ([]models.CourseSection, error) {
+	query := r.db.WithContext(ctx).Model(&models.CourseSection{})
+	if filter.TermID != 0 {
+		query = query.Where("course_sections.term_id = ?", filter.TermID)
+	}
+	if filter.SubjectID != 0 {
+		query = query.Where("course_sections.subject_id = ?", filter.SubjectID)
+	}
+	if filter.TeacherID != 0 {
+		query = query.Where("course_sections.id IN (?)",
+			r.db.Table("section_teachers").Select("course_section_id").Where("user_id = ?", filter.TeacherID))
+	}
+
+	var sections []models.CourseSection
+	if err := r.preload(query).Order("course_sections.subject_id, course_sections.code").Find(&sections).Error; err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+func (r *sectionRepository) Save(ctx context.Context, section *models.CourseSection) error {
+	return r.db.WithContext(ctx).Omit("Subject", "Term", "Teachers", "Meetings").Save(section).Error
+}
+
+func (r *sectionRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Select("Teachers").Delete(&models.CourseSection{ID: id})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *sectionRepository) ReplaceTeachers(ctx context.Context, section *models.CourseSection, teachers []models.User) error {
+	return r.db.WithContext(ctx).Model(section).Association("Teachers").Replace(teachers)
+}
+
+func (r *sectionRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Subject.Teachers").Preload("Term").Preload("Teachers")
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SimilarityRepository exposes persistence operations for similarity
+// reports. An assignment has at most one report.
+type SimilarityRepository interface {
+	Transaction(ctx context.Context, fn func(tx SimilarityRepository) error) error
+	// SaveReport creates the assignment's report or, if there is one,
+	// updates the given columns, and sets the report's ID.
+	SaveReport(ctx context.Context, report *models.SimilarityReport, columns ...string) error
+	ReplaceMatches(ctx context.Context, reportID uint, matches []models.SimilarityMatch) error
+	// FindReport returns the assignment's report with its matches, highest
+	// score first, and their submissions, students, spans and files.
+	FindReport(ctx context.Context, assignmentID uint) (*models.SimilarityReport, error)
+}
+
+type similarityRepository struct {
+	db *gorm.DB
+}
+
+func NewSimilarityRepository(db *gorm.DB) SimilarityRepository {
+	return &similarityRepository{db: db}
+}
+
+func (r *similarityRepository) Transaction(ctx context.Context, fn func(tx SimilarityRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&similarityRepository{db: tx})
+	})
+}
+
+func (r *similarityRepository) SaveReport(ctx context.Context, report *models.SimilarityReport, columns ...string) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "assignment_id"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, "updated_at")),
+	}).Create(report).Error
+}
+
+func (r *similarityRepository) ReplaceMatches(ctx context.Context, reportID uint, matches []models.SimilarityMatch) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("report_id = ?", reportID).Delete(&models.SimilarityMatch{}).Error; err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	for i := range matches {
+		matches[i].ReportID = reportID
+	}
+	return db.Omit("SubmissionA", "SubmissionB").CreateInBatches(&matches, 100).Error
+}
+
+func (r *similarityRepository) FindReport(ctx context.Context, assignmentID uint) (*models.SimilarityReport, error) {
+	var report models.SimilarityReport
+	if err := r.db.WithContext(ctx).
+		Where("assignment_id = ?", assignmentID).
+		Preload("Matches", func(db *gorm.DB) *gorm.DB { return db.Order("score DESC, id") }).
+		Preload("Matches.SubmissionA.Student").
+		Preload("Matches.SubmissionB.Student").
+		Preload("Matches.Spans", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Matches.Spans.FileA").
+		Preload("Matches.Spans.FileB").
+		Take(&report).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -10,12 +10,42 @@ import (
 // SubjectRepository exposes persistence operations for subjects.
 type SubjectRepository interface {
 	Create(ctx context.Context, subject *models.Subject) error
-	List(ctx context.Context) ([]models.Subject, error)
+	List(ctx context.Context, filter SubjectFilter) ([]models.Subject, error)
 	FindByID(ctx context.Context, id uint) (*models.Subject, error)
 	Save(ctx context.Context, subject *models.Subject) error
 	Delete(ctx context.Context, id uint) error
 	ReplaceTeachers(ctx context.Context, subject *models.Subject, teachers []models.User) error
-	ListByTeacherID(ctx context.Context, teacherID uint) ([]models.Subject, error)
+	ReplaceTerms(ctx context.Context, subject *models.Subject, terms []models.Term) error
+	FindByIDs(ctx context.Context, ids []uint) ([]models.Subject, error)
+	ListRequisites(ctx context.Context, subjectID uint) ([]models.RequisiteGroup, error)
+	ReplaceRequisites(ctx context.Context, subjectID uint, groups []models.RequisiteGroup, check func(edges []RequisiteEdge) error) error
+}
+
+// RequisiteEdge is one "subject requires required subject" edge of the
+// requisite graph.
+type RequisiteEdge struct {
+	SubjectID         uint
+	RequiredSubjectID uint
+	Kind              string
+}
+
+// requisiteGraphLock is the advisory lock that serializes edits of the
+// requisite graph, so that two concurrent edits cannot together form a cycle
+// that neither would form alone.
+const requisiteGraphLock = 0x5245_5153
+
+// SubjectFilter narrows subject listings. Zero values match everything.
+type SubjectFilter struct {
+	// TermID keeps only subjects offered in the term.
+	TermID uint
+}
+
+func (f SubjectFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.TermID != 0 {
+		db = db.Where("subjects.id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Table("subject_terms").Select("subject_id").Where("term_id = ?", f.TermID))
+	}
+	return db
 }
 
 type subjectRepository struct {
@@ -30,9 +60,9 @@ func (r *subjectRepository) Create(ctx context.Context, subject *models.Subject)
 	return r.db.WithContext(ctx).Create(subject).Error
 }
 
-func (r *subjectRepository) List(ctx context.Context) ([]models.Subject, error) {
+func (r *subjectRepository) List(ctx context.Context, filter SubjectFilter) ([]models.Subject, error) {
 	var subjects []models.Subject
-	if err := r.db.WithContext(ctx).Preload("Teachers").Find(&subjects).Error; err != nil {
+	if err := filter.apply(r.db.WithContext(ctx)).Preload("Teachers").Preload("Terms").Find(&subjects).Error; err != nil {
 		return nil, err
 	}
 	return subjects, nil
@@ -40,7 +70,7 @@ func (r *subjectRepository) List(ctx context.Context) ([]models.Subject, error)
 
 func (r *subjectRepository) FindByID(ctx context.Context, id uint) (*models.Subject, error) {
 	var subject models.Subject
-	if err := r.db.WithContext(ctx).Preload("Teachers").First(&subject, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Teachers").Preload("Terms").First(&subject, id).Error; err != nil {
 		return nil, err
 	}
 	return &subject, nil
@@ -50,22 +80,80 @@ func (r *subjectRepository) Save(ctx context.Context, subject *models.Subject) e
 	return r.db.WithContext(ctx).Save(subject).Error
 }
 
+// Delete removes the subject for good instead of soft-deleting it, so that
+// the foreign keys of rows still referencing it are enforced.
 func (r *subjectRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Subject{}, id).Error
+	result := r.db.WithContext(ctx).Unscoped().Delete(&models.Subject{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *subjectRepository) ReplaceTeachers(ctx context.Context, subject *models.Subject, teachers []models.User) error {
 	return r.db.WithContext(ctx).Model(subject).Association("Teachers").Replace(teachers)
 }
 
-func (r *subjectRepository) ListByTeacherID(ctx context.Context, teacherID uint) ([]models.Subject, error) {
+func (r *subjectRepository) ReplaceTerms(ctx context.Context, subject *models.Subject, terms []models.Term) error {
+	return r.db.WithContext(ctx).Model(subject).Association("Terms").Replace(terms)
+}
+
+func (r *subjectRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.Subject, error) {
 	var subjects []models.Subject
-	if err := r.db.WithContext(ctx).
-		Preload("Teachers").
-		Joins("JOIN subject_teachers st ON st.subject_id = subjects.id").
-		Where("st.user_id = ?", teacherID).
-		Find(&subjects).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&subjects).Error; err != nil {
 		return nil, err
 	}
 	return subjects, nil
 }
+
+func (r *subjectRepository) ListRequisites(ctx context.Context, subjectID uint) ([]models.RequisiteGroup, error) {
+	var groups []models.RequisiteGroup
+	if err := r.db.WithContext(ctx).
+		Where("subject_id = ?", subjectID).
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Options.RequiredSubject").
+		Order("id").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ReplaceRequisites swaps the requirement groups of a subject. check receives
+// the whole graph as it would look after the change and can veto it; it runs
+// under a lock so that the graph cannot change in between.
+func (r *subjectRepository) ReplaceRequisites(ctx context.Context, subjectID uint, groups []models.RequisiteGroup, check func(edges []RequisiteEdge) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", requisiteGraphLock).Error; err != nil {
+			return err
+		}
+
+		var edges []RequisiteEdge
+		if err := tx.Table("requisite_options o").
+			Select("g.subject_id, o.required_subject_id, g.kind").
+			Joins("JOIN requisite_groups g ON g.id = o.group_id").
+			Where("g.subject_id <> ?", subjectID).
+			Scan(&edges).Error; err != nil {
+			return err
+		}
+		for _, g := range groups {
+			for _, o := range g.Options {
+				edges = append(edges, RequisiteEdge{SubjectID: subjectID, RequiredSubjectID: o.RequiredSubjectID, Kind: g.Kind})
+			}
+		}
+		if err := check(edges); err != nil {
+			return err
+		}
+
+		if err := tx.Where("subject_id = ?", subjectID).Delete(&models.RequisiteGroup{}).Error; err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return nil
+		}
+		return tx.Create(&groups).Error
+	})
+}
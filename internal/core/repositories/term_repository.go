@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// TermRepository exposes persistence operations for academic terms.
+type TermRepository interface {
+	Create(ctx context.Context, term *models.Term) error
+	FindByID(ctx context.Context, id uint) (*models.Term, error)
+	FindByName(ctx context.Context, name string) (*models.Term, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]models.Term, error)
+	FindCurrent(ctx context.Context, at time.Time) (*models.Term, error)
+	List(ctx context.Context) ([]models.Term, error)
+	Save(ctx context.Context, term *models.Term) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type termRepository struct {
+	db *gorm.DB
+}
+
+func NewTermRepository(db *gorm.DB) TermRepository {
+	return &termRepository{db: db}
+}
+
+func (r *termRepository) Create(ctx context.Context, term *models.Term) error {
+	return r.db.WithContext(ctx).Create(term).Error
+}
+
+func (r *termRepository) FindByID(ctx context.Context, id uint) (*models.Term, error) {
+	var term models.Term
+	if err := r.db.WithContext(ctx).First(&term, id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (r *termRepository) FindByName(ctx context.Context, name string) (*models.Term, error) {
+	var term models.Term
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&term).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (r *termRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.Term, error) {
+	var terms []models.Term
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&terms).Error; err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+// FindCurrent returns the term in session at the given time. When terms
+// overlap, the one that started last wins.
+func (r *termRepository) FindCurrent(ctx context.Context, at time.Time) (*models.Term, error) {
+	var term models.Term
+	if err := r.db.WithContext(ctx).
+		Where("start_date <= ? AND end_date > ?", at, at).
+		Order("start_date DESC").
+		First(&term).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (r *termRepository) List(ctx context.Context) ([]models.Term, error) {
+	var terms []models.Term
+	if err := r.db.WithContext(ctx).Order("start_date DESC").Find(&terms).Error; err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *termRepository) Save(ctx context.Context, term *models.Term) error {
+	return r.db.WithContext(ctx).Save(term).Error
+}
+
+func (r *termRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.Term{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
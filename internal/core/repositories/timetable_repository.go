@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// timetableLock namespaces the per-term advisory locks taken while a term's
+// timetable is changed.
+const timetableLock = 0x5449_4d45
+
+// TermSchedule is everything needed to find clashes within a term: every
+// meeting, with its section's subject and teachers and its room preloaded,
+// and the students enrolled in each section.
+type TermSchedule struct {
+	Meetings []models.SectionMeeting
+	Students map[uint][]uint
+}
+
+// TimetableRepository exposes persistence operations for section meetings.
+//
+// Changes that must not double-book anything happen inside Transaction
+// after LockTerm, which serializes timetable changes within a term.
+type TimetableRepository interface {
+	Transaction(ctx context.Context, fn func(tx TimetableRepository) error) error
+	LockTerm(ctx context.Context, termID uint) error
+
+	LoadTerm(ctx context.Context, termID uint) (*TermSchedule, error)
+	ListMeetings(ctx context.Context, sectionIDs []uint) ([]models.SectionMeeting, error)
+	ReplaceMeetings(ctx context.Context, sectionID uint, meetings []models.SectionMeeting) error
+}
+
+type timetableRepository struct {
+	db *gorm.DB
+}
+
+func NewTimetableRepository(db *gorm.DB) TimetableRepository {
+	return &timetableRepository{db: db}
+}
+
+func (r *timetableRepository) Transaction(ctx context.Context, fn func(tx TimetableRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&timetableRepository{db: tx})
+	})
+}
+
+func (r *timetableRepository) LockTerm(ctx context.Context, termID uint) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(?, ?)", timetableLock, termID).Error
+}
+
+func (r *timetableRepository) LoadTerm(ctx context.Context, termID uint) (*TermSchedule, error) {
+	db := r.db.WithContext(ctx)
+	schedule := &TermSchedule{Students: map[uint][]uint{}}
+
+	if err := db.
+		Joins("JOIN course_sections ON course_sections.id = section_meetings.section_id").
+		Where("course_sections.term_id = ?", termID).
+		Preload("Section.Subject").
+		Preload("Section.Teachers").
+		Preload("Room.Building").
+		Order("section_meetings.weekday, section_meetings.start_minute, section_meetings.id").
+		Find(&schedule.Meetings).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		SectionID uint
+		StudentID uint
+	}
+	if err := db.Model(&models.Enrollment{}).
+		Select("enrollments.section_id, enrollments.student_id").
+		Joins("JOIN course_sections ON course_sections.id = enrollments.section_id").
+		Where("course_sections.term_id = ? AND enrollments.status = ?", termID, models.EnrollmentEnrolled).
+		Order("enrollments.student_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		schedule.Students[row.SectionID] = append(schedule.Students[row.SectionID], row.StudentID)
+	}
+	return schedule, nil
+}
+
+// ListMeetings returns the meetings of the given sections in weekly order,
+// with their rooms preloaded.
+func (r *timetableRepository) ListMeetings(ctx context.Context, sectionIDs []uint) ([]models.SectionMeeting, error) {
+	var meetings []models.SectionMeeting
+	if len(sectionIDs) == 0 {
+		return meetings, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("section_id IN ?", sectionIDs).
+		Preload("Room.Building").
+		Order("weekday, start_minute, id").
+		Find(&meetings).Error; err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+func (r *timetableRepository) ReplaceMeetings(ctx context.Context, sectionID uint, meetings []models.SectionMeeting) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("section_id = ?", sectionID).Delete(&models.SectionMeeting{}).Error; err != nil {
+		return err
+	}
+	if len(meetings) == 0 {
+		return nil
+	}
+	for i := range meetings {
+		meetings[i].SectionID = sectionID
+	}
+	return db.Omit(clause.Associations).Create(&meetings).Error
+}
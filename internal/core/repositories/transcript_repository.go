@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TranscriptRepository exposes persistence operations for issued
+// transcripts.
+type TranscriptRepository interface {
+	// Record stores the record unless one with the same hash exists, and
+	// loads the stored one into record either way.
+	Record(ctx context.Context, record *models.TranscriptRecord) error
+	FindByHash(ctx context.Context, hash string) (*models.TranscriptRecord, error)
+}
+
+type transcriptRepository struct {
+	db *gorm.DB
+}
+
+func NewTranscriptRepository(db *gorm.DB) TranscriptRepository {
+	return &transcriptRepository{db: db}
+}
+
+func (r *transcriptRepository) Record(ctx context.Context, record *models.TranscriptRecord) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "hash"}}, DoNothing: true}).
+		Create(record).Error; err != nil {
+		return err
+	}
+	return db.Where("hash = ?", record.Hash).First(record).Error
+}
+
+func (r *transcriptRepository) FindByHash(ctx context.Context, hash string) (*models.TranscriptRecord, error) {
+	var record models.TranscriptRecord
+	if err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
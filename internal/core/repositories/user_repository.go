@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/arman300s/uni-portal/internal/models"
 	"gorm.io/gorm"
@@ -12,10 +14,42 @@ type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindByID(ctx context.Context, id uint) (*models.User, error)
-	List(ctx context.Context) ([]models.User, error)
+	ListPage(ctx context.Context, opts UserListOptions) ([]models.User, int64, error)
 	Save(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id uint) error
 	FindByIDs(ctx context.Context, ids []uint) ([]models.User, error)
+	TokenVersion(ctx context.Context, id uint) (int64, error)
+	// BumpTokenVersion raises the user's token version past both its
+	// current value and atLeast, and returns the new version.
+	BumpTokenVersion(ctx context.Context, id uint, atLeast int64) (int64, error)
+}
+
+// UserSortColumns maps the sortable field names accepted by the API to
+// columns of the users table.
+var UserSortColumns = map[string]string{
+	"id":         "users.id",
+	"name":       "users.name",
+	"email":      "users.email",
+	"created_at": "users.created_at",
+}
+
+// UserListOptions filters and pages ListPage. SortField must be a key of
+// UserSortColumns; rows are ordered by it and then by id so that the
+// (After, AfterID) keyset is unique.
+type UserListOptions struct {
+	RoleName      string
+	Search        string
+	CreatedFrom   *time.Time
+	CreatedBefore *time.Time
+
+	SortField string
+	Desc      bool
+	Limit     int
+
+	// After and AfterID identify the last row of the previous page. After
+	// holds that row's SortField value and is ignored when sorting by id.
+	After   any
+	AfterID uint
 }
 
 type userRepository struct {
@@ -46,12 +80,55 @@ func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, e
 	return &user, nil
 }
 
-func (r *userRepository) List(ctx context.Context) ([]models.User, error) {
+// ListPage returns one page of users matching opts together with the number
+// of users matching the filters across all pages.
+func (r *userRepository) ListPage(ctx context.Context, opts UserListOptions) ([]models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{})
+	if opts.RoleName != "" {
+		query = query.Where("users.role_id IN (?)", r.db.Model(&models.Role{}).Select("id").Where("name = ?", opts.RoleName))
+	}
+	if opts.Search != "" {
+		pattern := "%" + escapeLike(opts.Search) + "%"
+		query = query.Where("(users.name ILIKE ? OR users.email ILIKE ?)", pattern, pattern)
+	}
+	if opts.CreatedFrom != nil {
+		query = query.Where("users.created_at >= ?", *opts.CreatedFrom)
+	}
+	if opts.CreatedBefore != nil {
+		query = query.Where("users.created_at < ?", *opts.CreatedBefore)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := UserSortColumns[opts.SortField]
+	if !ok {
+		column = UserSortColumns["id"]
+	}
+	cmp, dir := ">", "ASC"
+	if opts.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if opts.AfterID != 0 {
+		if column == "users.id" {
+			query = query.Where("users.id "+cmp+" ?", opts.AfterID)
+		} else {
+			query = query.Where("("+column+" "+cmp+" ? OR ("+column+" = ? AND users.id "+cmp+" ?))",
+				opts.After, opts.After, opts.AfterID)
+		}
+	}
+	if column != "users.id" {
+		query = query.Order(column + " " + dir)
+	}
+
 	var users []models.User
-	if err := r.db.WithContext(ctx).Preload("Role").Find(&users).Error; err != nil {
-		return nil, err
+	if err := query.Order("users.id " + dir).Limit(opts.Limit).Preload("Role").Find(&users).Error; err != nil {
+		return nil, 0, err
 	}
-	return users, nil
+	return users, total, nil
 }
 
 func (r *userRepository) Save(ctx context.Context, user *models.User) error {
@@ -64,8 +141,34 @@ func (r *userRepository) Delete(ctx context.Context, id uint) error {
 
 func (r *userRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
 	var users []models.User
-	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Preload("Role").Find(&users).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Preload("Role.Permissions").Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
 }
+
+func (r *userRepository) TokenVersion(ctx context.Context, id uint) (int64, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Select("token_version").First(&user, id).Error; err != nil {
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
+
+func (r *userRepository) BumpTokenVersion(ctx context.Context, id uint, atLeast int64) (int64, error) {
+	var versions []int64
+	if err := r.db.WithContext(ctx).
+		Raw("UPDATE users SET token_version = GREATEST(token_version, ?) + 1 WHERE id = ? RETURNING token_version", atLeast, id).
+		Scan(&versions).Error; err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return versions[0], nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserTokenRepository exposes persistence operations for one-time user tokens.
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *models.UserToken) error
+	FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error)
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+	InvalidateForUser(ctx context.Context, userID uint, purpose string) error
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *userTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error) {
+	var token models.UserToken
+	if err := r.db.WithContext(ctx).
+		Where("purpose = ? AND token_hash = ?", purpose, hash).
+		First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes the token. It reports false when the token had already
+// been used.
+func (r *userTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *userTokenRepository) InvalidateForUser(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
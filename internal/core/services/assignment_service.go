@@ -0,0 +1,1082 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/storage"
+)
+
+const (
+	maxAssignmentTitleLength = 200
+	maxAssignmentPoints      = 10000
+	maxAllowedTypesLength    = 255
+	maxFileNameLength        = 255
+	maxGraceMinutes          = 7 * 24 * 60
+	maxExtensionReasonLength = 500
+
+	defaultSubmissionFileMB = 10
+	defaultSubmissionFiles  = 5
+)
+
+// SubmissionLimits returns the largest file a student may upload, in bytes,
+// and how many files one submission may have. They are configured with
+// SUBMISSION_MAX_FILE_MB and SUBMISSION_MAX_FILES.
+func SubmissionLimits() (maxFileSize int64, maxFiles int) {
+	return int64(envInt("SUBMISSION_MAX_FILE_MB", defaultSubmissionFileMB)) << 20,
+		envInt("SUBMISSION_MAX_FILES", defaultSubmissionFiles)
+}
+
+// AssignmentService hands out assignments, collects file submissions and
+// grades them. Files are stored content-addressed in a BlobStore, so a file
+// uploaded many times, by one student or several, is stored once. Released
+// scores are copied into the gradebook.
+type AssignmentService struct {
+	assignments repositories.AssignmentRepository
+	sections    repositories.SectionRepository
+	subjects    repositories.SubjectRepository
+	terms       repositories.TermRepository
+	blobs       storage.BlobStore
+	rubrics     repositories.RubricRepository
+	gradebook   *GradebookService
+}
+
+func NewAssignmentService(
+	assignments repositories.AssignmentRepository,
+	sections repositories.SectionRepository,
+	subjects repositories.SubjectRepository,
+	terms repositories.TermRepository,
+	blobs storage.BlobStore,
+	rubrics repositories.RubricRepository,
+	gradebook *GradebookService,
+) *AssignmentService {
+	return &AssignmentService{
+		assignments: assignments,
+		sections:    sections,
+		subjects:    subjects,
+		terms:       terms,
+		blobs:       blobs,
+		rubrics:     rubrics,
+		gradebook:   gradebook,
+	}
+}
+
+// ListTeacherAssignments lists the assignments of the sections and subjects
+// a teacher teaches, optionally limited to a term (see resolveTermParam).
+func (s *AssignmentService) ListTeacherAssignments(ctx context.Context, teacherID uint, term string) ([]contracts.AssignmentDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.assignments.List(ctx, repositories.AssignmentFilter{TermID: termID, TeacherID: teacherID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dtos := make([]contracts.AssignmentDTO, 0, len(assignments))
+	for i := range assignments {
+		dtos = append(dtos, *mapToAssignmentDTO(&assignments[i], nil, now))
+	}
+	return dtos, nil
+}
+
+func (s *AssignmentService) GetTeacherAssignment(ctx context.Context, teacherID, id uint) (*contracts.AssignmentDTO, error) {
+	assignment, err := teacherAssignment(ctx, s.assignments, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapToAssignmentDTO(assignment, nil, time.Now()), nil
+}
+
+// CreateAssignment hands out an assignment to a section the teacher
+// teaches, or to every section of a subject the teacher teaches.
+func (s *AssignmentService) CreateAssignment(ctx context.Context, teacherID uint, input contracts.AssignmentInput) (*contracts.AssignmentDTO, error) {
+	allowed, errs := normalizeFileTypes(input.AllowedTypes)
+	input.Title = strings.TrimSpace(input.Title)
+	errs = append(errs, validateAssignmentFields(input.Title, input.DueAt, input.MaxPoints)...)
+	errs = append(errs, validateLatePolicy(input.LatePolicy, input.DueAt)...)
+	if (input.SectionID == nil) == (input.SubjectID == nil) {
+		errs = append(errs, contracts.ValidationError{Field: "section_id", Message: "exactly one of section_id and subject_id is required"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	assignment := &models.Assignment{
+		Title:        input.Title,
+		Description:  strings.TrimSpace(input.Description),
+		DueAt:        input.DueAt,
+		MaxPoints:    input.MaxPoints,
+		AllowedTypes: allowed,
+		CreatedByID:  &teacherID,
+	}
+	applyLatePolicy(assignment, input.LatePolicy)
+
+	if input.SectionID != nil {
+		section, err := findSection(ctx, s.sections, *input.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		if !teachesSection(section, teacherID) {
+			return nil, contracts.ErrNotSectionTeacher
+		}
+		assignment.SectionID = &section.ID
+		assignment.SubjectID = section.SubjectID
+		assignment.TermID = section.TermID
+	} else {
+		subject, err := s.subjects.FindByID(ctx, *input.SubjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, contracts.ErrSubjectNotFound
+			}
+			return nil, err
+		}
+		if !hasTeacher(subject.Teachers, teacherID) {
+			return nil, contracts.ErrNotSubjectTeacher
+		}
+		term := input.Term
+		if term == "" {
+			term = "current"
+		}
+		termID, err := resolveTermParam(ctx, s.terms, term)
+		if err != nil {
+			return nil, err
+		}
+		assignment.SubjectID = subject.ID
+		assignment.TermID = termID
+	}
+	if err := s.applyGrading(ctx, teacherID, assignment, input.Grading); err != nil {
+		return nil, err
+	}
+
+	if err := s.assignments.Create(ctx, assignment); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrComponentLinked
+		}
+		return nil, err
+	}
+	scheduleSimilarityCheck(assignment)
+	return s.GetTeacherAssignment(ctx, teacherID, assignment.ID)
+}
+
+// UpdateAssignment replaces the editable fields of an assignment. Moving the
+// deadline or changing the late policy reopens or closes it for
+// resubmissions; grades already recorded keep their penalty. The rubric
+// cannot change once a submission has been graded with it.
+func (s *AssignmentService) UpdateAssignment(ctx context.Context, teacherID, id uint, input contracts.UpdateAssignmentInput) (*contracts.AssignmentDTO, error) {
+	assignment, err := teacherAssignment(ctx, s.assignments, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, errs := normalizeFileTypes(input.AllowedTypes)
+	input.Title = strings.TrimSpace(input.Title)
+	errs = append(errs, validateAssignmentFields(input.Title, input.DueAt, input.MaxPoints)...)
+	errs = append(errs, validateLatePolicy(input.LatePolicy, input.DueAt)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	closesAt := assignment.ClosesAt(nil)
+	assignment.Title = input.Title
+	assignment.Description = strings.TrimSpace(input.Description)
+	assignment.DueAt = input.DueAt
+	assignment.MaxPoints = input.MaxPoints
+	assignment.AllowedTypes = allowed
+	applyLatePolicy(assignment, input.LatePolicy)
+	if err := s.applyGrading(ctx, teacherID, assignment, input.Grading); err != nil {
+		return nil, err
+	}
+	if err := s.assignments.Transaction(ctx, func(tx repositories.AssignmentRepository) error {
+		if err := tx.Save(ctx, assignment); err != nil {
+			return err
+		}
+		return s.syncGradebook(ctx, tx, teacherID, assignment, nil)
+	}); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrComponentLinked
+		}
+		return nil, err
+	}
+	if !assignment.ClosesAt(nil).Equal(closesAt) {
+		scheduleSimilarityCheck(assignment)
+	}
+	return mapToAssignmentDTO(assignment, nil, time.Now()), nil
+}
+
+// ReleaseGrades shows the grades of an assignment to its students, now and
+// whenever a submission is graded later, and copies the scores into the
+// assignment's gradebook component. Releasing again copies them again,
+// replacing the whole component.
+func (s *AssignmentService) ReleaseGrades(ctx context.Context, teacherID, id uint) (*contracts.AssignmentDTO, error) {
+	assignment, err := teacherAssignment(ctx, s.assignments, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.assignments.Transaction(ctx, func(tx repositories.AssignmentRepository) error {
+		if assignment.GradesReleasedAt == nil {
+			now := time.Now()
+			assignment.GradesReleasedAt = &now
+			if err := tx.Save(ctx, assignment); err != nil {
+				return err
+			}
+		}
+		return s.syncGradebook(ctx, tx, teacherID, assignment, nil)
+	}); err != nil {
+		return nil, err
+	}
+	return mapToAssignmentDTO(assignment, nil, time.Now()), nil
+}
+
+// DeleteAssignment removes an assignment nobody has submitted to yet.
+func (s *AssignmentService) DeleteAssignment(ctx context.Context, teacherID, id uint) error {
+	if _, err := teacherAssignment(ctx, s.assignments, teacherID, id); err != nil {
+		return err
+	}
+	if err := s.assignments.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrAssignmentNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrAssignmentInUse
+		}
+		return err
+	}
+	return nil
+}
+
+// ListSubmissions returns every submission to an assignment of the teacher.
+func (s *AssignmentService) ListSubmissions(ctx context.Context, teacherID, id uint) ([]contracts.SubmissionDTO, error) {
+	assignment, err := teacherAssignment(ctx, s.assignments, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.assignments.ListSubmissions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	extensions, err := s.assignments.LatestExtensions(ctx, []uint{id}, 0)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint]*models.AssignmentExtension, len(extensions))
+	for i := range extensions {
+		byStudent[extensions[i].StudentID] = &extensions[i]
+	}
+
+	dtos := make([]contracts.SubmissionDTO, 0, len(submissions))
+	for i := range submissions {
+		dtos = append(dtos, *mapToSubmissionDTO(&submissions[i], assignment, byStudent[submissions[i].StudentID], true))
+	}
+	return dtos, nil
+}
+
+// GradeSubmission records the raw score of a submission. The late penalty
+// is worked out now, from when the work was submitted and the student's
+// extension at this moment, and kept with the grade. Assignments with a
+// rubric are graded with GradeWithRubric instead.
+func (s *AssignmentService) GradeSubmission(ctx context.Context, teacherID, submissionID uint, input contracts.GradeSubmissionInput) (*contracts.SubmissionDTO, error) {
+	submission, assignment, err := s.teacherSubmission(ctx, teacherID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.RubricID != nil {
+		return nil, contracts.ValidationErrors{{Field: "score", Message: "this assignment is graded with its rubric"}}
+	}
+	if math.IsNaN(input.Score) || input.Score < 0 || input.Score > assignment.MaxPoints {
+		return nil, contracts.ValidationErrors{{Field: "score", Message: fmt.Sprintf("score must be between 0 and %g", assignment.MaxPoints)}}
+	}
+	return s.recordGrade(ctx, teacherID, submission, assignment, input.Score, input.Feedback, nil)
+}
+
+// GradeWithRubric scores every criterion of the assignment's rubric. The
+// total is scaled from the rubric's max points to the assignment's and
+// recorded as the raw score, so the late penalty applies as usual.
+func (s *AssignmentService) GradeWithRubric(ctx context.Context, teacherID, submissionID uint, input contracts.RubricGradeInput) (*contracts.SubmissionDTO, error) {
+	submission, assignment, err := s.teacherSubmission(ctx, teacherID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.Rubric == nil {
+		return nil, contracts.ValidationErrors{{Field: "scores", Message: "this assignment has no rubric"}}
+	}
+	scores, total, errs := scoreRubric(assignment.Rubric, input)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	raw := total / assignment.Rubric.MaxPoints() * assignment.MaxPoints
+	return s.recordGrade(ctx, teacherID, submission, assignment, raw, input.Feedback, scores)
+}
+
+// GrantExtension moves one student's due date, for example as an
+// accommodation. Every grant is kept with the teacher who made it; the
+// latest one applies.
+func (s *AssignmentService) GrantExtension(ctx context.Context, teacherID, assignmentID uint, input contracts.ExtensionInput) (*contracts.ExtensionDTO, error) {
+	assignment, err := teacherAssignment(ctx, s.assignments, teacherID, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Reason = strings.TrimSpace(input.Reason)
+	var errs contracts.ValidationErrors
+	if input.StudentID == 0 {
+		errs = append(errs, contracts.ValidationError{Field: "student_id", Message: "student_id is required"})
+	}
+	if !input.DueAt.After(assignment.DueAt) {
+		errs = append(errs, contracts.ValidationError{Field: "due_at", Message: "due_at must be after the assignment's due date"})
+	}
+	if len(input.Reason) > maxExtensionReasonLength {
+		errs = append(errs, contracts.ValidationError{Field: "reason", Message: "reason is too long"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	enrolled, err := s.assignments.IsEnrolled(ctx, input.StudentID, assignment)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, contracts.ValidationErrors{{Field: "student_id", Message: "student is not enrolled in the assignment's section"}}
+	}
+
+	extension := &models.AssignmentExtension{
+		AssignmentID: assignment.ID,
+		StudentID:    input.StudentID,
+		DueAt:        input.DueAt,
+		Reason:       input.Reason,
+		GrantedByID:  &teacherID,
+	}
+	if err := s.assignments.CreateExtension(ctx, extension); err != nil {
+		return nil, err
+	}
+
+	extensions, err := s.assignments.ListExtensions(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range extensions {
+		if extensions[i].ID == extension.ID {
+			return mapToExtensionDTO(&extensions[i]), nil
+		}
+	}
+	return mapToExtensionDTO(extension), nil
+}
+
+// ListExtensions returns every extension granted for an assignment, newest
+// first.
+func (s *AssignmentService) ListExtensions(ctx context.Context, teacherID, assignmentID uint) ([]contracts.ExtensionDTO, error) {
+	if _, err := teacherAssignment(ctx, s.assignments, teacherID, assignmentID); err != nil {
+		return nil, err
+	}
+	extensions, err := s.assignments.ListExtensions(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.ExtensionDTO, 0, len(extensions))
+	for i := range extensions {
+		dtos = append(dtos, *mapToExtensionDTO(&extensions[i]))
+	}
+	return dtos, nil
+}
+
+// TeacherFile opens a submitted file of an assignment the teacher manages.
+func (s *AssignmentService) TeacherFile(ctx context.Context, teacherID, fileID uint) (*contracts.FileContent, error) {
+	file, submission, err := s.findFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := teacherAssignment(ctx, s.assignments, teacherID, submission.AssignmentID); err != nil {
+		if errors.Is(err, contracts.ErrNotSectionTeacher) || errors.Is(err, contracts.ErrNotSubjectTeacher) {
+			return nil, contracts.ErrFileNotFound
+		}
+		return nil, err
+	}
+	return s.openFile(ctx, file)
+}
+
+// ListStudentAssignments lists the assignments of the sections a student is
+// enrolled in for a term, the current one by default, each with the
+// student's own submission. Between terms the list is empty.
+func (s *AssignmentService) ListStudentAssignments(ctx context.Context, studentID uint, term string) ([]contracts.AssignmentDTO, error) {
+	if term == "" {
+		term = "current"
+	}
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		if errors.Is(err, contracts.ErrNoCurrentTerm) {
+			return []contracts.AssignmentDTO{}, nil
+		}
+		return nil, err
+	}
+
+	assignments, err := s.assignments.List(ctx, repositories.AssignmentFilter{TermID: termID, StudentID: studentID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(assignments))
+	for _, a := range assignments {
+		ids = append(ids, a.ID)
+	}
+	submissions, err := s.assignments.ListStudentSubmissions(ctx, studentID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byAssignment := make(map[uint]*models.Submission, len(submissions))
+	for i := range submissions {
+		byAssignment[submissions[i].AssignmentID] = &submissions[i]
+	}
+	extensions, err := s.assignments.LatestExtensions(ctx, ids, studentID)
+	if err != nil {
+		return nil, err
+	}
+	extended := make(map[uint]*models.AssignmentExtension, len(extensions))
+	for i := range extensions {
+		extended[extensions[i].AssignmentID] = &extensions[i]
+	}
+
+	now := time.Now()
+	dtos := make([]contracts.AssignmentDTO, 0, len(assignments))
+	for i := range assignments {
+		a := &assignments[i]
+		dto := mapToAssignmentDTO(a, extended[a.ID], now)
+		if sub := byAssignment[a.ID]; sub != nil {
+			dto.Submission = mapToSubmissionDTO(sub, a, extended[a.ID], a.GradesReleasedAt != nil)
+		}
+		dtos = append(dtos, *dto)
+	}
+	return dtos, nil
+}
+
+func (s *AssignmentService) GetStudentAssignment(ctx context.Context, studentID, id uint) (*contracts.AssignmentDTO, error) {
+	assignment, err := s.studentAssignment(ctx, studentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	extension, err := s.latestExtension(ctx, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := mapToAssignmentDTO(assignment, extension, time.Now())
+	submission, err := s.assignments.FindSubmission(ctx, id, studentID)
+	switch {
+	case err == nil:
+		dto.Submission = mapToSubmissionDTO(submission, assignment, extension, assignment.GradesReleasedAt != nil)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	return dto, nil
+}
+
+// Submit hands in files for an assignment, replacing the files and grade of
+// any earlier submission. It is allowed until the assignment closes for the
+// student (see models.Assignment). Each file must have one of the
+// assignment's allowed extensions and fit the upload limit.
+func (s *AssignmentService) Submit(ctx context.Context, studentID, id uint, uploads []contracts.UploadedFile) (*contracts.SubmissionDTO, error) {
+	assignment, err := s.studentAssignment(ctx, studentID, id)
+	if err != nil {
+		return nil, err
+	}
+	extension, err := s.latestExtension(ctx, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+	closesAt := assignment.ClosesAt(extension)
+	if !time.Now().Before(closesAt) {
+		return nil, contracts.ErrDeadlinePassed
+	}
+
+	maxSize, maxFiles := SubmissionLimits()
+	if errs := validateUploads(assignment, uploads, maxFiles); len(errs) > 0 {
+		return nil, errs
+	}
+
+	files := make([]models.SubmissionFile, 0, len(uploads))
+	for _, upload := range uploads {
+		file, err := s.storeUpload(ctx, upload, maxSize)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, *file)
+	}
+
+	err = s.assignments.Transaction(ctx, func(tx repositories.AssignmentRepository) error {
+		now := time.Now()
+		if !now.Before(closesAt) {
+			return contracts.ErrDeadlinePassed
+		}
+		submissionID, err := tx.UpsertSubmission(ctx, assignment.ID, studentID, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.ReplaceFiles(ctx, submissionID, files); err != nil {
+			return err
+		}
+		// The grade of the replaced submission is gone, and so is the
+		// score copied from it.
+		if !feedsGradebook(assignment) {
+			return nil
+		}
+		return s.gradebook.ClearAssignmentScore(ctx, tx.Gradebook(), *assignment.SectionID, *assignment.GradebookComponentID, studentID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := s.assignments.FindSubmission(ctx, assignment.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToSubmissionDTO(submission, assignment, extension, false), nil
+}
+
+// StudentFile opens a file of the student's own submission.
+func (s *AssignmentService) StudentFile(ctx context.Context, studentID, fileID uint) (*contracts.FileContent, error) {
+	file, submission, err := s.findFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if submission.StudentID != studentID {
+		return nil, contracts.ErrFileNotFound
+	}
+	return s.openFile(ctx, file)
+}
+
+// storeUpload checksums an uploaded file and stores it unless a file with
+// the same content is already stored. Files are read in full before they
+// are stored, so the size limit holds whatever the client declared.
+func (s *AssignmentService) storeUpload(ctx context.Context, upload contracts.UploadedFile, maxSize int64) (*models.SubmissionFile, error) {
+	if upload.Size > maxSize {
+		return nil, contracts.ErrFileTooLarge
+	}
+
+	f, err := upload.Open()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		f.Close()
+		return nil, err
+	}
+	head = head[:n]
+	hash.Write(head)
+	rest, err := io.Copy(hash, io.LimitReader(f, maxSize-int64(n)+1))
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	size := int64(n) + rest
+	if size > maxSize {
+		return nil, contracts.ErrFileTooLarge
+	}
+
+	file := &models.SubmissionFile{
+		Name:        cleanFileName(upload.Name),
+		ContentType: http.DetectContentType(head),
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}
+
+	key := blobKey(file.Checksum)
+	exists, err := s.blobs.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return file, nil
+	}
+
+	f, err = upload.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := s.blobs.Put(ctx, key, io.LimitReader(f, size), size, file.ContentType); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *AssignmentService) findFile(ctx context.Context, fileID uint) (*models.SubmissionFile, *models.Submission, error) {
+	file, submission, err := s.assignments.FindFile(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, contracts.ErrFileNotFound
+		}
+		return nil, nil, err
+	}
+	return file, submission, nil
+}
+
+func (s *AssignmentService) openFile(ctx context.Context, file *models.SubmissionFile) (*contracts.FileContent, error) {
+	body, err := s.blobs.Get(ctx, blobKey(file.Checksum))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, contracts.ErrFileNotFound
+		}
+		return nil, err
+	}
+	return &contracts.FileContent{Name: file.Name, ContentType: file.ContentType, Size: file.Size, Body: body}, nil
+}
+
+// recordGrade saves a grade of raw points, before the late penalty, with
+// the rubric scores it was made of, and copies it into the gradebook once
+// grades are released.
+func (s *AssignmentService) recordGrade(ctx context.Context, teacherID uint, submission *models.Submission, assignment *models.Assignment, raw float64, feedback string, rubricScores []models.RubricScore) (*contracts.SubmissionDTO, error) {
+	extension, err := s.latestExtension(ctx, assignment.ID, submission.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	raw = roundScore(raw)
+	submission.RawScore = &raw
+	submission.DaysLate = daysLate(assignment, extension, submission.SubmittedAt)
+	submission.PenaltyPercent = latePenaltyPercent(assignment, submission.DaysLate)
+	score := roundScore(raw * (100 - submission.PenaltyPercent) / 100)
+	submission.Score = &score
+	submission.Feedback = strings.TrimSpace(feedback)
+	submission.GradedAt = &now
+	submission.GradedByID = &teacherID
+	submission.RubricScores = rubricScores
+
+	err = s.assignments.Transaction(ctx, func(tx repositories.AssignmentRepository) error {
+		if err := tx.SaveGrade(ctx, submission); err != nil {
+			return err
+		}
+		if err := tx.ReplaceRubricScores(ctx, submission.ID, submission.RubricScores); err != nil {
+			return err
+		}
+		return s.syncGradebook(ctx, tx, teacherID, assignment, []models.Submission{*submission})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapToSubmissionDTO(submission, assignment, extension, true), nil
+}
+
+// applyGrading attaches the rubric and gradebook component of an
+// assignment. Teachers can only attach their own rubrics, and only
+// components of the assignment's section.
+func (s *AssignmentService) applyGrading(ctx context.Context, teacherID uint, assignment *models.Assignment, grading contracts.GradingInput) error {
+	if !sameID(assignment.RubricID, grading.RubricID) {
+		if assignment.ID != 0 {
+			graded, err := s.assignments.HasRubricScores(ctx, assignment.ID)
+			if err != nil {
+				return err
+			}
+			if graded {
+				return contracts.ErrRubricGraded
+			}
+		}
+
+		assignment.Rubric = nil
+		if grading.RubricID != nil {
+			rubric, err := s.rubrics.FindByID(ctx, *grading.RubricID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err != nil || rubric.OwnerID != teacherID {
+				return contracts.ValidationErrors{{Field: "grading.rubric_id", Message: "rubric not found"}}
+			}
+			assignment.Rubric = rubric
+		}
+		assignment.RubricID = grading.RubricID
+	}
+
+	if grading.GradebookComponentID != nil && !sameID(assignment.GradebookComponentID, grading.GradebookComponentID) {
+		if assignment.SectionID == nil {
+			return contracts.ValidationErrors{{Field: "grading.gradebook_component_id", Message: "only assignments of one section can feed a gradebook component"}}
+		}
+		components, err := s.gradebook.ListComponents(ctx, teacherID, *assignment.SectionID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, c := range components {
+			found = found || c.ID == *grading.GradebookComponentID
+		}
+		if !found {
+			return contracts.ValidationErrors{{Field: "grading.gradebook_component_id", Message: "component does not belong to the assignment's section"}}
+		}
+	}
+	assignment.GradebookComponentID = grading.GradebookComponentID
+	return nil
+}
+
+// syncGradebook copies the scores of the given submissions into the
+// assignment's gradebook component, within the transaction tx. When
+// submissions is nil the component is replaced with the scores of every
+// submission, so students without a graded submission have no score in it.
+// It does nothing until grades are released.
+func (s *AssignmentService) syncGradebook(ctx context.Context, tx repositories.AssignmentRepository, teacherID uint, assignment *models.Assignment, submissions []models.Submission) error {
+	if !feedsGradebook(assignment) {
+		return nil
+	}
+	replace := submissions == nil
+	if replace {
+		var err error
+		if submissions, err = tx.ListSubmissions(ctx, assignment.ID); err != nil {
+			return err
+		}
+	}
+
+	shares := make(map[uint]float64, len(submissions))
+	for _, sub := range submissions {
+		if sub.Score != nil {
+			shares[sub.StudentID] = *sub.Score / assignment.MaxPoints
+		}
+	}
+	if len(shares) == 0 && !replace {
+		return nil
+	}
+	return s.gradebook.RecordAssignmentScores(ctx, tx.Gradebook(), teacherID, *assignment.SectionID, *assignment.GradebookComponentID, shares, replace)
+}
+
+// feedsGradebook reports whether the assignment's released grades are
+// copied into a gradebook component.
+func feedsGradebook(assignment *models.Assignment) bool {
+	return assignment.GradesReleasedAt != nil && assignment.GradebookComponentID != nil && assignment.SectionID != nil
+}
+
+// teacherSubmission loads a submission to an assignment the teacher
+// manages, together with the assignment.
+func (s *AssignmentService) teacherSubmission(ctx context.Context, teacherID, submissionID uint) (*models.Submission, *models.Assignment, error) {
+	submission, err := s.assignments.FindSubmissionByID(ctx, submissionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, contracts.ErrSubmissionNotFound
+		}
+		return nil, nil, err
+	}
+	assignment, err := teacherAssignment(ctx, s.assignments, teacherID, submission.AssignmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return submission, assignment, nil
+}
+
+// latestExtension returns the student's extension in force, or nil.
+func (s *AssignmentService) latestExtension(ctx context.Context, assignmentID, studentID uint) (*models.AssignmentExtension, error) {
+	extensions, err := s.assignments.LatestExtensions(ctx, []uint{assignmentID}, studentID)
+	if err != nil || len(extensions) == 0 {
+		return nil, err
+	}
+	return &extensions[0], nil
+}
+
+func findAssignment(ctx context.Context, assignments repositories.AssignmentRepository, id uint) (*models.Assignment, error) {
+	assignment, err := assignments.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrAssignmentNotFound
+		}
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// teacherAssignment loads an assignment the teacher may manage: one of a
+// section they teach, or a subject-wide one of a subject they teach.
+func teacherAssignment(ctx context.Context, assignments repositories.AssignmentRepository, teacherID, id uint) (*models.Assignment, error) {
+	assignment, err := findAssignment(ctx, assignments, id)
+	if err != nil {
+		return nil, err
+	}
+	if !managesAssignment(assignment, teacherID) {
+		if assignment.SectionID != nil {
+			return nil, contracts.ErrNotSectionTeacher
+		}
+		return nil, contracts.ErrNotSubjectTeacher
+	}
+	return assignment, nil
+}
+
+// studentAssignment loads an assignment handed out to the student.
+// Assignments of other sections are reported as missing.
+func (s *AssignmentService) studentAssignment(ctx context.Context, studentID, id uint) (*models.Assignment, error) {
+	assignment, err := findAssignment(ctx, s.assignments, id)
+	if err != nil {
+		return nil, err
+	}
+	enrolled, err := s.assignments.IsEnrolled(ctx, studentID, assignment)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, contracts.ErrAssignmentNotFound
+	}
+	return assignment, nil
+}
+
+// daysLate counts the started days between the end of the grace period and
+// the submission.
+func daysLate(assignment *models.Assignment, extension *models.AssignmentExtension, submittedAt time.Time) int {
+	graceEnds := assignment.Due(extension).Add(time.Duration(assignment.GraceMinutes) * time.Minute)
+	if !submittedAt.After(graceEnds) {
+		return 0
+	}
+	return int(math.Ceil(submittedAt.Sub(graceEnds).Hours() / 24))
+}
+
+func latePenaltyPercent(assignment *models.Assignment, daysLate int) float64 {
+	return math.Min(100, roundScore(float64(daysLate)*assignment.LatePenaltyPercent))
+}
+
+// managesAssignment reports whether the user teaches the assignment's
+// section or, for subject-wide assignments, its subject. Section and
+// Subject must be preloaded with their teachers.
+func managesAssignment(assignment *models.Assignment, userID uint) bool {
+	if assignment.Section != nil {
+		return teachesSection(assignment.Section, userID)
+	}
+	return assignment.Subject != nil && hasTeacher(assignment.Subject.Teachers, userID)
+}
+
+func sameID(a, b *uint) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func hasTeacher(teachers []models.User, userID uint) bool {
+	for _, t := range teachers {
+		if t.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// blobKey is where a file with the given SHA-256 is stored.
+func blobKey(checksum string) string {
+	return "submissions/" + checksum[:2] + "/" + checksum
+}
+
+// cleanFileName keeps the base name of a client-supplied file name.
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		name = "file"
+	}
+	if len(name) > maxFileNameLength {
+		name = name[len(name)-maxFileNameLength:]
+	}
+	return name
+}
+
+// fileExtension returns the lower-case extension of a file name without
+// the dot.
+func fileExtension(name string) string {
+	return strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+}
+
+func validateUploads(assignment *models.Assignment, uploads []contracts.UploadedFile, maxFiles int) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	switch {
+	case len(uploads) == 0:
+		return contracts.ValidationErrors{{Field: "files", Message: "at least one file is required"}}
+	case len(uploads) > maxFiles:
+		return contracts.ValidationErrors{{Field: "files", Message: fmt.Sprintf("at most %d files can be submitted", maxFiles)}}
+	}
+	for _, upload := range uploads {
+		if !assignment.Allows(fileExtension(upload.Name)) {
+			errs = append(errs, contracts.ValidationError{
+				Field:   "files",
+				Message: fmt.Sprintf("%s: allowed file types are %s", cleanFileName(upload.Name), strings.Join(assignment.FileTypes(), ", ")),
+			})
+		}
+	}
+	return errs
+}
+
+func validateLatePolicy(policy contracts.LatePolicyDTO, dueAt time.Time) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	if policy.GraceMinutes < 0 || policy.GraceMinutes > maxGraceMinutes {
+		errs = append(errs, contracts.ValidationError{Field: "late_policy.grace_minutes", Message: fmt.Sprintf("grace_minutes must be between 0 and %d", maxGraceMinutes)})
+	}
+	if policy.PenaltyPercentPerDay < 0 || policy.PenaltyPercentPerDay > 100 {
+		errs = append(errs, contracts.ValidationError{Field: "late_policy.penalty_percent_per_day", Message: "penalty_percent_per_day must be between 0 and 100"})
+	}
+	if policy.CutoffAt != nil && policy.CutoffAt.Before(dueAt) {
+		errs = append(errs, contracts.ValidationError{Field: "late_policy.cutoff_at", Message: "cutoff_at cannot be before due_at"})
+	}
+	// Without a cutoff the assignment closes when the grace period ends, so a
+	// penalty would never apply.
+	if policy.PenaltyPercentPerDay > 0 && policy.CutoffAt == nil {
+		errs = append(errs, contracts.ValidationError{Field: "late_policy.cutoff_at", Message: "cutoff_at is required for a late penalty"})
+	}
+	return errs
+}
+
+func applyLatePolicy(assignment *models.Assignment, policy contracts.LatePolicyDTO) {
+	assignment.GraceMinutes = policy.GraceMinutes
+	assignment.LatePenaltyPercent = policy.PenaltyPercentPerDay
+	assignment.CutoffAt = policy.CutoffAt
+}
+
+func validateAssignmentFields(title string, dueAt time.Time, maxPoints float64) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	switch {
+	case title == "":
+		errs = append(errs, contracts.ValidationError{Field: "title", Message: "title is required"})
+	case len(title) > maxAssignmentTitleLength:
+		errs = append(errs, contracts.ValidationError{Field: "title", Message: "title is too long"})
+	}
+	if dueAt.IsZero() {
+		errs = append(errs, contracts.ValidationError{Field: "due_at", Message: "due_at is required"})
+	}
+	if maxPoints <= 0 || maxPoints > maxAssignmentPoints {
+		errs = append(errs, contracts.ValidationError{Field: "max_points", Message: fmt.Sprintf("max_points must be above 0 and at most %d", maxAssignmentPoints)})
+	}
+	return errs
+}
+
+// normalizeFileTypes turns allowed extensions such as ".PDF" into the
+// comma-separated form stored on the assignment.
+func normalizeFileTypes(types []string) (string, contracts.ValidationErrors) {
+	seen := make(map[string]bool, len(types))
+	normalized := make([]string, 0, len(types))
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(t), "."))
+		if t == "" || seen[t] {
+			continue
+		}
+		if strings.ContainsAny(t, ",./\\ ") {
+			return "", contracts.ValidationErrors{{Field: "allowed_types", Message: fmt.Sprintf("%q is not a file extension", t)}}
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+
+	joined := strings.Join(normalized, ",")
+	if len(joined) > maxAllowedTypesLength {
+		return "", contracts.ValidationErrors{{Field: "allowed_types", Message: "too many allowed types"}}
+	}
+	return joined, nil
+}
+
+// mapToAssignmentDTO maps an assignment as seen by a student with the given
+// extension, or by a teacher when extension is nil.
+func mapToAssignmentDTO(assignment *models.Assignment, extension *models.AssignmentExtension, now time.Time) *contracts.AssignmentDTO {
+	closesAt := assignment.ClosesAt(extension)
+	dto := &contracts.AssignmentDTO{
+		ID:           assignment.ID,
+		SubjectID:    assignment.SubjectID,
+		TermID:       assignment.TermID,
+		SectionID:    assignment.SectionID,
+		Title:        assignment.Title,
+		Description:  assignment.Description,
+		DueAt:        assignment.DueAt,
+		MaxPoints:    assignment.MaxPoints,
+		AllowedTypes: assignment.FileTypes(),
+		LatePolicy: contracts.LatePolicyDTO{
+			GraceMinutes:         assignment.GraceMinutes,
+			PenaltyPercentPerDay: assignment.LatePenaltyPercent,
+			CutoffAt:             assignment.CutoffAt,
+		},
+		ClosesAt:             closesAt,
+		Open:                 now.Before(closesAt),
+		GradebookComponentID: assignment.GradebookComponentID,
+		GradesReleasedAt:     assignment.GradesReleasedAt,
+	}
+	if extension != nil {
+		dto.ExtendedDueAt = &extension.DueAt
+	}
+	if assignment.Rubric != nil {
+		dto.Rubric = mapToRubricDTO(assignment.Rubric)
+	}
+	if assignment.Subject != nil {
+		dto.SubjectName = assignment.Subject.Name
+	}
+	if assignment.Section != nil {
+		dto.SectionCode = assignment.Section.Code
+	}
+	return dto
+}
+
+// mapToSubmissionDTO maps a submission, with its grade when withGrade is
+// set. Lateness is judged against the student's current extension until the
+// submission is graded, and against what was recorded with the grade
+// afterwards.
+func mapToSubmissionDTO(submission *models.Submission, assignment *models.Assignment, extension *models.AssignmentExtension, withGrade bool) *contracts.SubmissionDTO {
+	dto := &contracts.SubmissionDTO{
+		ID:           submission.ID,
+		AssignmentID: submission.AssignmentID,
+		StudentID:    submission.StudentID,
+		Version:      submission.Version,
+		SubmittedAt:  submission.SubmittedAt,
+		Late:         submission.SubmittedAt.After(assignment.Due(extension)),
+		DaysLate:     daysLate(assignment, extension, submission.SubmittedAt),
+		Files:        make([]contracts.SubmissionFileDTO, 0, len(submission.Files)),
+	}
+	if withGrade && submission.RawScore != nil && submission.Score != nil && submission.GradedAt != nil {
+		dto.DaysLate = submission.DaysLate
+		dto.Grade = &contracts.SubmissionGradeDTO{
+			RawScore:       *submission.RawScore,
+			DaysLate:       submission.DaysLate,
+			PenaltyPercent: submission.PenaltyPercent,
+			Penalty:        roundScore(*submission.RawScore - *submission.Score),
+			Score:          *submission.Score,
+			Feedback:       submission.Feedback,
+			GradedAt:       *submission.GradedAt,
+		}
+		if assignment.Rubric != nil && len(submission.RubricScores) > 0 {
+			dto.Grade.Rubric = mapToCriterionScoreDTOs(assignment.Rubric, submission.RubricScores)
+		}
+	}
+	if submission.Student != nil {
+		dto.StudentName = submission.Student.Name
+	}
+	for _, f := range submission.Files {
+		dto.Files = append(dto.Files, contracts.SubmissionFileDTO{
+			ID:          f.ID,
+			Name:        f.Name,
+			ContentType: f.ContentType,
+			Size:        f.Size,
+			Checksum:    f.Checksum,
+		})
+	}
+	return dto
+}
+
+func mapToExtensionDTO(extension *models.AssignmentExtension) *contracts.ExtensionDTO {
+	dto := &contracts.ExtensionDTO{
+		ID:           extension.ID,
+		AssignmentID: extension.AssignmentID,
+		StudentID:    extension.StudentID,
+		DueAt:        extension.DueAt,
+		Reason:       extension.Reason,
+		GrantedByID:  extension.GrantedByID,
+		GrantedAt:    extension.CreatedAt,
+	}
+	if extension.Student != nil {
+		dto.StudentName = extension.Student.Name
+	}
+	if extension.GrantedBy != nil {
+		dto.GrantedByName = extension.GrantedBy.Name
+	}
+	return dto
+}
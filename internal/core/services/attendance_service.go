@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/auth"
+)
+
+const (
+	defaultRotationSeconds  = 15
+	minRotationSeconds      = 5
+	maxRotationSeconds      = 300
+	defaultLateAfterMinutes = 10
+	maxLateAfterMinutes     = 240
+	maxOverridesPerRequest  = 1000
+)
+
+// AttendanceService runs class sessions. While a session is open its
+// teacher displays a check-in code that rotates every few seconds; students
+// scan it to check in, and teachers can override any status afterwards.
+type AttendanceService struct {
+	attendance  repositories.AttendanceRepository
+	sections    repositories.SectionRepository
+	enrollments repositories.EnrollmentRepository
+	terms       repositories.TermRepository
+}
+
+func NewAttendanceService(
+	attendance repositories.AttendanceRepository,
+	sections repositories.SectionRepository,
+	enrollments repositories.EnrollmentRepository,
+	terms repositories.TermRepository,
+) *AttendanceService {
+	return &AttendanceService{attendance: attendance, sections: sections, enrollments: enrollments, terms: terms}
+}
+
+func (s *AttendanceService) OpenSession(ctx context.Context, teacherID, sectionID uint, input contracts.OpenSessionInput) (*contracts.ClassSessionDTO, error) {
+	if input.RotationSeconds == 0 {
+		input.RotationSeconds = defaultRotationSeconds
+	}
+	lateAfter := defaultLateAfterMinutes
+	if input.LateAfterMinutes != nil {
+		lateAfter = *input.LateAfterMinutes
+	}
+	var errs contracts.ValidationErrors
+	if input.RotationSeconds < minRotationSeconds || input.RotationSeconds > maxRotationSeconds {
+		errs = append(errs, contracts.ValidationError{Field: "rotation_seconds", Message: fmt.Sprintf("rotation_seconds must be between %d and %d", minRotationSeconds, maxRotationSeconds)})
+	}
+	if lateAfter < 0 || lateAfter > maxLateAfterMinutes {
+		errs = append(errs, contracts.ValidationError{Field: "late_after_minutes", Message: fmt.Sprintf("late_after_minutes must be between 0 and %d", maxLateAfterMinutes)})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if _, err := s.teacherSection(ctx, teacherID, sectionID); err != nil {
+		return nil, err
+	}
+
+	opener := teacherID
+	session := &models.ClassSession{
+		SectionID:        sectionID,
+		OpenedByID:       &opener,
+		StartedAt:        time.Now(),
+		RotationSeconds:  input.RotationSeconds,
+		LateAfterMinutes: lateAfter,
+	}
+	if err := s.attendance.CreateSession(ctx, session); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrSessionOpen
+		}
+		return nil, err
+	}
+	return mapToClassSessionDTO(session), nil
+}
+
+func (s *AttendanceService) ListSessions(ctx context.Context, teacherID, sectionID uint) ([]contracts.ClassSessionDTO, error) {
+	if _, err := s.teacherSection(ctx, teacherID, sectionID); err != nil {
+		return nil, err
+	}
+	sessions, err := s.attendance.ListSessions(ctx, []uint{sectionID})
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]contracts.ClassSessionDTO, 0, len(sessions))
+	for i := range sessions {
+		dtos = append(dtos, *mapToClassSessionDTO(&sessions[i]))
+	}
+	return dtos, nil
+}
+
+func (s *AttendanceService) CloseSession(ctx context.Context, teacherID, sessionID uint) (*contracts.ClassSessionDTO, error) {
+	session, err := s.teacherSession(ctx, teacherID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	closed, err := s.attendance.CloseSession(ctx, session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, contracts.ErrSessionClosed
+	}
+	session.ClosedAt = &now
+	return mapToClassSessionDTO(session), nil
+}
+
+// CheckInCode returns the code to display for the current rotation step.
+func (s *AttendanceService) CheckInCode(ctx context.Context, teacherID, sessionID uint) (*contracts.CheckInCodeDTO, error) {
+	session, err := s.teacherSession(ctx, teacherID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.Open() {
+		return nil, contracts.ErrSessionClosed
+	}
+
+	now := time.Now()
+	period := int64(session.RotationSeconds)
+	step := now.Unix() / period
+	rotatesAt := time.Unix((step+1)*period, 0)
+	// A code stays valid for one extra step so that a scan just before the
+	// rotation is not lost in transit.
+	payload, err := auth.SignAttendanceCode(session.ID, step, rotatesAt.Add(time.Duration(period)*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return &contracts.CheckInCodeDTO{
+		SessionID: session.ID,
+		Payload:   payload,
+		ExpiresAt: rotatesAt,
+		RefreshIn: int(math.Ceil(rotatesAt.Sub(now).Seconds())),
+	}, nil
+}
+
+// CheckIn records the student as present, or late, at the session named by
+// a scanned code. Checking in again returns the existing record.
+func (s *AttendanceService) CheckIn(ctx context.Context, studentID uint, input contracts.CheckInInput) (*contracts.CheckInDTO, error) {
+	if input.Code == "" {
+		return nil, contracts.ValidationErrors{{Field: "code", Message: "code is required"}}
+	}
+	claims, err := auth.ParseAttendanceCode(input.Code)
+	if err != nil {
+		return nil, contracts.ErrInvalidCheckIn
+	}
+
+	session, err := s.findSession(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, contracts.ErrSessionNotFound) {
+			return nil, contracts.ErrInvalidCheckIn
+		}
+		return nil, err
+	}
+	if !session.Open() {
+		return nil, contracts.ErrSessionClosed
+	}
+
+	now := time.Now()
+	current := now.Unix() / int64(session.RotationSeconds)
+	if claims.Step != current && claims.Step != current-1 {
+		return nil, contracts.ErrInvalidCheckIn
+	}
+
+	enrollment, err := s.enrollments.FindByStudentAndSection(ctx, studentID, session.SectionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrNotEnrolled
+		}
+		return nil, err
+	}
+	if enrollment.Status != models.EnrollmentEnrolled {
+		return nil, contracts.ErrNotEnrolled
+	}
+
+	status := models.AttendancePresent
+	if now.After(session.StartedAt.Add(time.Duration(session.LateAfterMinutes) * time.Minute)) {
+		status = models.AttendanceLate
+	}
+	record := &models.AttendanceRecord{
+		SessionID:    session.ID,
+		EnrollmentID: enrollment.ID,
+		Status:       status,
+		Source:       models.AttendanceSourceQR,
+		CheckedInAt:  &now,
+	}
+	if _, err := s.attendance.CreateRecord(ctx, record); err != nil {
+		return nil, err
+	}
+
+	dto := &contracts.CheckInDTO{SessionID: session.ID, SectionID: session.SectionID, Status: record.Status}
+	if record.CheckedInAt != nil {
+		dto.CheckedInAt = *record.CheckedInAt
+	} else {
+		dto.CheckedInAt = record.UpdatedAt
+	}
+	return dto, nil
+}
+
+// SessionAttendance lists every enrolled student's status at the session.
+func (s *AttendanceService) SessionAttendance(ctx context.Context, teacherID, sessionID uint) ([]contracts.SessionAttendanceDTO, error) {
+	session, err := s.teacherSession(ctx, teacherID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionAttendance(ctx, session)
+}
+
+// OverrideAttendance sets students' statuses at the session, open or closed.
+func (s *AttendanceService) OverrideAttendance(ctx context.Context, teacherID, sessionID uint, input contracts.AttendanceOverridesInput) ([]contracts.SessionAttendanceDTO, error) {
+	switch {
+	case len(input.Records) == 0:
+		return nil, contracts.ValidationErrors{{Field: "records", Message: "at least one record is required"}}
+	case len(input.Records) > maxOverridesPerRequest:
+		return nil, contracts.ValidationErrors{{Field: "records", Message: fmt.Sprintf("at most %d records per request", maxOverridesPerRequest)}}
+	}
+
+	session, err := s.teacherSession(ctx, teacherID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := s.attendance.ListEnrolled(ctx, session.SectionID)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint]uint, len(enrollments))
+	for _, e := range enrollments {
+		byStudent[e.StudentID] = e.ID
+	}
+
+	var errs contracts.ValidationErrors
+	seen := map[uint]bool{}
+	updatedBy := teacherID
+	records := make([]models.AttendanceRecord, 0, len(input.Records))
+	for i, o := range input.Records {
+		field := fmt.Sprintf("records[%d]", i)
+		enrollmentID, ok := byStudent[o.StudentID]
+		switch {
+		case !ok:
+			errs = append(errs, contracts.ValidationError{Field: field + ".student_id", Message: "student is not enrolled in this section"})
+		case seen[o.StudentID]:
+			errs = append(errs, contracts.ValidationError{Field: field + ".student_id", Message: "student is listed more than once"})
+		}
+		seen[o.StudentID] = true
+		switch o.Status {
+		case models.AttendancePresent, models.AttendanceLate, models.AttendanceExcused, models.AttendanceAbsent:
+		default:
+			errs = append(errs, contracts.ValidationError{Field: field + ".status", Message: "status must be present, late, excused or absent"})
+		}
+		records = append(records, models.AttendanceRecord{
+			SessionID:    session.ID,
+			EnrollmentID: enrollmentID,
+			Status:       o.Status,
+			Source:       models.AttendanceSourceManual,
+			UpdatedByID:  &updatedBy,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err := s.attendance.UpsertRecords(ctx, records); err != nil {
+		return nil, err
+	}
+	return s.sessionAttendance(ctx, session)
+}
+
+// SectionReport summarizes every enrolled student's attendance over the
+// section's closed sessions.
+func (s *AttendanceService) SectionReport(ctx context.Context, teacherID, sectionID uint) (*contracts.SectionAttendanceReportDTO, error) {
+	section, err := s.teacherSection(ctx, teacherID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := s.attendance.ListEnrolled(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, records, err := s.sessionsWithRecords(ctx, []uint{sectionID})
+	if err != nil {
+		return nil, err
+	}
+
+	sectionDTO := mapToSectionDTO(section)
+	report := &contracts.SectionAttendanceReportDTO{
+		SectionID:   section.ID,
+		SectionCode: section.Code,
+		SubjectName: sectionDTO.SubjectName,
+		TermName:    sectionDTO.TermName,
+		Students:    make([]contracts.StudentAttendanceReportDTO, 0, len(enrollments)),
+	}
+	for i := range enrollments {
+		e := &enrollments[i]
+		row := contracts.StudentAttendanceReportDTO{
+			StudentID:        e.StudentID,
+			AttendanceCounts: countAttendance(sessions, records, e),
+		}
+		if e.Student != nil {
+			row.Name = e.Student.Name
+			row.Email = e.Student.Email
+		}
+		report.Students = append(report.Students, row)
+	}
+	return report, nil
+}
+
+// MyAttendance summarizes the student's attendance in each enrolled
+// section, optionally limited to a term (see resolveTermParam).
+func (s *AttendanceService) MyAttendance(ctx context.Context, studentID uint, term string) ([]contracts.MyAttendanceDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.enrollments.ListByStudent(ctx, studentID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments := make([]models.Enrollment, 0, len(all))
+	sectionIDs := make([]uint, 0, len(all))
+	for _, e := range all {
+		if e.Status == models.EnrollmentEnrolled {
+			enrollments = append(enrollments, e)
+			sectionIDs = append(sectionIDs, e.SectionID)
+		}
+	}
+	sessions, records, err := s.sessionsWithRecords(ctx, sectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.MyAttendanceDTO, 0, len(enrollments))
+	for i := range enrollments {
+		e := &enrollments[i]
+		dto := contracts.MyAttendanceDTO{
+			SectionID:        e.SectionID,
+			AttendanceCounts: countAttendance(sessions, records, e),
+		}
+		if e.Section != nil {
+			sectionDTO := mapToSectionDTO(e.Section)
+			dto.SectionCode = sectionDTO.Code
+			dto.SubjectName = sectionDTO.SubjectName
+			dto.TermName = sectionDTO.TermName
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (s *AttendanceService) sessionAttendance(ctx context.Context, session *models.ClassSession) ([]contracts.SessionAttendanceDTO, error) {
+	enrollments, err := s.attendance.ListEnrolled(ctx, session.SectionID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.attendance.ListRecords(ctx, []uint{session.ID})
+	if err != nil {
+		return nil, err
+	}
+	byEnrollment := make(map[uint]models.AttendanceRecord, len(records))
+	for _, r := range records {
+		byEnrollment[r.EnrollmentID] = r
+	}
+
+	dtos := make([]contracts.SessionAttendanceDTO, 0, len(enrollments))
+	for _, e := range enrollments {
+		dto := contracts.SessionAttendanceDTO{StudentID: e.StudentID, Status: models.AttendanceAbsent}
+		if e.Student != nil {
+			dto.Name = e.Student.Name
+			dto.Email = e.Student.Email
+		}
+		if r, ok := byEnrollment[e.ID]; ok {
+			dto.Status = r.Status
+			dto.Source = r.Source
+			dto.CheckedInAt = r.CheckedInAt
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+// sessionsWithRecords loads the closed sessions of the sections and their
+// records keyed by session and enrollment.
+func (s *AttendanceService) sessionsWithRecords(ctx context.Context, sectionIDs []uint) ([]models.ClassSession, map[uint]map[uint]string, error) {
+	all, err := s.attendance.ListSessions(ctx, sectionIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	sessions := make([]models.ClassSession, 0, len(all))
+	ids := make([]uint, 0, len(all))
+	for _, session := range all {
+		if !session.Open() {
+			sessions = append(sessions, session)
+			ids = append(ids, session.ID)
+		}
+	}
+
+	list, err := s.attendance.ListRecords(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	records := map[uint]map[uint]string{}
+	for _, r := range list {
+		if records[r.SessionID] == nil {
+			records[r.SessionID] = map[uint]string{}
+		}
+		records[r.SessionID][r.EnrollmentID] = r.Status
+	}
+	return sessions, records, nil
+}
+
+// countAttendance tallies an enrollment's statuses over the closed sessions
+// of its section. Sessions held before the student enrolled are skipped
+// unless a status was recorded for them.
+func countAttendance(sessions []models.ClassSession, records map[uint]map[uint]string, e *models.Enrollment) contracts.AttendanceCounts {
+	var counts contracts.AttendanceCounts
+	for _, session := range sessions {
+		if session.SectionID != e.SectionID {
+			continue
+		}
+		status, ok := records[session.ID][e.ID]
+		if !ok {
+			if e.EnrolledAt != nil && session.StartedAt.Before(*e.EnrolledAt) {
+				continue
+			}
+			status = models.AttendanceAbsent
+		}
+
+		counts.Sessions++
+		switch status {
+		case models.AttendancePresent:
+			counts.Present++
+		case models.AttendanceLate:
+			counts.Late++
+		case models.AttendanceExcused:
+			counts.Excused++
+		default:
+			counts.Absent++
+		}
+	}
+
+	if counted := counts.Sessions - counts.Excused; counted > 0 {
+		pct := roundScore(float64(counts.Present+counts.Late) / float64(counted) * 100)
+		counts.Percentage = &pct
+	}
+	return counts
+}
+
+func (s *AttendanceService) teacherSection(ctx context.Context, teacherID, sectionID uint) (*models.CourseSection, error) {
+	section, err := findSection(ctx, s.sections, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !teachesSection(section, teacherID) {
+		return nil, contracts.ErrNotSectionTeacher
+	}
+	return section, nil
+}
+
+func (s *AttendanceService) teacherSession(ctx context.Context, teacherID, sessionID uint) (*models.ClassSession, error) {
+	session, err := s.findSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Section == nil || !teachesSection(session.Section, teacherID) {
+		return nil, contracts.ErrNotSectionTeacher
+	}
+	return session, nil
+}
+
+func (s *AttendanceService) findSession(ctx context.Context, id uint) (*models.ClassSession, error) {
+	session, err := s.attendance.FindSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+func mapToClassSessionDTO(session *models.ClassSession) *contracts.ClassSessionDTO {
+	return &contracts.ClassSessionDTO{
+		ID:               session.ID,
+		SectionID:        session.SectionID,
+		StartedAt:        session.StartedAt,
+		ClosedAt:         session.ClosedAt,
+		Open:             session.Open(),
+		RotationSeconds:  session.RotationSeconds,
+		LateAfterMinutes: session.LateAfterMinutes,
+	}
+}
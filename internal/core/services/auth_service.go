@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -11,18 +13,45 @@ import (
 	"github.com/arman300s/uni-portal/internal/core/repositories"
 	"github.com/arman300s/uni-portal/internal/models"
 	"github.com/arman300s/uni-portal/pkg/auth"
+	"github.com/arman300s/uni-portal/pkg/cache"
 	"github.com/arman300s/uni-portal/pkg/queue"
 	"github.com/arman300s/uni-portal/pkg/tasks"
 )
 
+const (
+	passwordResetTTL           = 30 * time.Minute
+	emailVerificationTTL       = 24 * time.Hour
+	verificationResendCooldown = time.Minute
+)
+
 // AuthService coordinates signup/login flows.
 type AuthService struct {
-	users repositories.UserRepository
-	roles repositories.RoleRepository
+	users         repositories.UserRepository
+	roles         repositories.RoleRepository
+	refreshTokens repositories.RefreshTokenRepository
+	userTokens    repositories.UserTokenRepository
+	recoveryCodes repositories.RecoveryCodeRepository
+	throttle      *loginThrottle
+	cache         cache.Cache
 }
 
-func NewAuthService(users repositories.UserRepository, roles repositories.RoleRepository) *AuthService {
-	return &AuthService{users: users, roles: roles}
+func NewAuthService(
+	users repositories.UserRepository,
+	roles repositories.RoleRepository,
+	refreshTokens repositories.RefreshTokenRepository,
+	userTokens repositories.UserTokenRepository,
+	recoveryCodes repositories.RecoveryCodeRepository,
+	c cache.Cache,
+) *AuthService {
+	return &AuthService{
+		users:         users,
+		roles:         roles,
+		refreshTokens: refreshTokens,
+		userTokens:    userTokens,
+		recoveryCodes: recoveryCodes,
+		cache:         c,
+		throttle:      newLoginThrottle(),
+	}
 }
 
 func (s *AuthService) Signup(ctx context.Context, input contracts.SignupInput) (*contracts.AuthResponse, error) {
@@ -52,20 +81,18 @@ func (s *AuthService) Signup(ctx context.Context, input contracts.SignupInput) (
 	if err := s.users.Create(ctx, &user); err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, s.cache, cache.TagUsers)
 
-	token, err := auth.GenerateToken(user.ID)
+	resp, _, err := s.issueTokens(ctx, user.ID, "", false)
 	if err != nil {
 		return nil, err
 	}
 
-	payload := tasks.SendWelcomeEmailPayload{
-		UserID: user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
+	if err := s.sendVerificationEmail(ctx, &user); err != nil {
+		return nil, err
 	}
-	_ = queue.Enqueue(tasks.TypeSendWelcomeEmail, payload, 0)
 
-	return &contracts.AuthResponse{Token: token, UserID: user.ID}, nil
+	return resp, nil
 }
 
 func (s *AuthService) Login(ctx context.Context, input contracts.LoginInput) (*contracts.AuthResponse, error) {
@@ -75,23 +102,277 @@ func (s *AuthService) Login(ctx context.Context, input contracts.LoginInput) (*c
 		return nil, errs
 	}
 
+	if err := s.throttle.Check(ctx, input.Email, input.IP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.users.FindByEmail(ctx, input.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, contracts.ErrInvalidCredentials
+			auth.CheckDummyPassword(input.Password)
+			return nil, s.loginFailed(ctx, input)
 		}
 		return nil, err
 	}
 
 	if err := auth.CheckPassword(user.Password, input.Password); err != nil {
-		return nil, contracts.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, input)
+	}
+
+	payload := tasks.SendWelcomeEmailPayload{
+		UserID: user.ID,
+		Email:  user.Email,
+		Name:   user.Name,
+	}
+	_ = queue.Enqueue(tasks.TypeSendWelcomeEmail, payload, 0)
+
+	// With two factors the failure count is only reset once the second
+	// one checks out, so that guessing codes cannot be interleaved with
+	// correct passwords to stay below the lockout.
+	if user.TOTPEnabledAt != nil {
+		mfaToken, err := auth.GenerateMFAPendingToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &contracts.AuthResponse{UserID: user.ID, MFARequired: true, MFAToken: mfaToken}, nil
+	}
+	if err := s.throttle.Reset(ctx, input.Email); err != nil {
+		return nil, err
+	}
+
+	resp, _, err := s.issueTokens(ctx, user.ID, "", false)
+	if err != nil {
+		return nil, err
+	}
+	resp.MFAEnrollmentRequired = user.Role != nil && auth.MFARequiredForRole(user.Role.Name)
+
+	return resp, nil
+}
+
+// loginFailed records a failed attempt and returns the error to report.
+func (s *AuthService) loginFailed(ctx context.Context, input contracts.LoginInput) error {
+	if err := s.throttle.RecordFailure(ctx, input.Email, input.IP); err != nil {
+		return err
+	}
+	return contracts.ErrInvalidCredentials
+}
+
+// LoginStatus reports whether the user's account is locked and lists its
+// recent failed login attempts.
+func (s *AuthService) LoginStatus(ctx context.Context, userID uint) (*contracts.LoginStatusDTO, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	status, err := s.throttle.Status(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	status.UserID = user.ID
+	return status, nil
+}
+
+// UnlockAccount lifts a login lockout on the user's account.
+func (s *AuthService) UnlockAccount(ctx context.Context, userID uint) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrUserNotFound
+		}
+		return err
+	}
+	return s.throttle.Reset(ctx, user.Email)
+}
+
+// Refresh exchanges a refresh token for a new access/refresh pair. The
+// presented token is revoked and replaced by the new one. Presenting a token
+// that has already been rotated revokes its whole family.
+func (s *AuthService) Refresh(ctx context.Context, input contracts.RefreshInput) (*contracts.AuthResponse, error) {
+	input.RefreshToken = strings.TrimSpace(input.RefreshToken)
+
+	if errs := validateRefreshInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+
+	current, err := s.refreshTokens.FindByHash(ctx, auth.HashToken(input.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrInvalidRefresh
+		}
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		if err := s.refreshTokens.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, contracts.ErrRefreshReused
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, contracts.ErrInvalidRefresh
+	}
+
+	resp, next, err := s.issueTokens(ctx, current.UserID, current.FamilyID, current.MFA)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.refreshTokens.Revoke(ctx, current.ID, &next.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request rotated the same token first.
+		if err := s.refreshTokens.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, contracts.ErrRefreshReused
+	}
+
+	return resp, nil
+}
+
+// Logout revokes the access token described by claims and, when a refresh
+// token is supplied, the refresh token family it belongs to.
+func (s *AuthService) Logout(ctx context.Context, claims *auth.Claims, input contracts.LogoutInput) error {
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if err := auth.RevokeToken(ctx, claims.ID, expiresAt); err != nil {
+		return err
+	}
+
+	raw := strings.TrimSpace(input.RefreshToken)
+	if raw == "" {
+		return nil
+	}
+
+	refresh, err := s.refreshTokens.FindByHash(ctx, auth.HashToken(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if refresh.UserID != claims.UserID {
+		return nil
+	}
+	return s.refreshTokens.RevokeFamily(ctx, refresh.FamilyID)
+}
+
+// ForgotPassword queues a password reset for the address. The account is
+// looked up by the worker (see PasswordResetEmail), so the request takes as
+// long for unknown addresses as for registered ones and callers cannot tell
+// them apart.
+func (s *AuthService) ForgotPassword(ctx context.Context, input contracts.ForgotPasswordInput) error {
+	input.Email = strings.TrimSpace(strings.ToLower(input.Email))
+
+	if errs := validateForgotPasswordInput(input); len(errs) > 0 {
+		return errs
+	}
+
+	return queue.Enqueue(tasks.TypeRequestPasswordReset, tasks.RequestPasswordResetPayload{Email: input.Email}, 0)
+}
+
+// PasswordResetEmail issues a reset token for the user registered under
+// email and returns the email carrying it. It returns nil for unknown
+// addresses.
+func (s *AuthService) PasswordResetEmail(ctx context.Context, email string) (*tasks.SendPasswordResetEmailPayload, error) {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	token, err := auth.GenerateToken(user.ID)
+	raw, token, err := s.createUserToken(ctx, user.ID, models.TokenPurposePasswordReset, passwordResetTTL)
 	if err != nil {
 		return nil, err
 	}
 
+	return &tasks.SendPasswordResetEmailPayload{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Token:     raw,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// ResetPassword consumes a reset token, stores the new password and signs the
+// user out of every existing session.
+func (s *AuthService) ResetPassword(ctx context.Context, input contracts.ResetPasswordInput) error {
+	input.Token = strings.TrimSpace(input.Token)
+
+	if errs := validateResetPasswordInput(input); len(errs) > 0 {
+		return errs
+	}
+
+	token, err := s.consumeUserToken(ctx, models.TokenPurposePasswordReset, input.Token, contracts.ErrInvalidResetToken)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	hashed, err := auth.HashPassword(input.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+	if err := s.users.Save(ctx, user); err != nil {
+		return err
+	}
+	if err := s.throttle.Reset(ctx, user.Email); err != nil {
+		return err
+	}
+
+	return revokeUserSessions(ctx, s.refreshTokens, user.ID)
+}
+
+// VerifyEmail consumes an email verification token and marks the owner's
+// address as verified.
+func (s *AuthService) VerifyEmail(ctx context.Context, rawToken string) error {
+	rawToken = strings.TrimSpace(rawToken)
+	if rawToken == "" {
+		return contracts.ErrInvalidVerifyToken
+	}
+
+	token, err := s.consumeUserToken(ctx, models.TokenPurposeEmailVerification, rawToken, contracts.ErrInvalidVerifyToken)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrInvalidVerifyToken
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := s.users.Save(ctx, user); err != nil {
+		return err
+	}
+	invalidateCache(ctx, s.cache, cache.TagUsers)
+
 	payload := tasks.SendWelcomeEmailPayload{
 		UserID: user.ID,
 		Email:  user.Email,
@@ -99,5 +380,143 @@ func (s *AuthService) Login(ctx context.Context, input contracts.LoginInput) (*c
 	}
 	_ = queue.Enqueue(tasks.TypeSendWelcomeEmail, payload, 0)
 
-	return &contracts.AuthResponse{Token: token, UserID: user.ID}, nil
+	return nil
+}
+
+// ResendVerification sends a fresh verification link to the user. Requests
+// are throttled to one per verificationResendCooldown.
+func (s *AuthService) ResendVerification(ctx context.Context, userID uint) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrUserNotFound
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return contracts.ErrEmailVerified
+	}
+
+	if cache.RDB != nil {
+		key := fmt.Sprintf("auth:verify_resend:%d", user.ID)
+		ok, err := cache.RDB.SetNX(ctx, key, 1, verificationResendCooldown).Result()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			retryAfter, _ := cache.RDB.TTL(ctx, key).Result()
+			return &contracts.RateLimitError{
+				Message:    "verification email was sent recently, try again later",
+				RetryAfter: retryAfter,
+			}
+		}
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *models.User) error {
+	raw, token, err := s.createUserToken(ctx, user.ID, models.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	payload := tasks.SendVerificationEmailPayload{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Token:     raw,
+		ExpiresAt: token.ExpiresAt,
+	}
+	_ = queue.Enqueue(tasks.TypeSendVerificationEmail, payload, 0)
+
+	return nil
+}
+
+// createUserToken issues a new one-time token for purpose, invalidating any
+// the user still holds for the same purpose. It returns the plain token.
+func (s *AuthService) createUserToken(ctx context.Context, userID uint, purpose string, ttl time.Duration) (string, *models.UserToken, error) {
+	if err := s.userTokens.InvalidateForUser(ctx, userID, purpose); err != nil {
+		return "", nil, err
+	}
+
+	raw, err := auth.RandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: auth.HashToken(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.userTokens.Create(ctx, token); err != nil {
+		return "", nil, err
+	}
+	return raw, token, nil
+}
+
+// consumeUserToken looks up an unused, unexpired token and marks it used.
+// invalidErr is returned for unknown, expired or already used tokens.
+func (s *AuthService) consumeUserToken(ctx context.Context, purpose, raw string, invalidErr error) (*models.UserToken, error) {
+	token, err := s.userTokens.FindByHash(ctx, purpose, auth.HashToken(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalidErr
+		}
+		return nil, err
+	}
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, invalidErr
+	}
+
+	consumed, err := s.userTokens.MarkUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, invalidErr
+	}
+	return token, nil
+}
+
+// issueTokens creates an access token and a refresh token for the user. An
+// empty familyID starts a new token family; mfa marks tokens obtained with a
+// second factor.
+func (s *AuthService) issueTokens(ctx context.Context, userID uint, familyID string, mfa bool) (*contracts.AuthResponse, *models.RefreshToken, error) {
+	version, err := auth.TokenVersion(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := auth.GenerateToken(userID, version, mfa)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if familyID == "" {
+		familyID, err = auth.RandomToken(16)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	refresh, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(refresh),
+		MFA:       mfa,
+		ExpiresAt: time.Now().Add(auth.RefreshExpiry()),
+	}
+	if err := s.refreshTokens.Create(ctx, record); err != nil {
+		return nil, nil, err
+	}
+
+	return &contracts.AuthResponse{Token: token, RefreshToken: refresh, UserID: userID}, record, nil
 }
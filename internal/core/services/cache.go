@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+// invalidateCache drops cached reads derived from data a write just changed.
+// The write has already been committed, so a failure is logged rather than
+// returned; affected entries still expire with their TTL.
+func invalidateCache(ctx context.Context, c cache.Cache, tags ...string) {
+	if c == nil {
+		return
+	}
+	if err := c.InvalidateTags(ctx, tags...); err != nil {
+		log.Printf("cache invalidation for %v failed: %v", tags, err)
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/queue"
+	"github.com/arman300s/uni-portal/pkg/tasks"
+)
+
+// EnrollmentService registers students into course sections. Sections that
+// are full put students on a first-come, first-served waitlist that is
+// promoted automatically whenever a seat opens.
+type EnrollmentService struct {
+	enrollments repositories.EnrollmentRepository
+	terms       repositories.TermRepository
+	subjects    *SubjectService
+	timetable   *TimetableService
+}
+
+func NewEnrollmentService(
+	enrollments repositories.EnrollmentRepository,
+	terms repositories.TermRepository,
+	subjects *SubjectService,
+	timetable *TimetableService,
+) *EnrollmentService {
+	return &EnrollmentService{enrollments: enrollments, terms: terms, subjects: subjects, timetable: timetable}
+}
+
+// ListEnrollments returns the student's enrollments and waitlist places,
+// optionally limited to a term (see resolveTermParam).
+func (s *EnrollmentService) ListEnrollments(ctx context.Context, studentID uint, term string) ([]contracts.EnrollmentDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollments.ListByStudent(ctx, studentID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.EnrollmentDTO, 0, len(enrollments))
+	for i := range enrollments {
+		dto, err := s.toDTO(ctx, s.enrollments, &enrollments[i])
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, *dto)
+	}
+	return dtos, nil
+}
+
+// Enroll takes a seat in the section for the student, or a place on its
+// waitlist when the section is full. Open seats go to students already on
+// the waitlist first, so newcomers never jump the queue. It is only allowed while the term's
+// registration or add/drop window is open, and only once the student has
+// completed the subject's prerequisites.
+func (s *EnrollmentService) Enroll(ctx context.Context, studentID uint, input contracts.EnrollmentInput) (*contracts.EnrollmentDTO, error) {
+	if input.SectionID == 0 {
+		return nil, contracts.ValidationErrors{{Field: "section_id", Message: "section_id is required"}}
+	}
+
+	var (
+		dto      *contracts.EnrollmentDTO
+		section  *models.CourseSection
+		promoted []models.Enrollment
+	)
+	err := s.enrollments.Transaction(ctx, func(tx repositories.EnrollmentRepository) error {
+		var err error
+		section, err = tx.LockSection(ctx, input.SectionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return contracts.ErrSectionNotFound
+			}
+			return err
+		}
+
+		now := time.Now()
+		if !canAddOrDrop(section.Term, now) {
+			return contracts.ErrRegistrationClosed
+		}
+		if err := s.subjects.EnsurePrerequisites(ctx, studentID, section.SubjectID); err != nil {
+			return err
+		}
+		timetable := tx.Timetable()
+		if err := timetable.LockTerm(ctx, section.TermID); err != nil {
+			return err
+		}
+		if err := s.timetable.CheckStudent(ctx, timetable, studentID, section); err != nil {
+			return err
+		}
+
+		enrollment, err := tx.FindByStudentAndSection(ctx, studentID, section.ID)
+		switch {
+		case err == nil && enrollment.Status != models.EnrollmentDropped:
+			return contracts.ErrAlreadyEnrolled
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			enrollment = &models.Enrollment{StudentID: studentID, SectionID: section.ID}
+		case err != nil:
+			return err
+		}
+
+		// A seat can be open while students wait, when they clashed with
+		// it at the last promotion; whoever no longer clashes goes first.
+		promoted, err = promoteWaitlisted(ctx, tx, section)
+		if err != nil {
+			return err
+		}
+		enrolled, err := tx.CountEnrolled(ctx, section.ID)
+		if err != nil {
+			return err
+		}
+
+		enrollment.DroppedAt = nil
+		if enrolled < int64(section.Capacity) {
+			enrollment.Status = models.EnrollmentEnrolled
+			enrollment.EnrolledAt = &now
+			enrollment.WaitlistedAt = nil
+		} else {
+			enrollment.Status = models.EnrollmentWaitlisted
+			enrollment.WaitlistedAt = &now
+			enrollment.EnrolledAt = nil
+		}
+		if err := tx.Save(ctx, enrollment); err != nil {
+			return err
+		}
+
+		enrollment.Section = section
+		dto, err = s.toDTO(ctx, tx, enrollment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyPromoted(promoted, section)
+	return dto, nil
+}
+
+// Drop gives up the student's seat or waitlist place. A freed seat goes to
+// the first student on the waitlist. Seats can only be given up while the
+// term's registration or add/drop window is open; leaving the waitlist is
+// always allowed.
+func (s *EnrollmentService) Drop(ctx context.Context, studentID, enrollmentID uint) error {
+	enrollment, err := s.enrollments.FindByID(ctx, enrollmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrEnrollmentNotFound
+		}
+		return err
+	}
+	if enrollment.StudentID != studentID {
+		return contracts.ErrEnrollmentNotFound
+	}
+
+	var promoted []models.Enrollment
+	err = s.enrollments.Transaction(ctx, func(tx repositories.EnrollmentRepository) error {
+		section, err := tx.LockSection(ctx, enrollment.SectionID)
+		if err != nil {
+			return err
+		}
+
+		// Re-read under the section lock; a promotion may have changed it.
+		current, err := tx.FindByID(ctx, enrollment.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status == models.EnrollmentDropped {
+			return contracts.ErrEnrollmentNotFound
+		}
+
+		now := time.Now()
+		wasEnrolled := current.Status == models.EnrollmentEnrolled
+		if wasEnrolled && !canAddOrDrop(section.Term, now) {
+			return contracts.ErrRegistrationClosed
+		}
+
+		current.Status = models.EnrollmentDropped
+		current.DroppedAt = &now
+		if err := tx.Save(ctx, current); err != nil {
+			return err
+		}
+
+		if wasEnrolled {
+			promoted, err = promoteWaitlisted(ctx, tx, section)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	notifyPromoted(promoted, enrollment.Section)
+	return nil
+}
+
+func (s *EnrollmentService) toDTO(ctx context.Context, repo repositories.EnrollmentRepository, enrollment *models.Enrollment) (*contracts.EnrollmentDTO, error) {
+	dto := &contracts.EnrollmentDTO{
+		ID:         enrollment.ID,
+		SectionID:  enrollment.SectionID,
+		Status:     enrollment.Status,
+		EnrolledAt: enrollment.EnrolledAt,
+	}
+	if section := enrollment.Section; section != nil {
+		dto.SectionCode = section.Code
+		if section.Subject != nil {
+			dto.SubjectName = section.Subject.Name
+		}
+		if section.Term != nil {
+			dto.TermName = section.Term.Name
+		}
+	}
+	if enrollment.Status == models.EnrollmentWaitlisted {
+		position, err := repo.WaitlistPosition(ctx, enrollment)
+		if err != nil {
+			return nil, err
+		}
+		dto.WaitlistPosition = position
+	}
+	return dto, nil
+}
+
+// canAddOrDrop reports whether students may change their enrollment in the
+// term at the given time.
+func canAddOrDrop(term *models.Term, at time.Time) bool {
+	return term != nil && (term.RegistrationOpen(at) || term.AddDropOpen(at))
+}
+
+// promoteWaitlisted moves students from the head of the waitlist into any
+// open seats of the section. Students the section would clash with on their
+// timetable stay waitlisted and the seat goes to the next in line. The
+// section must be locked by tx.
+func promoteWaitlisted(ctx context.Context, tx repositories.EnrollmentRepository, section *models.CourseSection) ([]models.Enrollment, error) {
+	enrolled, err := tx.CountEnrolled(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	open := section.Capacity - int(enrolled)
+	if open <= 0 {
+		return nil, nil
+	}
+
+	waitlist, err := tx.NextWaitlisted(ctx, section.ID, -1)
+	if err != nil || len(waitlist) == 0 {
+		return nil, err
+	}
+	timetable := tx.Timetable()
+	if err := timetable.LockTerm(ctx, section.TermID); err != nil {
+		return nil, err
+	}
+	schedule, err := timetable.LoadTerm(ctx, section.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var promoted []models.Enrollment
+	for i := range waitlist {
+		if len(promoted) == open {
+			break
+		}
+		if studentConflicts(schedule, waitlist[i].StudentID, section.ID) != nil {
+			continue
+		}
+		waitlist[i].Status = models.EnrollmentEnrolled
+		waitlist[i].EnrolledAt = &now
+		if err := tx.Save(ctx, &waitlist[i]); err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, waitlist[i])
+	}
+	return promoted, nil
+}
+
+// notifyPromoted emails students who were moved off the waitlist. It must
+// only be called once the promotion has been committed.
+func notifyPromoted(promoted []models.Enrollment, section *models.CourseSection) {
+	for _, e := range promoted {
+		if e.Student == nil {
+			continue
+		}
+		payload := tasks.SendWaitlistPromotionPayload{
+			UserID:       e.StudentID,
+			Email:        e.Student.Email,
+			Name:         e.Student.Name,
+			EnrollmentID: e.ID,
+			SectionCode:  section.Code,
+		}
+		if section.Subject != nil {
+			payload.SubjectName = section.Subject.Name
+		}
+		if section.Term != nil {
+			payload.TermName = section.Term.Name
+		}
+		_ = queue.Enqueue(tasks.TypeSendWaitlistPromotion, payload, 0)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+const maxGradeLetterLength = 5
+
+// GradeScaleService manages the letter grade scale used to turn final marks
+// into letters and grade points.
+type GradeScaleService struct {
+	scale repositories.GradeScaleRepository
+}
+
+func NewGradeScaleService(scale repositories.GradeScaleRepository) *GradeScaleService {
+	return &GradeScaleService{scale: scale}
+}
+
+func (s *GradeScaleService) GetScale(ctx context.Context) ([]contracts.GradeBandDTO, error) {
+	bands, err := s.scale.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mapToGradeBandDTOs(bands), nil
+}
+
+func (s *GradeScaleService) ReplaceScale(ctx context.Context, input contracts.GradeScaleInput) ([]contracts.GradeBandDTO, error) {
+	bands, errs := buildGradeBands(input)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if err := s.scale.Replace(ctx, bands); err != nil {
+		return nil, err
+	}
+	return s.GetScale(ctx)
+}
+
+func buildGradeBands(input contracts.GradeScaleInput) ([]models.GradeBand, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	if len(input.Bands) == 0 {
+		return nil, contracts.ValidationErrors{{Field: "bands", Message: "at least one band is required"}}
+	}
+
+	letters := map[string]bool{}
+	mins := map[float64]bool{}
+	hasZero := false
+	bands := make([]models.GradeBand, 0, len(input.Bands))
+	for i, b := range input.Bands {
+		field := fmt.Sprintf("bands[%d]", i)
+		letter := strings.TrimSpace(b.Letter)
+		switch {
+		case letter == "":
+			errs = append(errs, contracts.ValidationError{Field: field + ".letter", Message: "letter is required"})
+		case len(letter) > maxGradeLetterLength:
+			errs = append(errs, contracts.ValidationError{Field: field + ".letter", Message: "letter is too long"})
+		case letters[strings.ToUpper(letter)]:
+			errs = append(errs, contracts.ValidationError{Field: field + ".letter", Message: "letter is used more than once"})
+		}
+		letters[strings.ToUpper(letter)] = true
+
+		switch {
+		case b.MinScore < 0 || b.MinScore > 100:
+			errs = append(errs, contracts.ValidationError{Field: field + ".min_score", Message: "min_score must be between 0 and 100"})
+		case mins[b.MinScore]:
+			errs = append(errs, contracts.ValidationError{Field: field + ".min_score", Message: "min_score is used more than once"})
+		}
+		mins[b.MinScore] = true
+		hasZero = hasZero || b.MinScore == 0
+
+		if b.GradePoints < 0 || b.GradePoints > 4.3 {
+			errs = append(errs, contracts.ValidationError{Field: field + ".grade_points", Message: "grade_points must be between 0 and 4.3"})
+		}
+		bands = append(bands, models.GradeBand{Letter: letter, MinScore: b.MinScore, GradePoints: b.GradePoints})
+	}
+	if !hasZero {
+		errs = append(errs, contracts.ValidationError{Field: "bands", Message: "one band must start at 0"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	// A higher band must not be worth fewer grade points than a lower one.
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinScore > bands[j].MinScore })
+	for i := 1; i < len(bands); i++ {
+		if bands[i].GradePoints > bands[i-1].GradePoints {
+			errs = append(errs, contracts.ValidationError{
+				Field:   "bands",
+				Message: fmt.Sprintf("%s is worth more grade points than %s", bands[i].Letter, bands[i-1].Letter),
+			})
+		}
+	}
+	return bands, errs
+}
+
+// gradeBandFor returns the band of a final mark. Bands must be ordered from
+// the highest minimum score down, as returned by GradeScaleRepository.List.
+func gradeBandFor(bands []models.GradeBand, score float64) (models.GradeBand, bool) {
+	for _, b := range bands {
+		if score >= b.MinScore {
+			return b, true
+		}
+	}
+	return models.GradeBand{}, false
+}
+
+// letterFor returns the letter grade of a final mark, or "" if there is no
+// final mark yet.
+func letterFor(bands []models.GradeBand, score *float64) string {
+	if score == nil {
+		return ""
+	}
+	band, _ := gradeBandFor(bands, *score)
+	return band.Letter
+}
+
+func roundScore(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func mapToGradeBandDTOs(bands []models.GradeBand) []contracts.GradeBandDTO {
+	dtos := make([]contracts.GradeBandDTO, 0, len(bands))
+	for _, b := range bands {
+		dtos = append(dtos, contracts.GradeBandDTO{Letter: b.Letter, MinScore: b.MinScore, GradePoints: b.GradePoints})
+	}
+	return dtos
+}
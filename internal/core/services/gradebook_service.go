@@ -0,0 +1,620 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+const (
+	maxComponentNameLength = 100
+	defaultComponentPoints = 100.0
+	maxScoresPerRequest    = 2000
+	// weightTolerance absorbs rounding in weights such as 33.33 + 33.33 +
+	// 33.34.
+	weightTolerance = 0.001
+)
+
+// GradebookService lets teachers define weighted assessment components for
+// their sections and record scores, and computes final marks and letter
+// grades from them. A section is graded by the teachers of its subject and by
+// the teachers assigned to the section itself (see teacherSection).
+type GradebookService struct {
+	gradebook   repositories.GradebookRepository
+	enrollments repositories.EnrollmentRepository
+	scale       repositories.GradeScaleRepository
+	terms       repositories.TermRepository
+}
+
+func NewGradebookService(
+	gradebook repositories.GradebookRepository,
+	enrollments repositories.EnrollmentRepository,
+	scale repositories.GradeScaleRepository,
+	terms repositories.TermRepository,
+) *GradebookService {
+	return &GradebookService{gradebook: gradebook, enrollments: enrollments, scale: scale, terms: terms}
+}
+
+func (s *GradebookService) ListComponents(ctx context.Context, teacherID, sectionID uint) ([]contracts.ComponentDTO, error) {
+	if _, err := s.teacherSection(ctx, s.gradebook, teacherID, sectionID, false); err != nil {
+		return nil, err
+	}
+	components, err := s.gradebook.ListComponents(ctx, []uint{sectionID})
+	if err != nil {
+		return nil, err
+	}
+	return mapToComponentDTOs(components), nil
+}
+
+// ReplaceComponents replaces the section's assessment components. Scores of
+// kept components are preserved and every final mark is recomputed.
+func (s *GradebookService) ReplaceComponents(ctx context.Context, teacherID, sectionID uint, input contracts.ComponentsInput) ([]contracts.ComponentDTO, error) {
+	components, errs := buildComponents(input)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	err := s.gradebook.Transaction(ctx, func(tx repositories.GradebookRepository) error {
+		if _, err := s.teacherSection(ctx, tx, teacherID, sectionID, true); err != nil {
+			return err
+		}
+
+		existing, err := tx.ListComponents(ctx, []uint{sectionID})
+		if err != nil {
+			return err
+		}
+		enrollments, err := tx.ListEnrolled(ctx, sectionID)
+		if err != nil {
+			return err
+		}
+		scores, err := tx.ListScores(ctx, enrollmentIDs(enrollments))
+		if err != nil {
+			return err
+		}
+		if errs := checkKeptComponents(components, existing, scores); len(errs) > 0 {
+			return errs
+		}
+
+		if err := tx.ReplaceComponents(ctx, sectionID, components); err != nil {
+			return err
+		}
+		return recomputeFinalScores(ctx, tx, sectionID, enrollments)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapToComponentDTOs(components), nil
+}
+
+func (s *GradebookService) GetGradebook(ctx context.Context, teacherID, sectionID uint) (*contracts.GradebookDTO, error) {
+	section, err := s.teacherSection(ctx, s.gradebook, teacherID, sectionID, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.gradebookDTO(ctx, s.gradebook, section)
+}
+
+// RecordScores applies a batch of score entries to a section. The batch is
+// applied in full or not at all. Components fed by an assignment take their
+// scores from it and cannot be scored here.
+func (s *GradebookService) RecordScores(ctx context.Context, teacherID, sectionID uint, input contracts.BulkScoresInput) (*contracts.GradebookDTO, error) {
+	switch {
+	case len(input.Scores) == 0:
+		return nil, contracts.ValidationErrors{{Field: "scores", Message: "at least one score is required"}}
+	case len(input.Scores) > maxScoresPerRequest:
+		return nil, contracts.ValidationErrors{{Field: "scores", Message: fmt.Sprintf("at most %d scores per request", maxScoresPerRequest)}}
+	}
+
+	var dto *contracts.GradebookDTO
+	err := s.gradebook.Transaction(ctx, func(tx repositories.GradebookRepository) error {
+		section, err := s.teacherSection(ctx, tx, teacherID, sectionID, true)
+		if err != nil {
+			return err
+		}
+
+		components, err := tx.ListComponents(ctx, []uint{sectionID})
+		if err != nil {
+			return err
+		}
+		enrollments, err := tx.ListEnrolled(ctx, sectionID)
+		if err != nil {
+			return err
+		}
+
+		fed, err := tx.ListFedComponents(ctx, sectionID)
+		if err != nil {
+			return err
+		}
+
+		byComponent := make(map[uint]models.AssessmentComponent, len(components))
+		for _, c := range components {
+			byComponent[c.ID] = c
+		}
+		fedBy := make(map[uint]bool, len(fed))
+		for _, id := range fed {
+			fedBy[id] = true
+		}
+		byStudent := make(map[uint]models.Enrollment, len(enrollments))
+		for _, e := range enrollments {
+			byStudent[e.StudentID] = e
+		}
+
+		type key struct{ student, component uint }
+		seen := map[key]bool{}
+		var errs contracts.ValidationErrors
+		for i, entry := range input.Scores {
+			field := fmt.Sprintf("scores[%d]", i)
+			component, ok := byComponent[entry.ComponentID]
+			if !ok {
+				errs = append(errs, contracts.ValidationError{Field: field + ".component_id", Message: "component does not belong to this section"})
+			} else if fedBy[entry.ComponentID] {
+				errs = append(errs, contracts.ValidationError{Field: field + ".component_id", Message: "component is fed by an assignment; grade the assignment instead"})
+			}
+			if _, ok := byStudent[entry.StudentID]; !ok {
+				errs = append(errs, contracts.ValidationError{Field: field + ".student_id", Message: "student is not enrolled in this section"})
+			}
+			if seen[key{entry.StudentID, entry.ComponentID}] {
+				errs = append(errs, contracts.ValidationError{Field: field, Message: "score is given more than once"})
+			}
+			seen[key{entry.StudentID, entry.ComponentID}] = true
+			if ok && entry.Points != nil && (math.IsNaN(*entry.Points) || *entry.Points < 0 || *entry.Points > component.MaxPoints) {
+				errs = append(errs, contracts.ValidationError{Field: field + ".points", Message: fmt.Sprintf("points must be between 0 and %g", component.MaxPoints)})
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+
+		for _, entry := range input.Scores {
+			enrollmentID := byStudent[entry.StudentID].ID
+			if entry.Points == nil {
+				if err := tx.DeleteScore(ctx, entry.ComponentID, enrollmentID); err != nil {
+					return err
+				}
+				continue
+			}
+			gradedBy := teacherID
+			score := &models.AssessmentScore{
+				ComponentID:  entry.ComponentID,
+				EnrollmentID: enrollmentID,
+				Points:       *entry.Points,
+				GradedByID:   &gradedBy,
+			}
+			if err := tx.UpsertScore(ctx, score); err != nil {
+				return err
+			}
+		}
+
+		if err := recomputeFinalScores(ctx, tx, sectionID, enrollments); err != nil {
+			return err
+		}
+		dto, err = s.gradebookDTO(ctx, tx, section)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// RecordAssignmentScores copies assignment scores into a component of a
+// section the teacher teaches, within the caller's transaction tx. shares
+// maps student IDs to the fraction of full marks they earned, which is
+// scaled to the component's max points; students no longer enrolled are
+// skipped. With replace, students missing from shares lose their score in
+// the component. Final marks are recomputed.
+func (s *GradebookService) RecordAssignmentScores(ctx context.Context, tx repositories.GradebookRepository, teacherID, sectionID, componentID uint, shares map[uint]float64, replace bool) error {
+	if _, err := s.teacherSection(ctx, tx, teacherID, sectionID, true); err != nil {
+		return err
+	}
+	component, err := sectionComponent(ctx, tx, sectionID, componentID)
+	if err != nil {
+		return err
+	}
+
+	enrollments, err := tx.ListEnrolled(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	for _, e := range enrollments {
+		share, ok := shares[e.StudentID]
+		if !ok {
+			if replace {
+				if err := tx.DeleteScore(ctx, component.ID, e.ID); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		gradedBy := teacherID
+		score := &models.AssessmentScore{
+			ComponentID:  component.ID,
+			EnrollmentID: e.ID,
+			Points:       roundScore(math.Min(1, math.Max(0, share)) * component.MaxPoints),
+			GradedByID:   &gradedBy,
+		}
+		if err := tx.UpsertScore(ctx, score); err != nil {
+			return err
+		}
+	}
+	return recomputeFinalScores(ctx, tx, sectionID, enrollments)
+}
+
+// ClearAssignmentScore removes a student's score from a component fed by
+// an assignment, within the caller's transaction tx, once the grade it was
+// copied from is gone. The final mark is recomputed. There is nothing to
+// clear when the section or component no longer exists.
+func (s *GradebookService) ClearAssignmentScore(ctx context.Context, tx repositories.GradebookRepository, sectionID, componentID, studentID uint) error {
+	if _, err := tx.LockSection(ctx, sectionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	enrollments, err := tx.ListEnrolled(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	for _, e := range enrollments {
+		if e.StudentID != studentID {
+			continue
+		}
+		if err := tx.DeleteScore(ctx, componentID, e.ID); err != nil {
+			return err
+		}
+		return recomputeFinalScores(ctx, tx, sectionID, []models.Enrollment{e})
+	}
+	return nil
+}
+
+// sectionComponent returns the section's component with the given ID.
+func sectionComponent(ctx context.Context, repo repositories.GradebookRepository, sectionID, componentID uint) (*models.AssessmentComponent, error) {
+	components, err := repo.ListComponents(ctx, []uint{sectionID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range components {
+		if components[i].ID == componentID {
+			return &components[i], nil
+		}
+	}
+	return nil, contracts.ValidationErrors{{Field: "gradebook_component_id", Message: "component does not belong to this section"}}
+}
+
+// ListStudentGrades returns the student's marks in every section they are
+// enrolled in, optionally limited to a term (see resolveTermParam).
+func (s *GradebookService) ListStudentGrades(ctx context.Context, studentID uint, term string) ([]contracts.SectionGradeDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.enrollments.ListByStudent(ctx, studentID, termID)
+	if err != nil {
+		return nil, err
+	}
+	enrollments := make([]models.Enrollment, 0, len(all))
+	sectionIDs := make([]uint, 0, len(all))
+	for _, e := range all {
+		if e.Status == models.EnrollmentEnrolled {
+			enrollments = append(enrollments, e)
+			sectionIDs = append(sectionIDs, e.SectionID)
+		}
+	}
+
+	components, err := s.gradebook.ListComponents(ctx, sectionIDs)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := s.gradebook.ListScores(ctx, enrollmentIDs(enrollments))
+	if err != nil {
+		return nil, err
+	}
+	bands, err := s.scale.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bySection := map[uint][]models.AssessmentComponent{}
+	for _, c := range components {
+		bySection[c.SectionID] = append(bySection[c.SectionID], c)
+	}
+	points := scoresByEnrollment(scores)
+
+	grades := make([]contracts.SectionGradeDTO, 0, len(enrollments))
+	for _, e := range enrollments {
+		sectionComponents := bySection[e.SectionID]
+		current, final := computeMarks(sectionComponents, points[e.ID])
+
+		dto := contracts.SectionGradeDTO{
+			SectionID:    e.SectionID,
+			Components:   make([]contracts.GradedComponentDTO, 0, len(sectionComponents)),
+			CurrentScore: current,
+			FinalScore:   final,
+			Letter:       letterFor(bands, final),
+		}
+		if e.Section != nil {
+			dto.SectionCode = e.Section.Code
+			if e.Section.Subject != nil {
+				dto.SubjectName = e.Section.Subject.Name
+			}
+			if e.Section.Term != nil {
+				dto.TermName = e.Section.Term.Name
+			}
+		}
+		for _, c := range sectionComponents {
+			graded := contracts.GradedComponentDTO{ComponentDTO: mapToComponentDTO(c)}
+			if p, ok := points[e.ID][c.ID]; ok {
+				graded.Points = &p
+			}
+			dto.Components = append(dto.Components, graded)
+		}
+		grades = append(grades, dto)
+	}
+	return grades, nil
+}
+
+// teacherSection loads a section and checks that the user may grade it.
+// Besides the subject's teachers, that includes the section's own teachers:
+// since sections have teachers of their own, those are the ones actually
+// running the class, and they also grade its assignments and quizzes.
+func (s *GradebookService) teacherSection(ctx context.Context, repo repositories.GradebookRepository, teacherID, sectionID uint, lock bool) (*models.CourseSection, error) {
+	find := repo.FindSection
+	if lock {
+		find = repo.LockSection
+	}
+	section, err := find(ctx, sectionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrSectionNotFound
+		}
+		return nil, err
+	}
+
+	if !teachesSection(section, teacherID) {
+		return nil, contracts.ErrNotSectionTeacher
+	}
+	return section, nil
+}
+
+func (s *GradebookService) gradebookDTO(ctx context.Context, repo repositories.GradebookRepository, section *models.CourseSection) (*contracts.GradebookDTO, error) {
+	components, err := repo.ListComponents(ctx, []uint{section.ID})
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := repo.ListEnrolled(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := repo.ListScores(ctx, enrollmentIDs(enrollments))
+	if err != nil {
+		return nil, err
+	}
+	bands, err := s.scale.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := &contracts.GradebookDTO{
+		SectionID:   section.ID,
+		SectionCode: section.Code,
+		Components:  mapToComponentDTOs(components),
+		Students:    make([]contracts.StudentGradeDTO, 0, len(enrollments)),
+	}
+	if section.Subject != nil {
+		dto.SubjectName = section.Subject.Name
+	}
+	if section.Term != nil {
+		dto.TermName = section.Term.Name
+	}
+
+	points := scoresByEnrollment(scores)
+	for _, e := range enrollments {
+		current, final := computeMarks(components, points[e.ID])
+		row := contracts.StudentGradeDTO{
+			StudentID:    e.StudentID,
+			Scores:       make([]contracts.ComponentScoreDTO, 0, len(components)),
+			CurrentScore: current,
+			FinalScore:   final,
+			Letter:       letterFor(bands, final),
+		}
+		if e.Student != nil {
+			row.Name = e.Student.Name
+			row.Email = e.Student.Email
+		}
+		for _, c := range components {
+			score := contracts.ComponentScoreDTO{ComponentID: c.ID}
+			if p, ok := points[e.ID][c.ID]; ok {
+				score.Points = &p
+			}
+			row.Scores = append(row.Scores, score)
+		}
+		dto.Students = append(dto.Students, row)
+	}
+	return dto, nil
+}
+
+// recomputeFinalScores stores the final mark of every enrollment, or clears
+// it while some component is still ungraded.
+func recomputeFinalScores(ctx context.Context, tx repositories.GradebookRepository, sectionID uint, enrollments []models.Enrollment) error {
+	components, err := tx.ListComponents(ctx, []uint{sectionID})
+	if err != nil {
+		return err
+	}
+	scores, err := tx.ListScores(ctx, enrollmentIDs(enrollments))
+	if err != nil {
+		return err
+	}
+
+	points := scoresByEnrollment(scores)
+	for _, e := range enrollments {
+		_, final := computeMarks(components, points[e.ID])
+		if sameScore(e.FinalScore, final) {
+			continue
+		}
+		if err := tx.SetFinalScore(ctx, e.ID, final); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// computeMarks returns the weighted mark over the graded components,
+// rescaled to percent, and the final mark once every component is graded.
+func computeMarks(components []models.AssessmentComponent, points map[uint]float64) (current, final *float64) {
+	var earned, graded float64
+	complete := len(components) > 0
+	for _, c := range components {
+		p, ok := points[c.ID]
+		if !ok {
+			complete = false
+			continue
+		}
+		earned += c.Weight * p / c.MaxPoints
+		graded += c.Weight
+	}
+	if graded == 0 {
+		return nil, nil
+	}
+
+	cur := roundScore(earned / graded * 100)
+	current = &cur
+	if complete {
+		fin := roundScore(earned)
+		final = &fin
+	}
+	return current, final
+}
+
+func buildComponents(input contracts.ComponentsInput) ([]models.AssessmentComponent, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	if len(input.Components) == 0 {
+		return nil, contracts.ValidationErrors{{Field: "components", Message: "at least one component is required"}}
+	}
+
+	names := map[string]bool{}
+	ids := map[uint]bool{}
+	var total float64
+	components := make([]models.AssessmentComponent, 0, len(input.Components))
+	for i, c := range input.Components {
+		field := fmt.Sprintf("components[%d]", i)
+		name := strings.TrimSpace(c.Name)
+		switch {
+		case name == "":
+			errs = append(errs, contracts.ValidationError{Field: field + ".name", Message: "name is required"})
+		case len(name) > maxComponentNameLength:
+			errs = append(errs, contracts.ValidationError{Field: field + ".name", Message: "name is too long"})
+		case names[strings.ToLower(name)]:
+			errs = append(errs, contracts.ValidationError{Field: field + ".name", Message: "name is used more than once"})
+		}
+		names[strings.ToLower(name)] = true
+
+		if c.ID != 0 {
+			if ids[c.ID] {
+				errs = append(errs, contracts.ValidationError{Field: field + ".id", Message: "component is listed more than once"})
+			}
+			ids[c.ID] = true
+		}
+
+		if math.IsNaN(c.Weight) || c.Weight <= 0 || c.Weight > 100 {
+			errs = append(errs, contracts.ValidationError{Field: field + ".weight", Message: "weight must be greater than 0 and at most 100"})
+		}
+		total += c.Weight
+
+		maxPoints := c.MaxPoints
+		if maxPoints == 0 {
+			maxPoints = defaultComponentPoints
+		}
+		if math.IsNaN(maxPoints) || maxPoints < 0 {
+			errs = append(errs, contracts.ValidationError{Field: field + ".max_points", Message: "max_points must be positive"})
+		}
+
+		components = append(components, models.AssessmentComponent{
+			ID:        c.ID,
+			Name:      name,
+			Weight:    c.Weight,
+			MaxPoints: maxPoints,
+			Position:  i,
+		})
+	}
+	if len(errs) == 0 && math.Abs(total-100) > weightTolerance {
+		errs = append(errs, contracts.ValidationError{Field: "components", Message: fmt.Sprintf("weights must add up to 100, got %g", roundScore(total))})
+	}
+	return components, errs
+}
+
+// checkKeptComponents verifies that kept component IDs belong to the section
+// and that lowering max_points does not leave recorded scores out of range.
+func checkKeptComponents(components, existing []models.AssessmentComponent, scores []models.AssessmentScore) contracts.ValidationErrors {
+	owned := make(map[uint]bool, len(existing))
+	for _, c := range existing {
+		owned[c.ID] = true
+	}
+	highest := map[uint]float64{}
+	for _, sc := range scores {
+		highest[sc.ComponentID] = math.Max(highest[sc.ComponentID], sc.Points)
+	}
+
+	var errs contracts.ValidationErrors
+	for i, c := range components {
+		if c.ID == 0 {
+			continue
+		}
+		field := fmt.Sprintf("components[%d]", i)
+		if !owned[c.ID] {
+			errs = append(errs, contracts.ValidationError{Field: field + ".id", Message: "component does not belong to this section"})
+			continue
+		}
+		if highest[c.ID] > c.MaxPoints {
+			errs = append(errs, contracts.ValidationError{Field: field + ".max_points", Message: fmt.Sprintf("a recorded score of %g exceeds max_points", highest[c.ID])})
+		}
+	}
+	return errs
+}
+
+func scoresByEnrollment(scores []models.AssessmentScore) map[uint]map[uint]float64 {
+	points := map[uint]map[uint]float64{}
+	for _, sc := range scores {
+		if points[sc.EnrollmentID] == nil {
+			points[sc.EnrollmentID] = map[uint]float64{}
+		}
+		points[sc.EnrollmentID][sc.ComponentID] = sc.Points
+	}
+	return points
+}
+
+func enrollmentIDs(enrollments []models.Enrollment) []uint {
+	ids := make([]uint, 0, len(enrollments))
+	for _, e := range enrollments {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func sameScore(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func mapToComponentDTOs(components []models.AssessmentComponent) []contracts.ComponentDTO {
+	dtos := make([]contracts.ComponentDTO, 0, len(components))
+	for _, c := range components {
+		dtos = append(dtos, mapToComponentDTO(c))
+	}
+	return dtos
+}
+
+func mapToComponentDTO(c models.AssessmentComponent) contracts.ComponentDTO {
+	return contracts.ComponentDTO{ID: c.ID, Name: c.Name, Weight: c.Weight, MaxPoints: c.MaxPoints}
+}
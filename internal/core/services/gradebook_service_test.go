@@ -0,0 +1,200 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+func TestComputeMarks(t *testing.T) {
+	// Midterm 30% out of 50 points, final 40% and labs 30% out of 100.
+	components := []models.AssessmentComponent{
+		{ID: 1, Weight: 30, MaxPoints: 50},
+		{ID: 2, Weight: 40, MaxPoints: 100},
+		{ID: 3, Weight: 30, MaxPoints: 100},
+	}
+	tests := []struct {
+		name          string
+		components    []models.AssessmentComponent
+		points        map[uint]float64
+		current, want *float64
+	}{
+		{name: "nothing graded", components: components, points: map[uint]float64{}},
+		{name: "no components", points: map[uint]float64{1: 10}},
+		{
+			name:       "one component graded",
+			components: components,
+			points:     map[uint]float64{1: 40},
+			current:    float(80),
+		},
+		{
+			name:       "part graded is rescaled to percent",
+			components: components,
+			points:     map[uint]float64{1: 50, 2: 50},
+			// 30 + 20 of 70 weighted points.
+			current: float(71.43),
+		},
+		{
+			name:       "all graded",
+			components: components,
+			points:     map[uint]float64{1: 45, 2: 80, 3: 90},
+			current:    float(86),
+			want:       float(86),
+		},
+		{
+			name:       "zero points still count as graded",
+			components: components,
+			points:     map[uint]float64{1: 0, 2: 0, 3: 0},
+			current:    float(0),
+			want:       float(0),
+		},
+		{
+			name:       "full marks",
+			components: components,
+			points:     map[uint]float64{1: 50, 2: 100, 3: 100},
+			current:    float(100),
+			want:       float(100),
+		},
+		{
+			name:       "scores of unknown components are ignored",
+			components: components,
+			points:     map[uint]float64{1: 50, 2: 100, 3: 100, 9: 0},
+			current:    float(100),
+			want:       float(100),
+		},
+		{
+			name: "thirds",
+			components: []models.AssessmentComponent{
+				{ID: 1, Weight: 33.33, MaxPoints: 100},
+				{ID: 2, Weight: 33.33, MaxPoints: 100},
+				{ID: 3, Weight: 33.34, MaxPoints: 100},
+			},
+			points:  map[uint]float64{1: 70, 2: 80, 3: 90},
+			current: float(80),
+			want:    float(80),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, final := computeMarks(tt.components, tt.points)
+			if !sameScore(current, tt.current) {
+				t.Errorf("current = %v, want %v", formatPoints(current), formatPoints(tt.current))
+			}
+			if !sameScore(final, tt.want) {
+				t.Errorf("final = %v, want %v", formatPoints(final), formatPoints(tt.want))
+			}
+		})
+	}
+}
+
+func TestBuildComponents(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []contracts.ComponentInput
+		errField   string
+	}{
+		{
+			name: "weights adding up to 100",
+			components: []contracts.ComponentInput{
+				{Name: "Midterm", Weight: 30}, {Name: "Final", Weight: 40}, {Name: "Labs", Weight: 30, MaxPoints: 20},
+			},
+		},
+		{
+			name: "thirds within the tolerance",
+			components: []contracts.ComponentInput{
+				{Name: "A", Weight: 33.33}, {Name: "B", Weight: 33.33}, {Name: "C", Weight: 33.34},
+			},
+		},
+		{name: "none", errField: "components"},
+		{
+			name:       "weights short of 100",
+			components: []contracts.ComponentInput{{Name: "Midterm", Weight: 30}, {Name: "Final", Weight: 60}},
+			errField:   "components",
+		},
+		{
+			name:       "weights over 100",
+			components: []contracts.ComponentInput{{Name: "Midterm", Weight: 50}, {Name: "Final", Weight: 60}},
+			errField:   "components",
+		},
+		{
+			name:       "zero weight",
+			components: []contracts.ComponentInput{{Name: "Final", Weight: 100}, {Name: "Bonus", Weight: 0}},
+			errField:   "components[1].weight",
+		},
+		{
+			name:       "weight that is not a number",
+			components: []contracts.ComponentInput{{Name: "Final", Weight: math.NaN()}},
+			errField:   "components[0].weight",
+		},
+		{
+			name:       "negative max points",
+			components: []contracts.ComponentInput{{Name: "Final", Weight: 100, MaxPoints: -10}},
+			errField:   "components[0].max_points",
+		},
+		{
+			name:       "names differing in case",
+			components: []contracts.ComponentInput{{Name: "Labs", Weight: 50}, {Name: " labs ", Weight: 50}},
+			errField:   "components[1].name",
+		},
+		{
+			name:       "blank name",
+			components: []contracts.ComponentInput{{Name: "  ", Weight: 100}},
+			errField:   "components[0].name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, errs := buildComponents(contracts.ComponentsInput{Components: tt.components})
+			if tt.errField == "" {
+				if len(errs) > 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				for i, c := range components {
+					if c.Position != i || c.MaxPoints <= 0 {
+						t.Errorf("component %d = %+v", i, c)
+					}
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != tt.errField {
+				t.Fatalf("errors = %v, want one for %s", errs, tt.errField)
+			}
+		})
+	}
+}
+
+func TestLetterFor(t *testing.T) {
+	// Ordered from the highest minimum score down, as stored.
+	bands := []models.GradeBand{
+		{Letter: "A", MinScore: 90, GradePoints: 4},
+		{Letter: "B", MinScore: 80, GradePoints: 3},
+		{Letter: "C", MinScore: 70, GradePoints: 2},
+		{Letter: "D", MinScore: 60, GradePoints: 1},
+		{Letter: "F", MinScore: 0, GradePoints: 0},
+	}
+	tests := []struct {
+		score *float64
+		want  string
+	}{
+		{score: nil, want: ""},
+		{score: float(100), want: "A"},
+		{score: float(90), want: "A"},
+		{score: float(89.99), want: "B"},
+		{score: float(80), want: "B"},
+		{score: float(60), want: "D"},
+		{score: float(59.99), want: "F"},
+		{score: float(0), want: "F"},
+	}
+	for _, tt := range tests {
+		if got := letterFor(bands, tt.score); got != tt.want {
+			t.Errorf("letterFor(%v) = %q, want %q", formatPoints(tt.score), got, tt.want)
+		}
+	}
+
+	// A mark below every band has no letter.
+	if got := letterFor(bands[:4], float(59)); got != "" {
+		t.Errorf("letterFor below the lowest band = %q, want none", got)
+	}
+}
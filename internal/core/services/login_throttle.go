@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+const (
+	loginFailureWindow    = 24 * time.Hour
+	loginRecentFailures   = 20
+	defaultAccountLimit   = 5
+	defaultIPLimit        = 20
+	defaultLockoutBase    = 30 * time.Second
+	defaultLockoutMaximum = time.Hour
+)
+
+// loginThrottle counts failed logins per account and per client IP in Redis.
+// Once a counter reaches its threshold the account or IP is locked, and each
+// further failure doubles the lock duration up to a maximum. Counters are
+// keyed by the submitted email, so unknown addresses are throttled exactly
+// like registered ones.
+type loginThrottle struct {
+	accountLimit int64
+	ipLimit      int64
+	baseLockout  time.Duration
+	maxLockout   time.Duration
+}
+
+type loginFailure struct {
+	IP string    `json:"ip"`
+	At time.Time `json:"at"`
+}
+
+func newLoginThrottle() *loginThrottle {
+	return &loginThrottle{
+		accountLimit: int64(envInt("LOGIN_LOCK_THRESHOLD", defaultAccountLimit)),
+		ipLimit:      int64(envInt("LOGIN_IP_LOCK_THRESHOLD", defaultIPLimit)),
+		baseLockout:  time.Duration(envInt("LOGIN_LOCK_BASE_SECONDS", int(defaultLockoutBase.Seconds()))) * time.Second,
+		maxLockout:   time.Duration(envInt("LOGIN_LOCK_MAX_SECONDS", int(defaultLockoutMaximum.Seconds()))) * time.Second,
+	}
+}
+
+func envInt(name string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+	return v
+}
+
+func accountFailKey(email string) string    { return fmt.Sprintf("login:fail:acct:%s", email) }
+func accountLockKey(email string) string    { return fmt.Sprintf("login:lock:acct:%s", email) }
+func ipFailKey(ip string) string            { return fmt.Sprintf("login:fail:ip:%s", ip) }
+func ipLockKey(ip string) string            { return fmt.Sprintf("login:lock:ip:%s", ip) }
+func recentFailuresKey(email string) string { return fmt.Sprintf("login:attempts:%s", email) }
+
+// Check returns a RateLimitError if either the account or the IP is locked.
+func (t *loginThrottle) Check(ctx context.Context, email, ip string) error {
+	if cache.RDB == nil {
+		return nil
+	}
+
+	keys := []string{accountLockKey(email)}
+	if ip != "" {
+		keys = append(keys, ipLockKey(ip))
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		ttl, err := cache.RDB.PTTL(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	if retryAfter > 0 {
+		return &contracts.RateLimitError{
+			Message:    "too many failed login attempts, try again later",
+			RetryAfter: retryAfter,
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt and locks the account and/or IP once
+// their thresholds are reached.
+func (t *loginThrottle) RecordFailure(ctx context.Context, email, ip string) error {
+	if cache.RDB == nil {
+		return nil
+	}
+
+	entry, err := json.Marshal(loginFailure{IP: ip, At: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	pipe := cache.RDB.TxPipeline()
+	accountCount := pipe.Incr(ctx, accountFailKey(email))
+	pipe.Expire(ctx, accountFailKey(email), loginFailureWindow)
+	pipe.LPush(ctx, recentFailuresKey(email), entry)
+	pipe.LTrim(ctx, recentFailuresKey(email), 0, loginRecentFailures-1)
+	pipe.Expire(ctx, recentFailuresKey(email), loginFailureWindow)
+	var ipCount *redis.IntCmd
+	if ip != "" {
+		ipCount = pipe.Incr(ctx, ipFailKey(ip))
+		pipe.Expire(ctx, ipFailKey(ip), loginFailureWindow)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	if d := t.lockoutFor(accountCount.Val(), t.accountLimit); d > 0 {
+		if err := cache.RDB.Set(ctx, accountLockKey(email), 1, d).Err(); err != nil {
+			return err
+		}
+	}
+	if ipCount != nil {
+		if d := t.lockoutFor(ipCount.Val(), t.ipLimit); d > 0 {
+			if err := cache.RDB.Set(ctx, ipLockKey(ip), 1, d).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reset clears the account lock and failure counter, e.g. after a successful
+// login. IP counters are left alone so that logging into one account cannot
+// be used to reset them.
+func (t *loginThrottle) Reset(ctx context.Context, email string) error {
+	if cache.RDB == nil {
+		return nil
+	}
+	return cache.RDB.Del(ctx, accountFailKey(email), accountLockKey(email)).Err()
+}
+
+// Status describes the lock state and recent failures of an account.
+func (t *loginThrottle) Status(ctx context.Context, email string) (*contracts.LoginStatusDTO, error) {
+	status := &contracts.LoginStatusDTO{RecentFailures: []contracts.LoginAttemptDTO{}}
+	if cache.RDB == nil {
+		return status, nil
+	}
+
+	count, err := cache.RDB.Get(ctx, accountFailKey(email)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	status.FailedAttempts = count
+
+	ttl, err := cache.RDB.PTTL(ctx, accountLockKey(email)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		status.Locked = true
+		status.RetryAfterSeconds = int(ttl.Round(time.Second).Seconds())
+	}
+
+	entries, err := cache.RDB.LRange(ctx, recentFailuresKey(email), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range entries {
+		var f loginFailure
+		if json.Unmarshal([]byte(raw), &f) != nil {
+			continue
+		}
+		status.RecentFailures = append(status.RecentFailures, contracts.LoginAttemptDTO{IP: f.IP, At: f.At})
+	}
+	return status, nil
+}
+
+// lockoutFor returns how long to lock after count failures, doubling for
+// every failure past the limit.
+func (t *loginThrottle) lockoutFor(count, limit int64) time.Duration {
+	if count < limit {
+		return 0
+	}
+	d := t.baseLockout
+	for i := limit; i < count; i++ {
+		d *= 2
+		if d >= t.maxLockout {
+			return t.maxLockout
+		}
+	}
+	return d
+}
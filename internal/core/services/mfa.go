@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/auth"
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+const (
+	recoveryCodeCount = 10
+	totpReplayWindow  = 3 * time.Minute
+)
+
+// LoginMFA completes a two-step login by exchanging an mfa_pending token and a
+// TOTP or recovery code for access and refresh tokens.
+func (s *AuthService) LoginMFA(ctx context.Context, input contracts.MFALoginInput) (*contracts.AuthResponse, error) {
+	claims, err := auth.ParseMFAPendingToken(strings.TrimSpace(input.MFAToken))
+	if err != nil {
+		return nil, contracts.ErrInvalidMFAToken
+	}
+	if err := auth.CheckRevocation(ctx, claims); err != nil {
+		return nil, contracts.ErrInvalidMFAToken
+	}
+
+	user, err := s.users.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if user.TOTPEnabledAt == nil {
+		return nil, contracts.ErrInvalidMFAToken
+	}
+
+	if err := s.checkSecondFactor(ctx, user, input.IP, input.Code, input.RecoveryCode); err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle.Reset(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	if err := auth.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+
+	resp, _, err := s.issueTokens(ctx, user.ID, "", true)
+	return resp, err
+}
+
+// EnrollTOTP starts enrollment by generating a new secret for the user. The
+// secret only takes effect once ConfirmTOTP has verified a code.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID uint) (*contracts.TOTPEnrollmentDTO, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, contracts.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	if err := s.users.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &contracts.TOTPEnrollmentDTO{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication after checking a code from the
+// authenticator app. It returns fresh recovery codes and tokens that satisfy
+// the MFA policy.
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID uint, input contracts.MFACodeInput) (*contracts.TOTPConfirmResponse, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, contracts.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, contracts.ErrMFANotStarted
+	}
+
+	if err := s.checkSecondFactor(ctx, user, input.IP, input.Code, ""); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	if err := s.users.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.generateRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := s.issueTokens(ctx, user.ID, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.TOTPConfirmResponse{AuthResponse: *resp, RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP turns two-factor authentication off and signs the user out of
+// every session. Users whose role requires a second factor cannot disable it.
+func (s *AuthService) DisableTOTP(ctx context.Context, userID uint, input contracts.MFACodeInput) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TOTPEnabledAt == nil {
+		return contracts.ErrMFANotEnabled
+	}
+	if user.Role != nil && auth.MFARequiredForRole(user.Role.Name) {
+		return contracts.ErrMFARequired
+	}
+
+	if err := s.checkSecondFactor(ctx, user, input.IP, input.Code, input.RecoveryCode); err != nil {
+		return err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	if err := s.users.Save(ctx, user); err != nil {
+		return err
+	}
+	if err := s.recoveryCodes.DeleteForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	// Tokens issued after the second factor still say so; sign the user
+	// out so that none outlive the factor.
+	return revokeUserSessions(ctx, s.refreshTokens, user.ID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uint, input contracts.MFACodeInput) (*contracts.RecoveryCodesDTO, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt == nil {
+		return nil, contracts.ErrMFANotEnabled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, input.IP, input.Code, ""); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.generateRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &contracts.RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+func (s *AuthService) findUser(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkSecondFactor verifies a TOTP or recovery code behind the login
+// throttle: locked accounts and IPs are refused, and wrong codes count as
+// failed logins.
+func (s *AuthService) checkSecondFactor(ctx context.Context, user *models.User, ip, code, recoveryCode string) error {
+	if err := s.throttle.Check(ctx, user.Email, ip); err != nil {
+		return err
+	}
+	ok, err := s.verifySecondFactor(ctx, user, code, recoveryCode)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err := s.throttle.RecordFailure(ctx, user.Email, ip); err != nil {
+			return err
+		}
+		return contracts.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// verifySecondFactor checks a TOTP code or, failing that, consumes a recovery
+// code. A TOTP code is accepted only once.
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *models.User, code, recoveryCode string) (bool, error) {
+	if code = strings.TrimSpace(code); code != "" {
+		step, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return claimTOTPStep(ctx, user.ID, step)
+	}
+
+	if recoveryCode = auth.NormalizeRecoveryCode(recoveryCode); recoveryCode != "" {
+		return s.recoveryCodes.Consume(ctx, user.ID, auth.HashToken(recoveryCode))
+	}
+	return false, nil
+}
+
+func (s *AuthService) generateRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	}
+
+	if err := s.recoveryCodes.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// claimTOTPStep records that the user has used the code for a time step and
+// reports false if it had already been used.
+func claimTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	if cache.RDB == nil {
+		return true, nil
+	}
+	key := fmt.Sprintf("mfa:totp_step:%d:%d", userID, step)
+	return cache.RDB.SetNX(ctx, key, 1, totpReplayWindow).Result()
+}
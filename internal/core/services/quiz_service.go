@@ -0,0 +1,1187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+const (
+	maxQuizTitleLength     = 200
+	maxQuizTextLength      = 5000
+	maxQuizQuestions       = 200
+	maxQuizOptions         = 20
+	maxQuizQuestionPoints  = 1000
+	maxQuizTimeLimit       = 24 * 60
+	maxQuizAttempts        = 100
+	maxAcceptedAnswers     = 50
+	maxShortAnswerLength   = 2000
+	maxAcceptedAnswerChars = 500
+
+	// quizAnswerGrace absorbs network latency: answers arriving this soon
+	// after an attempt's deadline still count.
+	quizAnswerGrace = 10 * time.Second
+	// discriminationGroup is the share of students in each of the top and
+	// bottom groups compared by the discrimination index.
+	discriminationGroup = 0.27
+)
+
+// QuizService runs online quizzes. Attempts are timed by the server: an
+// attempt's deadline is fixed when it starts, answers arriving after it are
+// rejected, and an attempt left open past its deadline is submitted with
+// the answers saved in time the next time anyone looks at it. Objective
+// questions are graded on submission; short answers without accepted
+// answers wait for the teacher.
+type QuizService struct {
+	quizzes  repositories.QuizRepository
+	sections repositories.SectionRepository
+	subjects repositories.SubjectRepository
+	terms    repositories.TermRepository
+}
+
+func NewQuizService(
+	quizzes repositories.QuizRepository,
+	sections repositories.SectionRepository,
+	subjects repositories.SubjectRepository,
+	terms repositories.TermRepository,
+) *QuizService {
+	return &QuizService{quizzes: quizzes, sections: sections, subjects: subjects, terms: terms}
+}
+
+// ListTeacherQuizzes lists the quizzes of the sections and subjects a
+// teacher teaches, optionally limited to a term (see resolveTermParam).
+func (s *QuizService) ListTeacherQuizzes(ctx context.Context, teacherID uint, term string) ([]contracts.QuizDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+	quizzes, err := s.quizzes.List(ctx, repositories.QuizFilter{TermID: termID, TeacherID: teacherID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dtos := make([]contracts.QuizDTO, 0, len(quizzes))
+	for i := range quizzes {
+		dtos = append(dtos, *mapToQuizDTO(&quizzes[i], false, now))
+	}
+	return dtos, nil
+}
+
+// GetTeacherQuiz returns a quiz with its questions and answer key.
+func (s *QuizService) GetTeacherQuiz(ctx context.Context, teacherID, id uint) (*contracts.QuizDTO, error) {
+	quiz, err := s.teacherQuiz(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapToQuizDTO(quiz, true, time.Now()), nil
+}
+
+// CreateQuiz sets a quiz for a section the teacher teaches, or for every
+// section of a subject the teacher teaches.
+func (s *QuizService) CreateQuiz(ctx context.Context, teacherID uint, input contracts.QuizInput) (*contracts.QuizDTO, error) {
+	quiz := &models.Quiz{CreatedByID: &teacherID}
+	errs := applyQuizFields(quiz, input.Title, input.Description, input.Settings)
+	questions, questionErrs := buildQuizQuestions(input.Questions)
+	errs = append(errs, questionErrs...)
+	if (input.SectionID == nil) == (input.SubjectID == nil) {
+		errs = append(errs, contracts.ValidationError{Field: "section_id", Message: "exactly one of section_id and subject_id is required"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	quiz.Questions = questions
+
+	if input.SectionID != nil {
+		section, err := findSection(ctx, s.sections, *input.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		if !teachesSection(section, teacherID) {
+			return nil, contracts.ErrNotSectionTeacher
+		}
+		quiz.SectionID = &section.ID
+		quiz.SubjectID = section.SubjectID
+		quiz.TermID = section.TermID
+	} else {
+		subject, err := s.subjects.FindByID(ctx, *input.SubjectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, contracts.ErrSubjectNotFound
+			}
+			return nil, err
+		}
+		if !hasTeacher(subject.Teachers, teacherID) {
+			return nil, contracts.ErrNotSubjectTeacher
+		}
+		term := input.Term
+		if term == "" {
+			term = "current"
+		}
+		termID, err := resolveTermParam(ctx, s.terms, term)
+		if err != nil {
+			return nil, err
+		}
+		quiz.SubjectID = subject.ID
+		quiz.TermID = termID
+	}
+
+	if err := s.quizzes.Create(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return s.GetTeacherQuiz(ctx, teacherID, quiz.ID)
+}
+
+// UpdateQuiz replaces the title, description and settings of a quiz.
+// Attempts already started keep the deadline they started with.
+func (s *QuizService) UpdateQuiz(ctx context.Context, teacherID, id uint, input contracts.UpdateQuizInput) (*contracts.QuizDTO, error) {
+	quiz, err := s.teacherQuiz(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	if errs := applyQuizFields(quiz, input.Title, input.Description, input.Settings); len(errs) > 0 {
+		return nil, errs
+	}
+	if err := s.quizzes.Save(ctx, quiz); err != nil {
+		return nil, err
+	}
+	return mapToQuizDTO(quiz, true, time.Now()), nil
+}
+
+// ReplaceQuestions swaps the questions of a quiz nobody has attempted yet.
+func (s *QuizService) ReplaceQuestions(ctx context.Context, teacherID, id uint, input contracts.QuizQuestionsInput) (*contracts.QuizDTO, error) {
+	quiz, err := s.teacherQuiz(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	questions, errs := buildQuizQuestions(input.Questions)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	quiz.Questions = questions
+	if err := s.quizzes.ReplaceQuestions(ctx, quiz); err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, contracts.ErrQuizAttempted
+		}
+		return nil, err
+	}
+	return s.GetTeacherQuiz(ctx, teacherID, id)
+}
+
+// DeleteQuiz removes a quiz nobody has attempted yet.
+func (s *QuizService) DeleteQuiz(ctx context.Context, teacherID, id uint) error {
+	if _, err := s.teacherQuiz(ctx, teacherID, id); err != nil {
+		return err
+	}
+	if err := s.quizzes.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrQuizNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrQuizInUse
+		}
+		return err
+	}
+	return nil
+}
+
+// ListAttempts returns every attempt at a quiz of the teacher.
+func (s *QuizService) ListAttempts(ctx context.Context, teacherID, id uint) ([]contracts.QuizAttemptSummaryDTO, error) {
+	quiz, err := s.teacherQuiz(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishExpired(ctx, quiz, 0); err != nil {
+		return nil, err
+	}
+	attempts, err := s.quizzes.ListAttempts(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPoints := quiz.MaxPoints()
+	dtos := make([]contracts.QuizAttemptSummaryDTO, 0, len(attempts))
+	for i := range attempts {
+		dtos = append(dtos, mapToQuizAttemptSummaryDTO(&attempts[i], maxPoints))
+	}
+	return dtos, nil
+}
+
+// GetTeacherAttempt returns an attempt at one of the teacher's quizzes with
+// its answers and the answer key.
+func (s *QuizService) GetTeacherAttempt(ctx context.Context, teacherID, attemptID uint) (*contracts.QuizAttemptDTO, error) {
+	attempt, err := s.findAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.teacherQuiz(ctx, teacherID, attempt.QuizID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishIfExpired(ctx, quiz, attempt); err != nil {
+		return nil, err
+	}
+	return mapToQuizAttemptDTO(quiz, attempt, true), nil
+}
+
+// GradeAnswer sets the points of an answer in a submitted attempt, most
+// often a short answer that had no accepted answers to match. Automatic
+// grades can be overridden the same way. The attempt's score is set once
+// every answer is graded.
+func (s *QuizService) GradeAnswer(ctx context.Context, teacherID, answerID uint, input contracts.GradeAnswerInput) (*contracts.QuizAttemptDTO, error) {
+	answer, err := s.quizzes.FindAnswer(ctx, answerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrAnswerNotFound
+		}
+		return nil, err
+	}
+	attempt, err := s.findAttempt(ctx, answer.AttemptID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.teacherQuiz(ctx, teacherID, attempt.QuizID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishIfExpired(ctx, quiz, attempt); err != nil {
+		return nil, err
+	}
+	if !attempt.Submitted() {
+		return nil, contracts.ValidationErrors{{Field: "points", Message: "the attempt is still in progress"}}
+	}
+	question := quiz.Question(answer.QuestionID)
+	if math.IsNaN(input.Points) || input.Points < 0 || input.Points > question.Points {
+		return nil, contracts.ValidationErrors{{Field: "points", Message: fmt.Sprintf("points must be between 0 and %g", question.Points)}}
+	}
+
+	points := roundScore(input.Points)
+	if err := s.quizzes.Transaction(ctx, func(tx repositories.QuizRepository) error {
+		// The score is totalled from answers read under the lock, so that
+		// concurrent grades of the same attempt all count.
+		locked, err := tx.LockAttempt(ctx, attempt.ID)
+		if err != nil {
+			return err
+		}
+		answer := locked.Answer(answer.QuestionID)
+		answer.Points = &points
+		locked.Score = attemptScore(locked)
+		if err := tx.SaveAnswer(ctx, answer); err != nil {
+			return err
+		}
+		if err := tx.SaveScore(ctx, locked.ID, locked.Score); err != nil {
+			return err
+		}
+		attempt = locked
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return mapToQuizAttemptDTO(quiz, attempt, true), nil
+}
+
+// Statistics analyses the questions of a quiz from each student's first
+// submitted attempt; later attempts are left out because students have
+// seen the questions by then.
+func (s *QuizService) Statistics(ctx context.Context, teacherID, id uint) (*contracts.QuizStatisticsDTO, error) {
+	quiz, err := s.teacherQuiz(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishExpired(ctx, quiz, 0); err != nil {
+		return nil, err
+	}
+	attempts, err := s.quizzes.ListAttempts(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var first []*models.QuizAttempt
+	seen := map[uint]bool{}
+	for i := range attempts {
+		attempt := &attempts[i]
+		if attempt.Submitted() && !seen[attempt.StudentID] {
+			seen[attempt.StudentID] = true
+			first = append(first, attempt)
+		}
+	}
+	return quizStatistics(quiz, first), nil
+}
+
+// ListStudentQuizzes lists the quizzes set for the sections a student is
+// enrolled in, with the student's attempts.
+func (s *QuizService) ListStudentQuizzes(ctx context.Context, studentID uint, term string) ([]contracts.QuizDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+	quizzes, err := s.quizzes.List(ctx, repositories.QuizFilter{TermID: termID, StudentID: studentID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(quizzes))
+	for i := range quizzes {
+		ids[i] = quizzes[i].ID
+	}
+	attempts, err := s.quizzes.ListStudentAttempts(ctx, studentID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byQuiz := map[uint][]models.QuizAttempt{}
+	for _, attempt := range attempts {
+		byQuiz[attempt.QuizID] = append(byQuiz[attempt.QuizID], attempt)
+	}
+
+	dtos := make([]contracts.QuizDTO, 0, len(quizzes))
+	for i := range quizzes {
+		dto, err := s.mapToStudentQuizDTO(ctx, &quizzes[i], byQuiz[quizzes[i].ID])
+		if err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, *dto)
+	}
+	return dtos, nil
+}
+
+func (s *QuizService) GetStudentQuiz(ctx context.Context, studentID, id uint) (*contracts.QuizDTO, error) {
+	quiz, err := s.studentQuiz(ctx, studentID, id)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.quizzes.ListAttempts(ctx, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapToStudentQuizDTO(ctx, quiz, attempts)
+}
+
+// StartAttempt starts the student's next attempt at an open quiz. Its
+// deadline is fixed now: the time limit from now, or when the quiz closes
+// if that is sooner. Questions and options are shuffled for the attempt if
+// the quiz says so.
+func (s *QuizService) StartAttempt(ctx context.Context, studentID, id uint) (*contracts.QuizAttemptDTO, error) {
+	quiz, err := s.studentQuiz(ctx, studentID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishExpired(ctx, quiz, studentID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !quiz.Open(now) || len(quiz.Questions) == 0 {
+		return nil, contracts.ErrQuizNotOpen
+	}
+	attempts, err := s.quizzes.ListAttempts(ctx, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, attempt := range attempts {
+		if !attempt.Submitted() {
+			return nil, contracts.ErrAttemptInProgress
+		}
+	}
+	if quiz.MaxAttempts > 0 && len(attempts) >= quiz.MaxAttempts {
+		return nil, contracts.ErrNoAttemptsLeft
+	}
+
+	questions := make([]*models.QuizQuestion, len(quiz.Questions))
+	for i := range quiz.Questions {
+		questions[i] = &quiz.Questions[i]
+	}
+	if quiz.ShuffleQuestions {
+		rand.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	}
+	attempt := &models.QuizAttempt{
+		QuizID:     quiz.ID,
+		StudentID:  studentID,
+		Number:     len(attempts) + 1,
+		StartedAt:  now,
+		DeadlineAt: attemptDeadline(quiz, now),
+	}
+	order := make([]uint, len(questions))
+	for i, question := range questions {
+		order[i] = question.ID
+		options := make([]uint, len(question.Options))
+		for j, option := range question.Options {
+			options[j] = option.ID
+		}
+		if quiz.ShuffleOptions && question.Type != models.QuestionTrueFalse {
+			rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+		}
+		attempt.Answers = append(attempt.Answers, models.QuizAnswer{QuestionID: question.ID, OptionOrder: models.JoinIDs(options)})
+	}
+	attempt.QuestionOrder = models.JoinIDs(order)
+
+	if err := s.quizzes.CreateAttempt(ctx, attempt); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrAttemptInProgress
+		}
+		return nil, err
+	}
+	return mapToQuizAttemptDTO(quiz, attempt, false), nil
+}
+
+func (s *QuizService) GetStudentAttempt(ctx context.Context, studentID, attemptID uint) (*contracts.QuizAttemptDTO, error) {
+	quiz, attempt, err := s.studentAttempt(ctx, studentID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToQuizAttemptDTO(quiz, attempt, false), nil
+}
+
+// SaveAnswers saves answers of an attempt in progress. Once the deadline
+// has passed the attempt is submitted with the answers saved before it,
+// and the new ones are rejected.
+func (s *QuizService) SaveAnswers(ctx context.Context, studentID, attemptID uint, input contracts.QuizAnswersInput) (*contracts.QuizAttemptDTO, error) {
+	quiz, attempt, err := s.openAttempt(ctx, studentID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.quizzes.Transaction(ctx, func(tx repositories.QuizRepository) error {
+		// Re-check under the lock: the attempt may have been submitted
+		// since it was loaded.
+		locked, err := tx.LockAttempt(ctx, attempt.ID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if locked.Submitted() {
+			return submittedError(locked)
+		}
+		if locked.Expired(now.Add(-quizAnswerGrace)) {
+			return contracts.ErrTimeUp
+		}
+		answers, errs := applyQuizAnswers(quiz, locked, input, now)
+		if len(errs) > 0 {
+			return errs
+		}
+		for _, answer := range answers {
+			if err := tx.SaveAnswer(ctx, answer); err != nil {
+				return err
+			}
+		}
+		attempt = locked
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return mapToQuizAttemptDTO(quiz, attempt, false), nil
+}
+
+// SubmitAttempt saves the last answers of an attempt, if any, and submits
+// it for grading.
+func (s *QuizService) SubmitAttempt(ctx context.Context, studentID, attemptID uint, input contracts.QuizAnswersInput) (*contracts.QuizAttemptDTO, error) {
+	quiz, attempt, err := s.openAttempt(ctx, studentID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	finished, err := s.finish(ctx, quiz, attempt, now, false, func(locked *models.QuizAttempt) error {
+		if _, errs := applyQuizAnswers(quiz, locked, input, now); len(errs) > 0 {
+			return errs
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, submittedError(attempt)
+	}
+	return mapToQuizAttemptDTO(quiz, attempt, false), nil
+}
+
+func (s *QuizService) mapToStudentQuizDTO(ctx context.Context, quiz *models.Quiz, attempts []models.QuizAttempt) (*contracts.QuizDTO, error) {
+	dto := mapToQuizDTO(quiz, false, time.Now())
+	maxPoints := quiz.MaxPoints()
+	dto.Attempts = make([]contracts.QuizAttemptSummaryDTO, 0, len(attempts))
+	for i := range attempts {
+		attempt := &attempts[i]
+		if err := s.finishIfExpired(ctx, quiz, attempt); err != nil {
+			return nil, err
+		}
+		dto.Attempts = append(dto.Attempts, mapToQuizAttemptSummaryDTO(attempt, maxPoints))
+		if attempt.Score != nil && (dto.BestScore == nil || *attempt.Score > *dto.BestScore) {
+			dto.BestScore = attempt.Score
+		}
+	}
+	if quiz.MaxAttempts > 0 {
+		left := max(0, quiz.MaxAttempts-len(attempts))
+		dto.AttemptsLeft = &left
+	}
+	return dto, nil
+}
+
+// finishExpired submits the attempts at a quiz, of one student unless
+// studentID is 0, whose deadline has passed.
+func (s *QuizService) finishExpired(ctx context.Context, quiz *models.Quiz, studentID uint) error {
+	attempts, err := s.quizzes.ExpiredAttempts(ctx, quiz.ID, studentID, time.Now().Add(-quizAnswerGrace))
+	if err != nil {
+		return err
+	}
+	for i := range attempts {
+		if _, err := s.finish(ctx, quiz, &attempts[i], *attempts[i].DeadlineAt, true, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishIfExpired submits the attempt if its deadline has passed.
+func (s *QuizService) finishIfExpired(ctx context.Context, quiz *models.Quiz, attempt *models.QuizAttempt) error {
+	if attempt.Submitted() || !attempt.Expired(time.Now().Add(-quizAnswerGrace)) {
+		return nil
+	}
+	_, err := s.finish(ctx, quiz, attempt, *attempt.DeadlineAt, true, nil)
+	return err
+}
+
+// finish grades and submits an attempt, after update, if not nil, has
+// changed its answers. The attempt is reloaded under a lock first and
+// replaced by what was stored. finish reports false, leaving the stored
+// attempt alone, when someone else submitted it first.
+func (s *QuizService) finish(ctx context.Context, quiz *models.Quiz, attempt *models.QuizAttempt, at time.Time, auto bool, update func(locked *models.QuizAttempt) error) (bool, error) {
+	finished := false
+	err := s.quizzes.Transaction(ctx, func(tx repositories.QuizRepository) error {
+		locked, err := tx.LockAttempt(ctx, attempt.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Submitted() {
+			*attempt = *locked
+			return nil
+		}
+		if update != nil {
+			if err := update(locked); err != nil {
+				return err
+			}
+		}
+
+		for i := range locked.Answers {
+			answer := &locked.Answers[i]
+			answer.Points = gradeQuizAnswer(quiz.Question(answer.QuestionID), answer)
+		}
+		locked.SubmittedAt = &at
+		locked.AutoSubmitted = auto
+		locked.Score = attemptScore(locked)
+		if finished, err = tx.FinishAttempt(ctx, locked); err != nil || !finished {
+			return err
+		}
+		for i := range locked.Answers {
+			if err := tx.SaveAnswer(ctx, &locked.Answers[i]); err != nil {
+				return err
+			}
+		}
+		*attempt = *locked
+		return nil
+	})
+	return finished, err
+}
+
+// openAttempt loads one of the student's attempts that still takes
+// answers.
+func (s *QuizService) openAttempt(ctx context.Context, studentID, attemptID uint) (*models.Quiz, *models.QuizAttempt, error) {
+	quiz, attempt, err := s.studentAttempt(ctx, studentID, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.Submitted() {
+		return nil, nil, submittedError(attempt)
+	}
+	return quiz, attempt, nil
+}
+
+// submittedError tells a student why a submitted attempt takes no more
+// answers.
+func submittedError(attempt *models.QuizAttempt) error {
+	if attempt.AutoSubmitted {
+		return contracts.ErrTimeUp
+	}
+	return contracts.ErrAttemptSubmitted
+}
+
+// studentAttempt loads one of the student's attempts and its quiz,
+// submitting the attempt if its deadline has passed. Other students'
+// attempts are reported as missing.
+func (s *QuizService) studentAttempt(ctx context.Context, studentID, attemptID uint) (*models.Quiz, *models.QuizAttempt, error) {
+	attempt, err := s.findAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.StudentID != studentID {
+		return nil, nil, contracts.ErrAttemptNotFound
+	}
+	quiz, err := s.findQuiz(ctx, attempt.QuizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.finishIfExpired(ctx, quiz, attempt); err != nil {
+		return nil, nil, err
+	}
+	return quiz, attempt, nil
+}
+
+func (s *QuizService) findAttempt(ctx context.Context, id uint) (*models.QuizAttempt, error) {
+	attempt, err := s.quizzes.FindAttempt(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrAttemptNotFound
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (s *QuizService) findQuiz(ctx context.Context, id uint) (*models.Quiz, error) {
+	quiz, err := s.quizzes.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrQuizNotFound
+		}
+		return nil, err
+	}
+	return quiz, nil
+}
+
+// teacherQuiz loads a quiz the teacher may manage: one of a section they
+// teach, or a subject-wide one of a subject they teach.
+func (s *QuizService) teacherQuiz(ctx context.Context, teacherID, id uint) (*models.Quiz, error) {
+	quiz, err := s.findQuiz(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.Section != nil && !teachesSection(quiz.Section, teacherID) {
+		return nil, contracts.ErrNotSectionTeacher
+	}
+	if quiz.Section == nil && (quiz.Subject == nil || !hasTeacher(quiz.Subject.Teachers, teacherID)) {
+		return nil, contracts.ErrNotSubjectTeacher
+	}
+	return quiz, nil
+}
+
+// studentQuiz loads a quiz set for the student. Quizzes of other sections
+// are reported as missing.
+func (s *QuizService) studentQuiz(ctx context.Context, studentID, id uint) (*models.Quiz, error) {
+	quiz, err := s.findQuiz(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	enrolled, err := s.quizzes.IsEnrolled(ctx, studentID, quiz)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, contracts.ErrQuizNotFound
+	}
+	return quiz, nil
+}
+
+// attemptDeadline is when an attempt started at start has to be in.
+func attemptDeadline(quiz *models.Quiz, start time.Time) *time.Time {
+	var deadline *time.Time
+	if quiz.TimeLimitMinutes > 0 {
+		d := start.Add(time.Duration(quiz.TimeLimitMinutes) * time.Minute)
+		deadline = &d
+	}
+	if quiz.ClosesAt != nil && (deadline == nil || quiz.ClosesAt.Before(*deadline)) {
+		d := *quiz.ClosesAt
+		deadline = &d
+	}
+	return deadline
+}
+
+func applyQuizFields(quiz *models.Quiz, title, description string, settings contracts.QuizSettingsDTO) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	quiz.Title = strings.TrimSpace(title)
+	quiz.Description = strings.TrimSpace(description)
+	switch {
+	case quiz.Title == "":
+		errs = append(errs, contracts.ValidationError{Field: "title", Message: "title is required"})
+	case len(quiz.Title) > maxQuizTitleLength:
+		errs = append(errs, contracts.ValidationError{Field: "title", Message: "title is too long"})
+	}
+	if len(quiz.Description) > maxQuizTextLength {
+		errs = append(errs, contracts.ValidationError{Field: "description", Message: "description is too long"})
+	}
+	if settings.OpensAt != nil && settings.ClosesAt != nil && !settings.ClosesAt.After(*settings.OpensAt) {
+		errs = append(errs, contracts.ValidationError{Field: "settings.closes_at", Message: "closes_at must be after opens_at"})
+	}
+	if settings.TimeLimitMinutes < 0 || settings.TimeLimitMinutes > maxQuizTimeLimit {
+		errs = append(errs, contracts.ValidationError{Field: "settings.time_limit_minutes", Message: fmt.Sprintf("time_limit_minutes must be between 0 and %d", maxQuizTimeLimit)})
+	}
+	if settings.MaxAttempts < 0 || settings.MaxAttempts > maxQuizAttempts {
+		errs = append(errs, contracts.ValidationError{Field: "settings.max_attempts", Message: fmt.Sprintf("max_attempts must be between 0 and %d", maxQuizAttempts)})
+	}
+
+	quiz.OpensAt = settings.OpensAt
+	quiz.ClosesAt = settings.ClosesAt
+	quiz.TimeLimitMinutes = settings.TimeLimitMinutes
+	quiz.MaxAttempts = settings.MaxAttempts
+	quiz.ShuffleQuestions = settings.ShuffleQuestions
+	quiz.ShuffleOptions = settings.ShuffleOptions
+	return errs
+}
+
+func buildQuizQuestions(inputs []contracts.QuizQuestionInput) ([]models.QuizQuestion, contracts.ValidationErrors) {
+	switch {
+	case len(inputs) == 0:
+		return nil, contracts.ValidationErrors{{Field: "questions", Message: "at least one question is required"}}
+	case len(inputs) > maxQuizQuestions:
+		return nil, contracts.ValidationErrors{{Field: "questions", Message: fmt.Sprintf("at most %d questions are allowed", maxQuizQuestions)}}
+	}
+
+	var errs contracts.ValidationErrors
+	questions := make([]models.QuizQuestion, 0, len(inputs))
+	for i, input := range inputs {
+		field := fmt.Sprintf("questions[%d]", i)
+		question := models.QuizQuestion{Position: i, Type: input.Type, Prompt: strings.TrimSpace(input.Prompt), Points: input.Points}
+		switch {
+		case question.Prompt == "":
+			errs = append(errs, contracts.ValidationError{Field: field + ".prompt", Message: "prompt is required"})
+		case len(question.Prompt) > maxQuizTextLength:
+			errs = append(errs, contracts.ValidationError{Field: field + ".prompt", Message: "prompt is too long"})
+		}
+		if math.IsNaN(question.Points) || question.Points <= 0 || question.Points > maxQuizQuestionPoints {
+			errs = append(errs, contracts.ValidationError{Field: field + ".points", Message: fmt.Sprintf("points must be more than 0 and at most %d", maxQuizQuestionPoints)})
+		}
+
+		switch input.Type {
+		case models.QuestionSingleChoice, models.QuestionMultiChoice:
+			options, optionErrs := buildQuizOptions(field, input)
+			errs = append(errs, optionErrs...)
+			question.Options = options
+		case models.QuestionTrueFalse:
+			if input.Correct == nil {
+				errs = append(errs, contracts.ValidationError{Field: field + ".correct", Message: "correct is required"})
+				break
+			}
+			question.Options = []models.QuizOption{
+				{Position: 0, Text: "True", Correct: *input.Correct},
+				{Position: 1, Text: "False", Correct: !*input.Correct},
+			}
+		case models.QuestionNumeric:
+			if input.Answer == nil || math.IsNaN(*input.Answer) || math.IsInf(*input.Answer, 0) {
+				errs = append(errs, contracts.ValidationError{Field: field + ".answer", Message: "answer is required"})
+			}
+			if math.IsNaN(input.Tolerance) || math.IsInf(input.Tolerance, 0) || input.Tolerance < 0 {
+				errs = append(errs, contracts.ValidationError{Field: field + ".tolerance", Message: "tolerance must be 0 or more"})
+			}
+			question.Answer = input.Answer
+			question.Tolerance = input.Tolerance
+		case models.QuestionShortAnswer:
+			accepted := make([]string, 0, len(input.AcceptedAnswers))
+			for _, a := range input.AcceptedAnswers {
+				a = strings.Join(strings.Fields(a), " ")
+				switch {
+				case a == "":
+					continue
+				case len(a) > maxAcceptedAnswerChars:
+					errs = append(errs, contracts.ValidationError{Field: field + ".accepted_answers", Message: "accepted answer is too long"})
+				}
+				accepted = append(accepted, a)
+			}
+			if len(accepted) > maxAcceptedAnswers {
+				errs = append(errs, contracts.ValidationError{Field: field + ".accepted_answers", Message: fmt.Sprintf("at most %d accepted answers are allowed", maxAcceptedAnswers)})
+			}
+			question.AcceptedAnswers = strings.Join(accepted, "\n")
+		default:
+			errs = append(errs, contracts.ValidationError{Field: field + ".type", Message: "type must be single_choice, multi_choice, true_false, numeric or short_answer"})
+		}
+		questions = append(questions, question)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return questions, nil
+}
+
+func buildQuizOptions(field string, input contracts.QuizQuestionInput) ([]models.QuizOption, contracts.ValidationErrors) {
+	switch {
+	case len(input.Options) < 2:
+		return nil, contracts.ValidationErrors{{Field: field + ".options", Message: "at least two options are required"}}
+	case len(input.Options) > maxQuizOptions:
+		return nil, contracts.ValidationErrors{{Field: field + ".options", Message: fmt.Sprintf("at most %d options are allowed", maxQuizOptions)}}
+	}
+
+	var errs contracts.ValidationErrors
+	options := make([]models.QuizOption, 0, len(input.Options))
+	correct := 0
+	for j, o := range input.Options {
+		option := models.QuizOption{Position: j, Text: strings.TrimSpace(o.Text), Correct: o.Correct}
+		optionField := fmt.Sprintf("%s.options[%d].text", field, j)
+		switch {
+		case option.Text == "":
+			errs = append(errs, contracts.ValidationError{Field: optionField, Message: "text is required"})
+		case len(option.Text) > maxQuizTextLength:
+			errs = append(errs, contracts.ValidationError{Field: optionField, Message: "text is too long"})
+		}
+		if option.Correct {
+			correct++
+		}
+		options = append(options, option)
+	}
+	switch {
+	case input.Type == models.QuestionSingleChoice && correct != 1:
+		errs = append(errs, contracts.ValidationError{Field: field + ".options", Message: "exactly one option must be correct"})
+	case correct == 0:
+		errs = append(errs, contracts.ValidationError{Field: field + ".options", Message: "at least one option must be correct"})
+	}
+	return options, errs
+}
+
+// applyQuizAnswers copies the given answers into the attempt and returns
+// the ones that changed.
+func applyQuizAnswers(quiz *models.Quiz, attempt *models.QuizAttempt, input contracts.QuizAnswersInput, now time.Time) ([]*models.QuizAnswer, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	var changed []*models.QuizAnswer
+	for i, in := range input.Answers {
+		field := fmt.Sprintf("answers[%d]", i)
+		answer := attempt.Answer(in.QuestionID)
+		if answer == nil {
+			errs = append(errs, contracts.ValidationError{Field: field + ".question_id", Message: "question is not part of this quiz"})
+			continue
+		}
+		question := quiz.Question(in.QuestionID)
+
+		answered := false
+		switch question.Type {
+		case models.QuestionSingleChoice, models.QuestionMultiChoice, models.QuestionTrueFalse:
+			if question.Type != models.QuestionMultiChoice && len(in.OptionIDs) > 1 {
+				errs = append(errs, contracts.ValidationError{Field: field + ".option_ids", Message: "pick one option"})
+				continue
+			}
+			picked := map[uint]bool{}
+			var ids []uint
+			for _, id := range in.OptionIDs {
+				if question.Option(id) == nil {
+					errs = append(errs, contracts.ValidationError{Field: field + ".option_ids", Message: fmt.Sprintf("option %d is not part of this question", id)})
+					continue
+				}
+				if !picked[id] {
+					picked[id] = true
+					ids = append(ids, id)
+				}
+			}
+			answer.OptionIDs = models.JoinIDs(ids)
+			answered = len(ids) > 0
+		case models.QuestionNumeric:
+			if in.Number != nil && (math.IsNaN(*in.Number) || math.IsInf(*in.Number, 0)) {
+				errs = append(errs, contracts.ValidationError{Field: field + ".number", Message: "number must be finite"})
+				continue
+			}
+			answer.Number = in.Number
+			answered = in.Number != nil
+		case models.QuestionShortAnswer:
+			text := strings.TrimSpace(in.Text)
+			if len(text) > maxShortAnswerLength {
+				errs = append(errs, contracts.ValidationError{Field: field + ".text", Message: "answer is too long"})
+				continue
+			}
+			answer.Text = text
+			answered = text != ""
+		}
+		answer.AnsweredAt = nil
+		if answered {
+			answer.AnsweredAt = &now
+		}
+		changed = append(changed, answer)
+	}
+	return changed, errs
+}
+
+// gradeQuizAnswer returns the points an answer earns, or nil for short
+// answers the teacher has to grade. Multi-choice questions give partial
+// credit: every wrong pick cancels a right one.
+func gradeQuizAnswer(question *models.QuizQuestion, answer *models.QuizAnswer) *float64 {
+	share := 0.0
+	switch question.Type {
+	case models.QuestionSingleChoice, models.QuestionTrueFalse:
+		if picked := answer.Options(); len(picked) == 1 {
+			if option := question.Option(picked[0]); option != nil && option.Correct {
+				share = 1
+			}
+		}
+	case models.QuestionMultiChoice:
+		correct, right, wrong := 0, 0, 0
+		for _, option := range question.Options {
+			if option.Correct {
+				correct++
+			}
+		}
+		for _, id := range answer.Options() {
+			if option := question.Option(id); option != nil && option.Correct {
+				right++
+			} else if option != nil {
+				wrong++
+			}
+		}
+		if correct > 0 {
+			share = math.Max(0, float64(right-wrong)/float64(correct))
+		}
+	case models.QuestionNumeric:
+		if answer.Number != nil && question.Answer != nil &&
+			math.Abs(*answer.Number-*question.Answer) <= question.Tolerance+1e-9*math.Max(1, math.Abs(*question.Answer)) {
+			share = 1
+		}
+	case models.QuestionShortAnswer:
+		text := normalizeShortAnswer(answer.Text)
+		if text != "" && !question.AutoGraded() {
+			return nil
+		}
+		for _, accepted := range question.Accepted() {
+			if text != "" && normalizeShortAnswer(accepted) == text {
+				share = 1
+			}
+		}
+	}
+	points := roundScore(share * question.Points)
+	return &points
+}
+
+// normalizeShortAnswer ignores case and spacing when matching short
+// answers.
+func normalizeShortAnswer(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// attemptScore totals the points of an attempt once every answer is
+// graded.
+func attemptScore(attempt *models.QuizAttempt) *float64 {
+	total := 0.0
+	for _, answer := range attempt.Answers {
+		if answer.Points == nil {
+			return nil
+		}
+		total += *answer.Points
+	}
+	total = roundScore(total)
+	return &total
+}
+
+// quizStatistics works out the difficulty and discrimination index of
+// every question from the given attempts, one per student.
+func quizStatistics(quiz *models.Quiz, attempts []*models.QuizAttempt) *contracts.QuizStatisticsDTO {
+	dto := &contracts.QuizStatisticsDTO{
+		QuizID:   quiz.ID,
+		Students: len(attempts),
+		Items:    make([]contracts.QuizItemStatisticsDTO, 0, len(quiz.Questions)),
+	}
+
+	// Students are ranked by the points graded so far.
+	totals := make(map[uint]float64, len(attempts))
+	for _, attempt := range attempts {
+		for _, answer := range attempt.Answers {
+			if answer.Points != nil {
+				totals[attempt.ID] += *answer.Points
+			}
+		}
+	}
+	ranked := append([]*models.QuizAttempt(nil), attempts...)
+	sort.SliceStable(ranked, func(i, j int) bool { return totals[ranked[i].ID] > totals[ranked[j].ID] })
+	group := 0
+	if len(ranked) >= 2 {
+		group = max(1, int(math.Round(discriminationGroup*float64(len(ranked)))))
+	}
+	upper, lower := ranked[:group], ranked[len(ranked)-group:]
+
+	if maxPoints := quiz.MaxPoints(); len(attempts) > 0 && maxPoints > 0 {
+		sum := 0.0
+		for _, total := range totals {
+			sum += total
+		}
+		mean := roundScore(sum / float64(len(attempts)) / maxPoints * 100)
+		dto.MeanPercent = &mean
+	}
+
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+		item := contracts.QuizItemStatisticsDTO{
+			QuestionID: question.ID,
+			Type:       question.Type,
+			Prompt:     question.Prompt,
+			Points:     question.Points,
+		}
+		picked := map[uint]int{}
+		for _, attempt := range attempts {
+			answer := attempt.Answer(question.ID)
+			if answer == nil {
+				continue
+			}
+			if answer.AnsweredAt != nil {
+				item.Responses++
+			}
+			if answer.Points == nil {
+				item.Ungraded++
+			}
+			for _, id := range answer.Options() {
+				picked[id]++
+			}
+		}
+		item.Difficulty = meanShare(question, attempts)
+		if group > 0 {
+			high, low := meanShare(question, upper), meanShare(question, lower)
+			if high != nil && low != nil {
+				d := roundScore(*high - *low)
+				item.Discrimination = &d
+			}
+		}
+		for _, option := range question.Options {
+			item.Options = append(item.Options, contracts.QuizOptionStatisticsDTO{
+				OptionID: option.ID,
+				Text:     option.Text,
+				Correct:  option.Correct,
+				Picked:   picked[option.ID],
+			})
+		}
+		dto.Items = append(dto.Items, item)
+	}
+	return dto
+}
+
+// meanShare is the mean share of a question's points earned in the given
+// attempts, counting graded answers only, or nil when none are graded.
+func meanShare(question *models.QuizQuestion, attempts []*models.QuizAttempt) *float64 {
+	sum, n := 0.0, 0
+	for _, attempt := range attempts {
+		if answer := attempt.Answer(question.ID); answer != nil && answer.Points != nil {
+			sum += *answer.Points / question.Points
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	share := roundScore(sum / float64(n))
+	return &share
+}
+
+func mapToQuizDTO(quiz *models.Quiz, withQuestions bool, now time.Time) *contracts.QuizDTO {
+	dto := &contracts.QuizDTO{
+		ID:          quiz.ID,
+		SubjectID:   quiz.SubjectID,
+		TermID:      quiz.TermID,
+		SectionID:   quiz.SectionID,
+		Title:       quiz.Title,
+		Description: quiz.Description,
+		Settings: contracts.QuizSettingsDTO{
+			OpensAt:          quiz.OpensAt,
+			ClosesAt:         quiz.ClosesAt,
+			TimeLimitMinutes: quiz.TimeLimitMinutes,
+			MaxAttempts:      quiz.MaxAttempts,
+			ShuffleQuestions: quiz.ShuffleQuestions,
+			ShuffleOptions:   quiz.ShuffleOptions,
+		},
+		Open:          quiz.Open(now),
+		MaxPoints:     quiz.MaxPoints(),
+		QuestionCount: len(quiz.Questions),
+	}
+	if withQuestions {
+		dto.Questions = make([]contracts.QuizQuestionDTO, 0, len(quiz.Questions))
+		for i := range quiz.Questions {
+			dto.Questions = append(dto.Questions, mapToQuizQuestionDTO(&quiz.Questions[i], nil, true))
+		}
+	}
+	if quiz.Subject != nil {
+		dto.SubjectName = quiz.Subject.Name
+	}
+	if quiz.Section != nil {
+		dto.SectionCode = quiz.Section.Code
+	}
+	return dto
+}
+
+// mapToQuizQuestionDTO maps a question with its options in the given
+// order, or their own when order is nil. The answer key is only included
+// withKey.
+func mapToQuizQuestionDTO(question *models.QuizQuestion, order []uint, withKey bool) contracts.QuizQuestionDTO {
+	dto := contracts.QuizQuestionDTO{
+		ID:     question.ID,
+		Type:   question.Type,
+		Prompt: question.Prompt,
+		Points: question.Points,
+	}
+	if order == nil {
+		for _, option := range question.Options {
+			order = append(order, option.ID)
+		}
+	}
+	for _, id := range order {
+		option := question.Option(id)
+		if option == nil {
+			continue
+		}
+		o := contracts.QuizOptionDTO{ID: option.ID, Text: option.Text}
+		if withKey {
+			o.Correct = &option.Correct
+		}
+		dto.Options = append(dto.Options, o)
+	}
+	if withKey {
+		dto.Answer = question.Answer
+		dto.Tolerance = question.Tolerance
+		dto.AcceptedAnswers = question.Accepted()
+	}
+	return dto
+}
+
+func mapToQuizAttemptSummaryDTO(attempt *models.QuizAttempt, maxPoints float64) contracts.QuizAttemptSummaryDTO {
+	dto := contracts.QuizAttemptSummaryDTO{
+		ID:            attempt.ID,
+		QuizID:        attempt.QuizID,
+		StudentID:     attempt.StudentID,
+		Number:        attempt.Number,
+		StartedAt:     attempt.StartedAt,
+		DeadlineAt:    attempt.DeadlineAt,
+		SubmittedAt:   attempt.SubmittedAt,
+		AutoSubmitted: attempt.AutoSubmitted,
+		Score:         attempt.Score,
+		MaxPoints:     maxPoints,
+	}
+	if attempt.Student != nil {
+		dto.StudentName = attempt.Student.Name
+	}
+	return dto
+}
+
+// mapToQuizAttemptDTO maps an attempt with its questions in the student's
+// order. Points are shown once the attempt is submitted; the answer key
+// only withKey.
+func mapToQuizAttemptDTO(quiz *models.Quiz, attempt *models.QuizAttempt, withKey bool) *contracts.QuizAttemptDTO {
+	dto := &contracts.QuizAttemptDTO{
+		QuizAttemptSummaryDTO: mapToQuizAttemptSummaryDTO(attempt, quiz.MaxPoints()),
+		Questions:             make([]contracts.AttemptQuestionDTO, 0, len(attempt.Answers)),
+	}
+	for _, id := range models.SplitIDs(attempt.QuestionOrder) {
+		question, answer := quiz.Question(id), attempt.Answer(id)
+		if question == nil || answer == nil {
+			continue
+		}
+		q := contracts.AttemptQuestionDTO{
+			QuizQuestionDTO: mapToQuizQuestionDTO(question, models.SplitIDs(answer.OptionOrder), withKey),
+			Response: contracts.AttemptAnswerDTO{
+				ID:         answer.ID,
+				OptionIDs:  answer.Options(),
+				Number:     answer.Number,
+				Text:       answer.Text,
+				AnsweredAt: answer.AnsweredAt,
+			},
+		}
+		if attempt.Submitted() {
+			q.Response.Points = answer.Points
+		}
+		dto.Questions = append(dto.Questions, q)
+	}
+	return dto
+}
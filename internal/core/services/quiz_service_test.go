@@ -0,0 +1,324 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+func float(v float64) *float64 { return &v }
+
+// testQuiz has a question of every type; short question 6 has no accepted
+// answers and is graded by hand.
+func testQuiz() *models.Quiz {
+	return &models.Quiz{
+		ID: 1,
+		Questions: []models.QuizQuestion{
+			{ID: 1, Type: models.QuestionSingleChoice, Points: 2, Options: []models.QuizOption{
+				{ID: 11, Text: "right", Correct: true}, {ID: 12, Text: "wrong"},
+			}},
+			{ID: 2, Type: models.QuestionMultiChoice, Points: 4, Options: []models.QuizOption{
+				{ID: 21, Correct: true}, {ID: 22, Correct: true}, {ID: 23}, {ID: 24},
+			}},
+			{ID: 3, Type: models.QuestionTrueFalse, Points: 1, Options: []models.QuizOption{
+				{ID: 31, Text: "true", Correct: true}, {ID: 32, Text: "false"},
+			}},
+			{ID: 4, Type: models.QuestionNumeric, Points: 3, Answer: float(9.81), Tolerance: 0.05},
+			{ID: 5, Type: models.QuestionShortAnswer, Points: 2, AcceptedAnswers: "Paris\nParis, France"},
+			{ID: 6, Type: models.QuestionShortAnswer, Points: 5},
+			{ID: 7, Type: models.QuestionNumeric, Points: 1, Answer: float(0.3)},
+		},
+	}
+}
+
+func TestGradeQuizAnswer(t *testing.T) {
+	quiz := testQuiz()
+	tests := []struct {
+		name     string
+		question uint
+		answer   models.QuizAnswer
+		want     *float64
+	}{
+		{name: "single choice right", question: 1, answer: models.QuizAnswer{OptionIDs: "11"}, want: float(2)},
+		{name: "single choice wrong", question: 1, answer: models.QuizAnswer{OptionIDs: "12"}, want: float(0)},
+		{name: "single choice unanswered", question: 1, want: float(0)},
+		{name: "single choice with two picks", question: 1, answer: models.QuizAnswer{OptionIDs: "11,12"}, want: float(0)},
+		{name: "multi choice all right", question: 2, answer: models.QuizAnswer{OptionIDs: "22,21"}, want: float(4)},
+		{name: "multi choice half right", question: 2, answer: models.QuizAnswer{OptionIDs: "21"}, want: float(2)},
+		{name: "multi choice wrong pick cancels right", question: 2, answer: models.QuizAnswer{OptionIDs: "21,23"}, want: float(0)},
+		{name: "multi choice two right one wrong", question: 2, answer: models.QuizAnswer{OptionIDs: "21,22,23"}, want: float(2)},
+		{name: "multi choice every option", question: 2, answer: models.QuizAnswer{OptionIDs: "21,22,23,24"}, want: float(0)},
+		{name: "multi choice unknown option ignored", question: 2, answer: models.QuizAnswer{OptionIDs: "21,99"}, want: float(2)},
+		{name: "true/false right", question: 3, answer: models.QuizAnswer{OptionIDs: "31"}, want: float(1)},
+		{name: "true/false wrong", question: 3, answer: models.QuizAnswer{OptionIDs: "32"}, want: float(0)},
+		{name: "numeric exact", question: 4, answer: models.QuizAnswer{Number: float(9.81)}, want: float(3)},
+		{name: "numeric within tolerance", question: 4, answer: models.QuizAnswer{Number: float(9.86)}, want: float(3)},
+		{name: "numeric outside tolerance", question: 4, answer: models.QuizAnswer{Number: float(9.9)}, want: float(0)},
+		{name: "numeric unanswered", question: 4, want: float(0)},
+		{name: "numeric rounding error", question: 7, answer: models.QuizAnswer{Number: float(0.1 + 0.2)}, want: float(1)},
+		{name: "short answer ignoring case and spacing", question: 5, answer: models.QuizAnswer{Text: "  PARIS "}, want: float(2)},
+		{name: "short answer second accepted", question: 5, answer: models.QuizAnswer{Text: "paris,   france"}, want: float(2)},
+		{name: "short answer wrong", question: 5, answer: models.QuizAnswer{Text: "London"}, want: float(0)},
+		{name: "short answer unanswered", question: 5, want: float(0)},
+		{name: "short answer graded by hand", question: 6, answer: models.QuizAnswer{Text: "An essay"}},
+		{name: "short answer graded by hand unanswered", question: 6, want: float(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gradeQuizAnswer(quiz.Question(tt.question), &tt.answer)
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Fatalf("gradeQuizAnswer = %v, want %v", formatPoints(got), formatPoints(tt.want))
+			}
+		})
+	}
+}
+
+func formatPoints(p *float64) any {
+	if p == nil {
+		return "nil"
+	}
+	return *p
+}
+
+func TestAttemptDeadline(t *testing.T) {
+	start := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	at := func(hour, min int) *time.Time {
+		t := time.Date(2024, 5, 6, hour, min, 0, 0, time.UTC)
+		return &t
+	}
+	tests := []struct {
+		name string
+		quiz models.Quiz
+		want *time.Time
+	}{
+		{name: "untimed and never closing", quiz: models.Quiz{}},
+		{name: "time limit", quiz: models.Quiz{TimeLimitMinutes: 30}, want: at(10, 30)},
+		{name: "closes before the time limit", quiz: models.Quiz{TimeLimitMinutes: 30, ClosesAt: at(10, 20)}, want: at(10, 20)},
+		{name: "closes after the time limit", quiz: models.Quiz{TimeLimitMinutes: 30, ClosesAt: at(11, 0)}, want: at(10, 30)},
+		{name: "untimed but closing", quiz: models.Quiz{ClosesAt: at(12, 0)}, want: at(12, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attemptDeadline(&tt.quiz, start)
+			if (got == nil) != (tt.want == nil) || got != nil && !got.Equal(*tt.want) {
+				t.Fatalf("attemptDeadline = %v, want %v", got, tt.want)
+			}
+			if got != nil && got == tt.quiz.ClosesAt {
+				t.Fatal("attemptDeadline returned the quiz's own ClosesAt")
+			}
+		})
+	}
+}
+
+func TestApplyQuizAnswers(t *testing.T) {
+	quiz := testQuiz()
+	now := time.Date(2024, 5, 6, 10, 5, 0, 0, time.UTC)
+	earlier := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		input    contracts.QuizAnswerInput
+		errField string
+		// want is the answer after applying input, when it is accepted.
+		want models.QuizAnswer
+	}{
+		{
+			name:  "single choice",
+			input: contracts.QuizAnswerInput{QuestionID: 1, OptionIDs: []uint{12}},
+			want:  models.QuizAnswer{QuestionID: 1, OptionIDs: "12", AnsweredAt: &now},
+		},
+		{
+			name:  "multi choice without duplicates",
+			input: contracts.QuizAnswerInput{QuestionID: 2, OptionIDs: []uint{22, 21, 22}},
+			want:  models.QuizAnswer{QuestionID: 2, OptionIDs: "22,21", AnsweredAt: &now},
+		},
+		{
+			name:  "clearing a choice",
+			input: contracts.QuizAnswerInput{QuestionID: 3},
+			want:  models.QuizAnswer{QuestionID: 3},
+		},
+		{
+			name:  "numeric",
+			input: contracts.QuizAnswerInput{QuestionID: 4, Number: float(9.8)},
+			want:  models.QuizAnswer{QuestionID: 4, Number: float(9.8), AnsweredAt: &now},
+		},
+		{
+			name:  "short answer trimmed",
+			input: contracts.QuizAnswerInput{QuestionID: 5, Text: "  Paris \n"},
+			want:  models.QuizAnswer{QuestionID: 5, Text: "Paris", AnsweredAt: &now},
+		},
+		{
+			name:  "blank short answer",
+			input: contracts.QuizAnswerInput{QuestionID: 6, Text: "   "},
+			want:  models.QuizAnswer{QuestionID: 6},
+		},
+		{
+			name:     "question not in the attempt",
+			input:    contracts.QuizAnswerInput{QuestionID: 99, OptionIDs: []uint{11}},
+			errField: "answers[0].question_id",
+		},
+		{
+			name:     "two picks of a single choice",
+			input:    contracts.QuizAnswerInput{QuestionID: 1, OptionIDs: []uint{11, 12}},
+			errField: "answers[0].option_ids",
+		},
+		{
+			name:     "option of another question",
+			input:    contracts.QuizAnswerInput{QuestionID: 2, OptionIDs: []uint{21, 11}},
+			errField: "answers[0].option_ids",
+		},
+		{
+			name:     "number that is not finite",
+			input:    contracts.QuizAnswerInput{QuestionID: 4, Number: float(math.Inf(1))},
+			errField: "answers[0].number",
+		},
+		{
+			name:     "short answer too long",
+			input:    contracts.QuizAnswerInput{QuestionID: 5, Text: strings.Repeat("a", maxShortAnswerLength+1)},
+			errField: "answers[0].text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every question starts out answered, to see answers cleared.
+			attempt := &models.QuizAttempt{}
+			for _, question := range quiz.Questions {
+				attempt.Answers = append(attempt.Answers, models.QuizAnswer{
+					QuestionID: question.ID,
+					OptionIDs:  "99",
+					Number:     float(1),
+					Text:       "old",
+					AnsweredAt: &earlier,
+				})
+			}
+
+			changed, errs := applyQuizAnswers(quiz, attempt, contracts.QuizAnswersInput{Answers: []contracts.QuizAnswerInput{tt.input}}, now)
+			if tt.errField != "" {
+				if len(errs) != 1 || errs[0].Field != tt.errField {
+					t.Fatalf("errors = %v, want one on %s", errs, tt.errField)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(changed) != 1 || changed[0] != attempt.Answer(tt.input.QuestionID) {
+				t.Fatalf("changed = %v, want the attempt's answer to question %d", changed, tt.input.QuestionID)
+			}
+
+			got := changed[0]
+			question := quiz.Question(tt.input.QuestionID)
+			switch question.Type {
+			case models.QuestionNumeric:
+				if (got.Number == nil) != (tt.want.Number == nil) || got.Number != nil && *got.Number != *tt.want.Number {
+					t.Errorf("Number = %v, want %v", formatPoints(got.Number), formatPoints(tt.want.Number))
+				}
+			case models.QuestionShortAnswer:
+				if got.Text != tt.want.Text {
+					t.Errorf("Text = %q, want %q", got.Text, tt.want.Text)
+				}
+			default:
+				if got.OptionIDs != tt.want.OptionIDs {
+					t.Errorf("OptionIDs = %q, want %q", got.OptionIDs, tt.want.OptionIDs)
+				}
+			}
+			if (got.AnsweredAt == nil) != (tt.want.AnsweredAt == nil) || got.AnsweredAt != nil && !got.AnsweredAt.Equal(now) {
+				t.Errorf("AnsweredAt = %v, want %v", got.AnsweredAt, tt.want.AnsweredAt)
+			}
+		})
+	}
+}
+
+func TestQuizStatistics(t *testing.T) {
+	quiz := &models.Quiz{
+		ID: 1,
+		Questions: []models.QuizQuestion{
+			{ID: 1, Type: models.QuestionSingleChoice, Points: 2, Options: []models.QuizOption{
+				{ID: 11, Correct: true}, {ID: 12},
+			}},
+			{ID: 6, Type: models.QuestionShortAnswer, Points: 5},
+		},
+	}
+	now := time.Now()
+	attempt := func(id uint, option string, choice, short *float64) *models.QuizAttempt {
+		a := &models.QuizAttempt{ID: id, Answers: []models.QuizAnswer{
+			{QuestionID: 1, OptionIDs: option, Points: choice},
+			{QuestionID: 6, Text: "essay", AnsweredAt: &now, Points: short},
+		}}
+		if option != "" {
+			a.Answers[0].AnsweredAt = &now
+		}
+		return a
+	}
+	// Ranked by points: 1 (7), 2 (5), 4 (1), 3 (0, one answer ungraded).
+	attempts := []*models.QuizAttempt{
+		attempt(1, "11", float(2), float(5)),
+		attempt(2, "11", float(2), float(3)),
+		attempt(3, "12", float(0), nil),
+		attempt(4, "", float(0), float(1)),
+	}
+
+	got := quizStatistics(quiz, attempts)
+	if got.QuizID != 1 || got.Students != 4 {
+		t.Fatalf("QuizID, Students = %d, %d", got.QuizID, got.Students)
+	}
+	if got.MeanPercent == nil || *got.MeanPercent != 46.43 {
+		t.Errorf("MeanPercent = %v, want 46.43", formatPoints(got.MeanPercent))
+	}
+	if len(got.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(got.Items))
+	}
+
+	choice := got.Items[0]
+	if choice.Responses != 3 || choice.Ungraded != 0 {
+		t.Errorf("choice Responses, Ungraded = %d, %d, want 3, 0", choice.Responses, choice.Ungraded)
+	}
+	if choice.Difficulty == nil || *choice.Difficulty != 0.5 {
+		t.Errorf("choice Difficulty = %v, want 0.5", formatPoints(choice.Difficulty))
+	}
+	// The top and bottom group are one student each: 1 and 3.
+	if choice.Discrimination == nil || *choice.Discrimination != 1 {
+		t.Errorf("choice Discrimination = %v, want 1", formatPoints(choice.Discrimination))
+	}
+	if len(choice.Options) != 2 || choice.Options[0].Picked != 2 || !choice.Options[0].Correct || choice.Options[1].Picked != 1 {
+		t.Errorf("choice Options = %+v", choice.Options)
+	}
+
+	short := got.Items[1]
+	if short.Responses != 4 || short.Ungraded != 1 {
+		t.Errorf("short Responses, Ungraded = %d, %d, want 4, 1", short.Responses, short.Ungraded)
+	}
+	if short.Difficulty == nil || *short.Difficulty != 0.6 {
+		t.Errorf("short Difficulty = %v, want 0.6", formatPoints(short.Difficulty))
+	}
+	// The bottom student's answer is not graded yet.
+	if short.Discrimination != nil {
+		t.Errorf("short Discrimination = %v, want nil", *short.Discrimination)
+	}
+	if len(short.Options) != 0 {
+		t.Errorf("short Options = %+v", short.Options)
+	}
+}
+
+func TestQuizStatisticsWithoutAttempts(t *testing.T) {
+	quiz := testQuiz()
+	for _, attempts := range [][]*models.QuizAttempt{nil, {{ID: 1, Answers: []models.QuizAnswer{{QuestionID: 1, OptionIDs: "11", Points: float(2)}}}}} {
+		got := quizStatistics(quiz, attempts)
+		if got.Students != len(attempts) || len(got.Items) != len(quiz.Questions) {
+			t.Fatalf("Students, Items = %d, %d", got.Students, len(got.Items))
+		}
+		if (got.MeanPercent == nil) != (len(attempts) == 0) {
+			t.Errorf("MeanPercent = %v with %d attempts", formatPoints(got.MeanPercent), len(attempts))
+		}
+		for _, item := range got.Items {
+			// A single student cannot be split into a top and bottom group.
+			if item.Discrimination != nil {
+				t.Errorf("question %d Discrimination = %v with %d attempts", item.QuestionID, *item.Discrimination, len(attempts))
+			}
+		}
+	}
+}
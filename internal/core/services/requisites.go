@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+const defaultPassingScore = 50.0
+
+// passingScore is the lowest final mark, in percent, that completes a
+// subject. It is read from PASSING_SCORE.
+func passingScore() float64 {
+	if v, err := strconv.ParseFloat(os.Getenv("PASSING_SCORE"), 64); err == nil && v >= 0 && v <= 100 {
+		return v
+	}
+	return defaultPassingScore
+}
+
+func (s *SubjectService) GetRequisites(ctx context.Context, subjectID uint) (*contracts.RequisitesDTO, error) {
+	if _, err := s.GetSubject(ctx, subjectID); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.subjects.ListRequisites(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToRequisitesDTO(subjectID, groups), nil
+}
+
+// ReplaceRequisites replaces every requirement of a subject. Edits that would
+// make a subject depend on itself through a chain containing a prerequisite
+// are rejected; purely corequisite loops are allowed since such subjects can
+// be taken together.
+func (s *SubjectService) ReplaceRequisites(ctx context.Context, subjectID uint, input contracts.RequisitesInput) (*contracts.RequisitesDTO, error) {
+	if _, err := s.GetSubject(ctx, subjectID); err != nil {
+		return nil, err
+	}
+
+	groups, errs := buildRequisiteGroups(subjectID, input)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if err := s.checkRequisiteSubjects(ctx, groups); err != nil {
+		return nil, err
+	}
+
+	err := s.subjects.ReplaceRequisites(ctx, subjectID, groups, func(edges []repositories.RequisiteEdge) error {
+		if cycle := findRequisiteCycle(edges); cycle != nil {
+			return s.cycleError(ctx, cycle)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
+
+	return s.GetRequisites(ctx, subjectID)
+}
+
+// CheckEligibility reports which requirements of a subject the student is
+// still missing. Corequisites count as met when the student is enrolled in
+// the subject in the given term (see resolveTermParam).
+func (s *SubjectService) CheckEligibility(ctx context.Context, studentID, subjectID uint, term string) (*contracts.EligibilityDTO, error) {
+	if _, err := s.GetSubject(ctx, subjectID); err != nil {
+		return nil, err
+	}
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil && !errors.Is(err, contracts.ErrNoCurrentTerm) {
+		return nil, err
+	}
+
+	missing, err := s.missingRequisites(ctx, studentID, subjectID, termID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &contracts.EligibilityDTO{
+		StudentID: studentID,
+		SubjectID: subjectID,
+		Eligible:  len(missing) == 0,
+		Missing:   missing,
+	}, nil
+}
+
+// EnsurePrerequisites returns a *contracts.EligibilityError when the student
+// has not completed the prerequisites of the subject. Corequisites are not
+// enforced because they may be taken in the same term.
+func (s *SubjectService) EnsurePrerequisites(ctx context.Context, studentID, subjectID uint) error {
+	missing, err := s.missingRequisites(ctx, studentID, subjectID, 0, false)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return &contracts.EligibilityError{Missing: missing}
+	}
+	return nil
+}
+
+func (s *SubjectService) missingRequisites(ctx context.Context, studentID, subjectID, termID uint, withCoreqs bool) ([]contracts.MissingRequisiteDTO, error) {
+	groups, err := s.subjects.ListRequisites(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return []contracts.MissingRequisiteDTO{}, nil
+	}
+
+	history, err := s.enrollments.ListByStudent(ctx, studentID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Best final mark per subject, and subjects taken in the target term.
+	best := map[uint]float64{}
+	current := map[uint]bool{}
+	for _, e := range history {
+		if e.Section == nil {
+			continue
+		}
+		if e.FinalScore != nil {
+			if prev, ok := best[e.Section.SubjectID]; !ok || *e.FinalScore > prev {
+				best[e.Section.SubjectID] = *e.FinalScore
+			}
+		}
+		if termID != 0 && e.Section.TermID == termID && e.Status == models.EnrollmentEnrolled {
+			current[e.Section.SubjectID] = true
+		}
+	}
+
+	pass := passingScore()
+	missing := []contracts.MissingRequisiteDTO{}
+	for _, g := range groups {
+		if g.Kind == models.RequisiteCorequisite && !withCoreqs {
+			continue
+		}
+
+		met := false
+		for _, o := range g.Options {
+			min := pass
+			if o.MinScore != nil {
+				min = *o.MinScore
+			}
+			if score, ok := best[o.RequiredSubjectID]; ok && score >= min {
+				met = true
+				break
+			}
+			if g.Kind == models.RequisiteCorequisite && current[o.RequiredSubjectID] {
+				met = true
+				break
+			}
+		}
+		if !met {
+			dto := mapToRequisiteGroupDTO(&g)
+			missing = append(missing, contracts.MissingRequisiteDTO{
+				Kind:        g.Kind,
+				Description: describeRequisiteGroup(dto, pass),
+				Options:     dto.Options,
+			})
+		}
+	}
+	return missing, nil
+}
+
+func (s *SubjectService) checkRequisiteSubjects(ctx context.Context, groups []models.RequisiteGroup) error {
+	ids := []uint{}
+	seen := map[uint]bool{}
+	for _, g := range groups {
+		for _, o := range g.Options {
+			if !seen[o.RequiredSubjectID] {
+				seen[o.RequiredSubjectID] = true
+				ids = append(ids, o.RequiredSubjectID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	subjects, err := s.subjects.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(subjects) != len(ids) {
+		return contracts.ValidationErrors{{Field: "groups", Message: "one or more required subjects not found"}}
+	}
+	return nil
+}
+
+func (s *SubjectService) cycleError(ctx context.Context, cycle []uint) error {
+	names := make([]string, 0, len(cycle)+1)
+	subjects, err := s.subjects.FindByIDs(ctx, cycle)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]string, len(subjects))
+	for _, sub := range subjects {
+		byID[sub.ID] = sub.Name
+	}
+	for _, id := range append(cycle, cycle[0]) {
+		names = append(names, byID[id])
+	}
+	return contracts.ValidationErrors{{
+		Field:   "groups",
+		Message: "would create a prerequisite cycle: " + strings.Join(names, " → "),
+	}}
+}
+
+func buildRequisiteGroups(subjectID uint, input contracts.RequisitesInput) ([]models.RequisiteGroup, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	groups := make([]models.RequisiteGroup, 0, len(input.Groups))
+	for i, g := range input.Groups {
+		field := fmt.Sprintf("groups[%d]", i)
+		kind := strings.TrimSpace(strings.ToLower(g.Kind))
+		if kind != models.RequisitePrerequisite && kind != models.RequisiteCorequisite {
+			errs = append(errs, contracts.ValidationError{Field: field + ".kind", Message: "kind must be prerequisite or corequisite"})
+		}
+		if len(g.Options) == 0 {
+			errs = append(errs, contracts.ValidationError{Field: field + ".options", Message: "at least one option is required"})
+		}
+
+		group := models.RequisiteGroup{SubjectID: subjectID, Kind: kind}
+		for j, o := range g.Options {
+			optField := fmt.Sprintf("%s.options[%d]", field, j)
+			switch {
+			case o.SubjectID == 0:
+				errs = append(errs, contracts.ValidationError{Field: optField + ".subject_id", Message: "subject_id is required"})
+			case o.SubjectID == subjectID:
+				errs = append(errs, contracts.ValidationError{Field: optField + ".subject_id", Message: "a subject cannot require itself"})
+			}
+			if o.MinScore != nil && (*o.MinScore < 0 || *o.MinScore > 100) {
+				errs = append(errs, contracts.ValidationError{Field: optField + ".min_score", Message: "min_score must be between 0 and 100"})
+			}
+			group.Options = append(group.Options, models.RequisiteOption{RequiredSubjectID: o.SubjectID, MinScore: o.MinScore})
+		}
+		groups = append(groups, group)
+	}
+	return groups, errs
+}
+
+// findRequisiteCycle returns the subjects of a cycle that contains at least
+// one prerequisite edge, or nil if there is none. Such a cycle exists exactly
+// when both ends of some prerequisite edge lie in the same strongly connected
+// component of the graph.
+func findRequisiteCycle(edges []repositories.RequisiteEdge) []uint {
+	adj := map[uint][]uint{}
+	for _, e := range edges {
+		adj[e.SubjectID] = append(adj[e.SubjectID], e.RequiredSubjectID)
+	}
+	component := stronglyConnected(adj)
+
+	for _, e := range edges {
+		if e.Kind != models.RequisitePrerequisite {
+			continue
+		}
+		c, ok := component[e.SubjectID]
+		if !ok || c != component[e.RequiredSubjectID] {
+			continue
+		}
+		// Walk back from the required subject to the dependent one inside
+		// the component to report a concrete cycle.
+		path := pathWithin(adj, component, e.RequiredSubjectID, e.SubjectID)
+		return append([]uint{e.SubjectID}, path...)
+	}
+	return nil
+}
+
+// stronglyConnected labels every node with its strongly connected component
+// using Tarjan's algorithm.
+func stronglyConnected(adj map[uint][]uint) map[uint]int {
+	var (
+		index   = map[uint]int{}
+		low     = map[uint]int{}
+		onStack = map[uint]bool{}
+		stack   []uint
+		comp    = map[uint]int{}
+		next    int
+		nComp   int
+	)
+
+	var visit func(v uint)
+	visit = func(v uint) {
+		index[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range adj[v] {
+			if _, seen := index[w]; !seen {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+
+		if low[v] == index[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp[w] = nComp
+				if w == v {
+					break
+				}
+			}
+			nComp++
+		}
+	}
+
+	nodes := make([]uint, 0, len(adj))
+	for v := range adj {
+		nodes = append(nodes, v)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+	for _, v := range nodes {
+		if _, seen := index[v]; !seen {
+			visit(v)
+		}
+	}
+	return comp
+}
+
+// pathWithin finds a path from -> to that stays inside from's component and
+// returns its nodes, excluding to.
+func pathWithin(adj map[uint][]uint, component map[uint]int, from, to uint) []uint {
+	prev := map[uint]uint{from: from}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if v == to {
+			break
+		}
+		for _, w := range adj[v] {
+			if _, seen := prev[w]; seen || component[w] != component[from] {
+				continue
+			}
+			prev[w] = v
+			queue = append(queue, w)
+		}
+	}
+
+	var path []uint
+	for v := prev[to]; ; v = prev[v] {
+		path = append([]uint{v}, path...)
+		if v == from {
+			break
+		}
+	}
+	return path
+}
+
+func mapToRequisitesDTO(subjectID uint, groups []models.RequisiteGroup) *contracts.RequisitesDTO {
+	dto := &contracts.RequisitesDTO{SubjectID: subjectID, Groups: make([]contracts.RequisiteGroupDTO, 0, len(groups))}
+	for i := range groups {
+		dto.Groups = append(dto.Groups, mapToRequisiteGroupDTO(&groups[i]))
+	}
+	return dto
+}
+
+func mapToRequisiteGroupDTO(group *models.RequisiteGroup) contracts.RequisiteGroupDTO {
+	dto := contracts.RequisiteGroupDTO{Kind: group.Kind, Options: make([]contracts.RequisiteOptionDTO, 0, len(group.Options))}
+	for _, o := range group.Options {
+		opt := contracts.RequisiteOptionDTO{SubjectID: o.RequiredSubjectID, MinScore: o.MinScore}
+		if o.RequiredSubject != nil {
+			opt.SubjectName = o.RequiredSubject.Name
+		}
+		dto.Options = append(dto.Options, opt)
+	}
+	return dto
+}
+
+func describeRequisiteGroup(group contracts.RequisiteGroupDTO, pass float64) string {
+	parts := make([]string, 0, len(group.Options))
+	for _, o := range group.Options {
+		min := pass
+		if o.MinScore != nil {
+			min = *o.MinScore
+		}
+		parts = append(parts, fmt.Sprintf("%s (at least %g%%)", o.SubjectName, min))
+	}
+
+	verb := "complete"
+	if group.Kind == models.RequisiteCorequisite {
+		verb = "complete or enroll this term in"
+	}
+	if len(parts) == 1 {
+		return verb + " " + parts[0]
+	}
+	return verb + " one of: " + strings.Join(parts, ", ")
+}
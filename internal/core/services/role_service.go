@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+// RoleService manages roles and the permissions they grant.
+type RoleService struct {
+	roles         repositories.RoleRepository
+	permissions   repositories.PermissionRepository
+	refreshTokens repositories.RefreshTokenRepository
+	cache         cache.Cache
+}
+
+func NewRoleService(roles repositories.RoleRepository, permissions repositories.PermissionRepository, refreshTokens repositories.RefreshTokenRepository, c cache.Cache) *RoleService {
+	return &RoleService{roles: roles, permissions: permissions, refreshTokens: refreshTokens, cache: c}
+}
+
+func (s *RoleService) ListPermissions(ctx context.Context) ([]contracts.PermissionDTO, error) {
+	permissions, err := s.permissions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.PermissionDTO, 0, len(permissions))
+	for _, p := range permissions {
+		dtos = append(dtos, contracts.PermissionDTO{Name: p.Name, Description: p.Description})
+	}
+	return dtos, nil
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]contracts.RoleDTO, error) {
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.roles.CountUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.RoleDTO, 0, len(roles))
+	for i := range roles {
+		dtos = append(dtos, *mapToRoleDTO(&roles[i], counts[roles[i].ID]))
+	}
+	return dtos, nil
+}
+
+func (s *RoleService) GetRole(ctx context.Context, id uint) (*contracts.RoleDTO, error) {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.roles.CountUsersForRole(ctx, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToRoleDTO(role, count), nil
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, input contracts.RoleInput) (*contracts.RoleDTO, error) {
+	input.Name = strings.TrimSpace(strings.ToLower(input.Name))
+
+	if errs := validateRoleInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err := s.ensureNameFree(ctx, input.Name); err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.resolvePermissions(ctx, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{Name: input.Name, Permissions: permissions}
+	if err := s.roles.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	return mapToRoleDTO(role, 0), nil
+}
+
+// UpdateRole renames a role and/or replaces its permission set. Built-in
+// roles keep their names because route guards refer to them, and the admin
+// role always holds every permission.
+func (s *RoleService) UpdateRole(ctx context.Context, id uint, input contracts.RoleInput) (*contracts.RoleDTO, error) {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Name = strings.TrimSpace(strings.ToLower(input.Name))
+	if input.Name != "" && input.Name != role.Name {
+		if role.System {
+			return nil, contracts.ErrSystemRole
+		}
+		if errs := validateRoleInput(input); len(errs) > 0 {
+			return nil, errs
+		}
+		if err := s.ensureNameFree(ctx, input.Name); err != nil {
+			return nil, err
+		}
+
+		role.Name = input.Name
+		if err := s.roles.Save(ctx, role); err != nil {
+			return nil, err
+		}
+		// User listings show role names.
+		invalidateCache(ctx, s.cache, cache.TagUsers)
+	}
+
+	if input.Permissions != nil {
+		if role.Name == models.RoleAdmin {
+			return nil, contracts.ErrSystemRole
+		}
+
+		permissions, err := s.resolvePermissions(ctx, input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.roles.ReplacePermissions(ctx, role, permissions); err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+
+		invalidateCache(ctx, s.cache, cache.RoleTag(role.ID))
+	}
+
+	count, err := s.roles.CountUsersForRole(ctx, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToRoleDTO(role, count), nil
+}
+
+// DeleteRole removes a custom role. Built-in roles cannot be deleted. A role
+// that still has users is only deleted when reassignTo names another role to
+// move them to; otherwise ErrRoleInUse is returned. Like any role change,
+// moving users signs them out everywhere.
+func (s *RoleService) DeleteRole(ctx context.Context, id uint, reassignTo *uint) error {
+	role, err := s.findRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role.System {
+		return contracts.ErrSystemRole
+	}
+
+	var moved []uint
+	if reassignTo == nil {
+		// users.role_id references the role, so a user assigned after any
+		// check here would still make the delete fail.
+		if err := s.roles.Delete(ctx, role.ID); err != nil {
+			if errors.Is(err, gorm.ErrForeignKeyViolated) {
+				return contracts.ErrRoleInUse
+			}
+			return err
+		}
+	} else {
+		if *reassignTo == role.ID {
+			return contracts.ValidationErrors{{Field: "reassign_to", Message: "cannot reassign users to the role being deleted"}}
+		}
+		if _, err := s.roles.FindByID(ctx, *reassignTo); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return contracts.ValidationErrors{{Field: "reassign_to", Message: "target role not found"}}
+			}
+			return err
+		}
+
+		if moved, err = s.roles.DeleteAndReassign(ctx, role.ID, *reassignTo); err != nil {
+			return err
+		}
+		invalidateCache(ctx, s.cache, cache.TagUsers)
+	}
+
+	invalidateCache(ctx, s.cache, cache.RoleTag(role.ID))
+	for _, userID := range moved {
+		if err := revokeUserSessions(ctx, s.refreshTokens, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RoleService) ensureNameFree(ctx context.Context, name string) error {
+	if _, err := s.roles.FindByName(ctx, name); err == nil {
+		return contracts.ErrRoleExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *RoleService) findRole(ctx context.Context, id uint) (*models.Role, error) {
+	role, err := s.roles.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// resolvePermissions loads the named permissions and rejects unknown names.
+func (s *RoleService) resolvePermissions(ctx context.Context, names []string) ([]models.Permission, error) {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		n = strings.TrimSpace(strings.ToLower(n))
+		if _, ok := seen[n]; ok || n == "" {
+			continue
+		}
+		seen[n] = struct{}{}
+		unique = append(unique, n)
+	}
+	if len(unique) == 0 {
+		return []models.Permission{}, nil
+	}
+
+	permissions, err := s.permissions.FindByNames(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(permissions) != len(unique) {
+		found := make(map[string]struct{}, len(permissions))
+		for _, p := range permissions {
+			found[p.Name] = struct{}{}
+		}
+		var errs contracts.ValidationErrors
+		for _, n := range unique {
+			if _, ok := found[n]; !ok {
+				errs = append(errs, contracts.ValidationError{
+					Field:   "permissions",
+					Message: fmt.Sprintf("unknown permission %q", n),
+				})
+			}
+		}
+		return nil, errs
+	}
+	return permissions, nil
+}
+
+func mapToRoleDTO(role *models.Role, userCount int64) *contracts.RoleDTO {
+	names := make([]string, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+
+	return &contracts.RoleDTO{
+		ID:          role.ID,
+		Name:        role.Name,
+		System:      role.System,
+		Permissions: names,
+		UserCount:   userCount,
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+const (
+	maxRoomCodeLength = 20
+	maxRoomCapacity   = 5000
+)
+
+// RoomService manages the buildings and rooms sections meet in.
+type RoomService struct {
+	rooms repositories.RoomRepository
+}
+
+func NewRoomService(rooms repositories.RoomRepository) *RoomService {
+	return &RoomService{rooms: rooms}
+}
+
+func (s *RoomService) ListBuildings(ctx context.Context) ([]contracts.BuildingDTO, error) {
+	buildings, err := s.rooms.ListBuildings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.BuildingDTO, 0, len(buildings))
+	for i := range buildings {
+		dtos = append(dtos, *mapToBuildingDTO(&buildings[i]))
+	}
+	return dtos, nil
+}
+
+func (s *RoomService) CreateBuilding(ctx context.Context, input contracts.BuildingInput) (*contracts.BuildingDTO, error) {
+	input = normalizeBuildingInput(input)
+	if errs := validateBuildingInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+
+	building := &models.Building{Code: input.Code, Name: input.Name}
+	if err := s.rooms.CreateBuilding(ctx, building); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrBuildingExists
+		}
+		return nil, err
+	}
+	return mapToBuildingDTO(building), nil
+}
+
+func (s *RoomService) UpdateBuilding(ctx context.Context, id uint, input contracts.BuildingInput) (*contracts.BuildingDTO, error) {
+	building, err := s.findBuilding(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input = normalizeBuildingInput(input)
+	if errs := validateBuildingInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+
+	building.Code = input.Code
+	building.Name = input.Name
+	if err := s.rooms.SaveBuilding(ctx, building); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrBuildingExists
+		}
+		return nil, err
+	}
+	return mapToBuildingDTO(building), nil
+}
+
+// DeleteBuilding removes a building. Buildings that still have rooms cannot
+// be deleted.
+func (s *RoomService) DeleteBuilding(ctx context.Context, id uint) error {
+	if err := s.rooms.DeleteBuilding(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrBuildingNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrBuildingInUse
+		}
+		return err
+	}
+	return nil
+}
+
+// ListRooms lists rooms, optionally only those of one building.
+func (s *RoomService) ListRooms(ctx context.Context, buildingID uint) ([]contracts.RoomDTO, error) {
+	rooms, err := s.rooms.ListRooms(ctx, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.RoomDTO, 0, len(rooms))
+	for i := range rooms {
+		dtos = append(dtos, *mapToRoomDTO(&rooms[i]))
+	}
+	return dtos, nil
+}
+
+func (s *RoomService) GetRoom(ctx context.Context, id uint) (*contracts.RoomDTO, error) {
+	room, err := s.findRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapToRoomDTO(room), nil
+}
+
+func (s *RoomService) CreateRoom(ctx context.Context, input contracts.RoomInput) (*contracts.RoomDTO, error) {
+	input.Code = strings.TrimSpace(strings.ToUpper(input.Code))
+	if errs := validateRoomInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+	if err := s.ensureBuilding(ctx, input.BuildingID); err != nil {
+		return nil, err
+	}
+
+	room := &models.Room{BuildingID: input.BuildingID, Code: input.Code, Capacity: input.Capacity}
+	if err := s.rooms.CreateRoom(ctx, room); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrRoomExists
+		}
+		return nil, err
+	}
+	return s.GetRoom(ctx, room.ID)
+}
+
+// UpdateRoom replaces every field of a room, including the building it is
+// in.
+func (s *RoomService) UpdateRoom(ctx context.Context, id uint, input contracts.RoomInput) (*contracts.RoomDTO, error) {
+	room, err := s.findRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Code = strings.TrimSpace(strings.ToUpper(input.Code))
+	if errs := validateRoomInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+	if input.BuildingID != room.BuildingID {
+		if err := s.ensureBuilding(ctx, input.BuildingID); err != nil {
+			return nil, err
+		}
+	}
+
+	room.BuildingID = input.BuildingID
+	room.Code = input.Code
+	room.Capacity = input.Capacity
+	if err := s.rooms.SaveRoom(ctx, room); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrRoomExists
+		}
+		return nil, err
+	}
+	return s.GetRoom(ctx, room.ID)
+}
+
+// DeleteRoom removes a room. Rooms that sections still meet in cannot be
+// deleted.
+func (s *RoomService) DeleteRoom(ctx context.Context, id uint) error {
+	if err := s.rooms.DeleteRoom(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrRoomNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrRoomInUse
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *RoomService) findBuilding(ctx context.Context, id uint) (*models.Building, error) {
+	building, err := s.rooms.FindBuilding(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrBuildingNotFound
+		}
+		return nil, err
+	}
+	return building, nil
+}
+
+func (s *RoomService) findRoom(ctx context.Context, id uint) (*models.Room, error) {
+	room, err := s.rooms.FindRoom(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrRoomNotFound
+		}
+		return nil, err
+	}
+	return room, nil
+}
+
+func (s *RoomService) ensureBuilding(ctx context.Context, id uint) error {
+	if _, err := s.rooms.FindBuilding(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ValidationErrors{{Field: "building_id", Message: "building not found"}}
+		}
+		return err
+	}
+	return nil
+}
+
+func normalizeBuildingInput(input contracts.BuildingInput) contracts.BuildingInput {
+	input.Code = strings.TrimSpace(strings.ToUpper(input.Code))
+	input.Name = strings.TrimSpace(input.Name)
+	return input
+}
+
+func validateBuildingInput(input contracts.BuildingInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	switch {
+	case input.Code == "":
+		errs = append(errs, contracts.ValidationError{Field: "code", Message: "code is required"})
+	case len(input.Code) > maxRoomCodeLength || strings.Contains(input.Code, "-"):
+		errs = append(errs, contracts.ValidationError{Field: "code", Message: fmt.Sprintf("code must be at most %d characters without dashes", maxRoomCodeLength)})
+	}
+	switch {
+	case input.Name == "":
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is required"})
+	case len(input.Name) > maxNameLength:
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is too long"})
+	}
+	return errs
+}
+
+func validateRoomInput(input contracts.RoomInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	if input.BuildingID == 0 {
+		errs = append(errs, contracts.ValidationError{Field: "building_id", Message: "building_id is required"})
+	}
+	switch {
+	case input.Code == "":
+		errs = append(errs, contracts.ValidationError{Field: "code", Message: "code is required"})
+	case len(input.Code) > maxRoomCodeLength:
+		errs = append(errs, contracts.ValidationError{Field: "code", Message: "code is too long"})
+	}
+	if input.Capacity < 1 || input.Capacity > maxRoomCapacity {
+		errs = append(errs, contracts.ValidationError{Field: "capacity", Message: fmt.Sprintf("capacity must be between 1 and %d", maxRoomCapacity)})
+	}
+	return errs
+}
+
+func mapToBuildingDTO(building *models.Building) *contracts.BuildingDTO {
+	return &contracts.BuildingDTO{ID: building.ID, Code: building.Code, Name: building.Name}
+}
+
+func mapToRoomDTO(room *models.Room) *contracts.RoomDTO {
+	dto := &contracts.RoomDTO{
+		ID:         room.ID,
+		BuildingID: room.BuildingID,
+		Code:       room.Code,
+		Label:      room.Label(),
+		Capacity:   room.Capacity,
+	}
+	if room.Building != nil {
+		dto.BuildingCode = room.Building.Code
+	}
+	return dto
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+const (
+	maxRubricTitleLength   = 200
+	maxRubricTextLength    = 2000
+	maxRubricCriteria      = 30
+	maxRubricLevels        = 10
+	maxRubricLevelPoints   = 1000
+	maxRubricCommentLength = 2000
+)
+
+// RubricService keeps teachers' reusable rubrics. A rubric belongs to the
+// teacher who wrote it; other teachers only see it through the assignments
+// it is attached to.
+type RubricService struct {
+	rubrics repositories.RubricRepository
+}
+
+func NewRubricService(rubrics repositories.RubricRepository) *RubricService {
+	return &RubricService{rubrics: rubrics}
+}
+
+func (s *RubricService) ListRubrics(ctx context.Context, teacherID uint) ([]contracts.RubricDTO, error) {
+	rubrics, err := s.rubrics.ListByOwner(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.RubricDTO, 0, len(rubrics))
+	for i := range rubrics {
+		dtos = append(dtos, *mapToRubricDTO(&rubrics[i]))
+	}
+	return dtos, nil
+}
+
+func (s *RubricService) GetRubric(ctx context.Context, teacherID, id uint) (*contracts.RubricDTO, error) {
+	rubric, err := s.ownRubric(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapToRubricDTO(rubric), nil
+}
+
+func (s *RubricService) CreateRubric(ctx context.Context, teacherID uint, input contracts.RubricInput) (*contracts.RubricDTO, error) {
+	rubric, errs := buildRubric(input)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	rubric.OwnerID = teacherID
+	if err := s.rubrics.Create(ctx, rubric); err != nil {
+		return nil, err
+	}
+	return s.GetRubric(ctx, teacherID, rubric.ID)
+}
+
+// UpdateRubric replaces a rubric's title, description and criteria. Once a
+// submission has been graded with the rubric it can no longer be changed;
+// teachers write a new one instead.
+func (s *RubricService) UpdateRubric(ctx context.Context, teacherID, id uint, input contracts.RubricInput) (*contracts.RubricDTO, error) {
+	rubric, err := s.ownRubric(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+	replacement, errs := buildRubric(input)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	rubric.Title = replacement.Title
+	rubric.Description = replacement.Description
+	rubric.Criteria = replacement.Criteria
+	if err := s.rubrics.Replace(ctx, rubric); err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, contracts.ErrRubricGraded
+		}
+		return nil, err
+	}
+	return s.GetRubric(ctx, teacherID, id)
+}
+
+// DeleteRubric removes a rubric that is not attached to any assignment.
+func (s *RubricService) DeleteRubric(ctx context.Context, teacherID, id uint) error {
+	if _, err := s.ownRubric(ctx, teacherID, id); err != nil {
+		return err
+	}
+	if err := s.rubrics.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrRubricNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrRubricInUse
+		}
+		return err
+	}
+	return nil
+}
+
+// ownRubric loads one of the teacher's rubrics. Other teachers' rubrics are
+// reported as missing.
+func (s *RubricService) ownRubric(ctx context.Context, teacherID, id uint) (*models.Rubric, error) {
+	rubric, err := s.rubrics.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrRubricNotFound
+		}
+		return nil, err
+	}
+	if rubric.OwnerID != teacherID {
+		return nil, contracts.ErrRubricNotFound
+	}
+	return rubric, nil
+}
+
+func buildRubric(input contracts.RubricInput) (*models.Rubric, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	rubric := &models.Rubric{
+		Title:       strings.TrimSpace(input.Title),
+		Description: strings.TrimSpace(input.Description),
+	}
+	switch {
+	case rubric.Title == "":
+		errs = append(errs, contracts.ValidationError{Field: "title", Message: "title is required"})
+	case len(rubric.Title) > maxRubricTitleLength:
+		errs = append(errs, contracts.ValidationError{Field: "title", Message: "title is too long"})
+	}
+	if len(rubric.Description) > maxRubricTextLength {
+		errs = append(errs, contracts.ValidationError{Field: "description", Message: "description is too long"})
+	}
+	switch {
+	case len(input.Criteria) == 0:
+		return nil, append(errs, contracts.ValidationError{Field: "criteria", Message: "at least one criterion is required"})
+	case len(input.Criteria) > maxRubricCriteria:
+		return nil, append(errs, contracts.ValidationError{Field: "criteria", Message: fmt.Sprintf("at most %d criteria are allowed", maxRubricCriteria)})
+	}
+
+	for i, c := range input.Criteria {
+		field := fmt.Sprintf("criteria[%d]", i)
+		criterion := models.RubricCriterion{
+			Position:    i,
+			Title:       strings.TrimSpace(c.Title),
+			Description: strings.TrimSpace(c.Description),
+		}
+		switch {
+		case criterion.Title == "":
+			errs = append(errs, contracts.ValidationError{Field: field + ".title", Message: "title is required"})
+		case len(criterion.Title) > maxRubricTitleLength:
+			errs = append(errs, contracts.ValidationError{Field: field + ".title", Message: "title is too long"})
+		}
+		if len(criterion.Description) > maxRubricTextLength {
+			errs = append(errs, contracts.ValidationError{Field: field + ".description", Message: "description is too long"})
+		}
+		switch {
+		case len(c.Levels) == 0:
+			errs = append(errs, contracts.ValidationError{Field: field + ".levels", Message: "at least one level is required"})
+		case len(c.Levels) > maxRubricLevels:
+			errs = append(errs, contracts.ValidationError{Field: field + ".levels", Message: fmt.Sprintf("at most %d levels are allowed", maxRubricLevels)})
+		}
+
+		for j, l := range c.Levels {
+			levelField := fmt.Sprintf("%s.levels[%d]", field, j)
+			level := models.RubricLevel{Position: j, Points: l.Points, Descriptor: strings.TrimSpace(l.Descriptor)}
+			if math.IsNaN(level.Points) || level.Points < 0 || level.Points > maxRubricLevelPoints {
+				errs = append(errs, contracts.ValidationError{Field: levelField + ".points", Message: fmt.Sprintf("points must be between 0 and %d", maxRubricLevelPoints)})
+			}
+			switch {
+			case level.Descriptor == "":
+				errs = append(errs, contracts.ValidationError{Field: levelField + ".descriptor", Message: "descriptor is required"})
+			case len(level.Descriptor) > maxRubricTextLength:
+				errs = append(errs, contracts.ValidationError{Field: levelField + ".descriptor", Message: "descriptor is too long"})
+			}
+			criterion.Levels = append(criterion.Levels, level)
+		}
+		rubric.Criteria = append(rubric.Criteria, criterion)
+	}
+
+	if len(errs) == 0 && rubric.MaxPoints() <= 0 {
+		errs = append(errs, contracts.ValidationError{Field: "criteria", Message: "the rubric must be worth more than 0 points"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return rubric, nil
+}
+
+func mapToRubricDTO(rubric *models.Rubric) *contracts.RubricDTO {
+	dto := &contracts.RubricDTO{
+		ID:          rubric.ID,
+		Title:       rubric.Title,
+		Description: rubric.Description,
+		MaxPoints:   rubric.MaxPoints(),
+		Criteria:    make([]contracts.RubricCriterionDTO, 0, len(rubric.Criteria)),
+		CreatedAt:   rubric.CreatedAt,
+		UpdatedAt:   rubric.UpdatedAt,
+	}
+	for i := range rubric.Criteria {
+		c := &rubric.Criteria[i]
+		criterion := contracts.RubricCriterionDTO{
+			ID:          c.ID,
+			Title:       c.Title,
+			Description: c.Description,
+			MaxPoints:   c.MaxPoints(),
+			Levels:      make([]contracts.RubricLevelDTO, 0, len(c.Levels)),
+		}
+		for _, l := range c.Levels {
+			criterion.Levels = append(criterion.Levels, contracts.RubricLevelDTO{ID: l.ID, Points: l.Points, Descriptor: l.Descriptor})
+		}
+		dto.Criteria = append(dto.Criteria, criterion)
+	}
+	return dto
+}
+
+// scoreRubric checks the criterion scores of a rubric grading and returns
+// them with their total. Every criterion must be scored exactly once.
+func scoreRubric(rubric *models.Rubric, input contracts.RubricGradeInput) ([]models.RubricScore, float64, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	var total float64
+	scores := make([]models.RubricScore, 0, len(input.Scores))
+	seen := make(map[uint]bool, len(input.Scores))
+	for i, entry := range input.Scores {
+		field := fmt.Sprintf("scores[%d]", i)
+		criterion := rubric.Criterion(entry.CriterionID)
+		switch {
+		case criterion == nil:
+			errs = append(errs, contracts.ValidationError{Field: field + ".criterion_id", Message: "criterion does not belong to the assignment's rubric"})
+			continue
+		case seen[criterion.ID]:
+			errs = append(errs, contracts.ValidationError{Field: field, Message: "criterion is scored more than once"})
+			continue
+		}
+		seen[criterion.ID] = true
+
+		score := models.RubricScore{CriterionID: criterion.ID, Comment: strings.TrimSpace(entry.Comment)}
+		if entry.LevelID != nil {
+			level := criterion.Level(*entry.LevelID)
+			if level == nil {
+				errs = append(errs, contracts.ValidationError{Field: field + ".level_id", Message: "level does not belong to the criterion"})
+				continue
+			}
+			score.LevelID = &level.ID
+			score.Points = level.Points
+		}
+		switch {
+		case entry.Points != nil:
+			score.Points = *entry.Points
+		case entry.LevelID == nil:
+			errs = append(errs, contracts.ValidationError{Field: field, Message: "level_id or points is required"})
+			continue
+		}
+		if math.IsNaN(score.Points) || score.Points < 0 || score.Points > criterion.MaxPoints() {
+			errs = append(errs, contracts.ValidationError{Field: field + ".points", Message: fmt.Sprintf("points must be between 0 and %g", criterion.MaxPoints())})
+		}
+		if len(score.Comment) > maxRubricCommentLength {
+			errs = append(errs, contracts.ValidationError{Field: field + ".comment", Message: "comment is too long"})
+		}
+		score.Points = roundScore(score.Points)
+		total += score.Points
+		scores = append(scores, score)
+	}
+
+	for _, c := range rubric.Criteria {
+		if !seen[c.ID] {
+			errs = append(errs, contracts.ValidationError{Field: "scores", Message: fmt.Sprintf("criterion %q is not scored", c.Title)})
+		}
+	}
+	return scores, total, errs
+}
+
+// mapToCriterionScoreDTOs lists a submission's rubric scores in the order
+// of the rubric's criteria.
+func mapToCriterionScoreDTOs(rubric *models.Rubric, scores []models.RubricScore) []contracts.CriterionScoreDTO {
+	byCriterion := make(map[uint]*models.RubricScore, len(scores))
+	for i := range scores {
+		byCriterion[scores[i].CriterionID] = &scores[i]
+	}
+
+	dtos := make([]contracts.CriterionScoreDTO, 0, len(scores))
+	for i := range rubric.Criteria {
+		c := &rubric.Criteria[i]
+		score := byCriterion[c.ID]
+		if score == nil {
+			continue
+		}
+		dto := contracts.CriterionScoreDTO{
+			CriterionID:    c.ID,
+			CriterionTitle: c.Title,
+			LevelID:        score.LevelID,
+			Points:         score.Points,
+			MaxPoints:      c.MaxPoints(),
+			Comment:        score.Comment,
+		}
+		if score.LevelID != nil {
+			if level := c.Level(*score.LevelID); level != nil {
+				dto.LevelDescriptor = level.Descriptor
+			}
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
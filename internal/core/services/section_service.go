@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+// SectionService manages course sections: the per-term classes of catalog
+// subjects.
+type SectionService struct {
+	sections    repositories.SectionRepository
+	subjects    repositories.SubjectRepository
+	terms       repositories.TermRepository
+	users       repositories.UserRepository
+	enrollments repositories.EnrollmentRepository
+	timetable   *TimetableService
+}
+
+func NewSectionService(
+	sections repositories.SectionRepository,
+	subjects repositories.SubjectRepository,
+	terms repositories.TermRepository,
+	users repositories.UserRepository,
+	enrollments repositories.EnrollmentRepository,
+	timetable *TimetableService,
+) *SectionService {
+	return &SectionService{sections: sections, subjects: subjects, terms: terms, users: users, enrollments: enrollments, timetable: timetable}
+}
+
+// ListSections lists sections, optionally restricted to a term (see
+// resolveTermParam) and/or a subject.
+func (s *SectionService) ListSections(ctx context.Context, term string, subjectID uint) ([]contracts.SectionDTO, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+	return s.list(ctx, repositories.SectionFilter{TermID: termID, SubjectID: subjectID})
+}
+
+// ListTeacherSections lists the sections a teacher teaches in a term, the
+// current one by default. Between terms the list is empty.
+func (s *SectionService) ListTeacherSections(ctx context.Context, teacherID uint, term string) ([]contracts.SectionDTO, error) {
+	if term == "" {
+		term = "current"
+	}
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		if errors.Is(err, contracts.ErrNoCurrentTerm) {
+			return []contracts.SectionDTO{}, nil
+		}
+		return nil, err
+	}
+	return s.list(ctx, repositories.SectionFilter{TermID: termID, TeacherID: teacherID})
+}
+
+func (s *SectionService) GetSection(ctx context.Context, id uint) (*contracts.SectionDTO, error) {
+	section, err := findSection(ctx, s.sections, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapToSectionDTO(section), nil
+}
+
+func (s *SectionService) CreateSection(ctx context.Context, input contracts.SectionInput) (*contracts.SectionDTO, error) {
+	input.Code = strings.TrimSpace(strings.ToUpper(input.Code))
+	input.DeliveryMode = strings.TrimSpace(strings.ToLower(input.DeliveryMode))
+	if input.DeliveryMode == "" {
+		input.DeliveryMode = models.DeliveryInPerson
+	}
+
+	errs := validateSectionFields(input.Code, input.Capacity, input.DeliveryMode)
+	if input.SubjectID == 0 {
+		errs = append(errs, contracts.ValidationError{Field: "subject_id", Message: "subject_id is required"})
+	}
+	if input.TermID == 0 {
+		errs = append(errs, contracts.ValidationError{Field: "term_id", Message: "term_id is required"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if _, err := s.subjects.FindByID(ctx, input.SubjectID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ValidationErrors{{Field: "subject_id", Message: "subject not found"}}
+		}
+		return nil, err
+	}
+	if _, err := s.terms.FindByID(ctx, input.TermID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ValidationErrors{{Field: "term_id", Message: "term not found"}}
+		}
+		return nil, err
+	}
+
+	section := &models.CourseSection{
+		SubjectID:    input.SubjectID,
+		TermID:       input.TermID,
+		Code:         input.Code,
+		Capacity:     input.Capacity,
+		DeliveryMode: input.DeliveryMode,
+	}
+	if len(input.TeacherIDs) > 0 {
+		teachers, err := loadTeachers(ctx, s.users, input.TeacherIDs)
+		if err != nil {
+			return nil, err
+		}
+		section.Teachers = teachers
+	}
+
+	if err := s.sections.Create(ctx, section); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, contracts.ErrSectionExists
+		}
+		return nil, err
+	}
+	return s.GetSection(ctx, section.ID)
+}
+
+// UpdateSection replaces the code, capacity, delivery mode and teachers of a
+// section. Capacity cannot drop below the number of enrolled students, and
+// raising it promotes students from the waitlist. New teachers must be free
+// whenever the section meets.
+func (s *SectionService) UpdateSection(ctx context.Context, id uint, input contracts.UpdateSectionInput) (*contracts.SectionDTO, error) {
+	section, err := findSection(ctx, s.sections, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Code = strings.TrimSpace(strings.ToUpper(input.Code))
+	input.DeliveryMode = strings.TrimSpace(strings.ToLower(input.DeliveryMode))
+	if errs := validateSectionFields(input.Code, input.Capacity, input.DeliveryMode); len(errs) > 0 {
+		return nil, errs
+	}
+
+	teachers := []models.User{}
+	if len(input.TeacherIDs) > 0 {
+		if teachers, err = loadTeachers(ctx, s.users, input.TeacherIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	var promoted []models.Enrollment
+	err = s.enrollments.Transaction(ctx, func(tx repositories.EnrollmentRepository) error {
+		// The section is locked before the term, as enrollments do.
+		locked, err := tx.LockSection(ctx, section.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return contracts.ErrSectionNotFound
+			}
+			return err
+		}
+		timetable := tx.Timetable()
+		if err := timetable.LockTerm(ctx, section.TermID); err != nil {
+			return err
+		}
+		if err := s.timetable.CheckTeachers(ctx, timetable, section, teachers); err != nil {
+			return err
+		}
+
+		if input.Capacity < locked.Capacity {
+			enrolled, err := tx.CountEnrolled(ctx, section.ID)
+			if err != nil {
+				return err
+			}
+			if int64(input.Capacity) < enrolled {
+				return contracts.ValidationErrors{{
+					Field:   "capacity",
+					Message: fmt.Sprintf("capacity cannot be below the %d students already enrolled", enrolled),
+				}}
+			}
+		}
+
+		section.Code = input.Code
+		section.Capacity = input.Capacity
+		section.DeliveryMode = input.DeliveryMode
+		sections := tx.Sections()
+		if err := sections.Save(ctx, section); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return contracts.ErrSectionExists
+			}
+			return err
+		}
+		if err := sections.ReplaceTeachers(ctx, section, teachers); err != nil {
+			return err
+		}
+
+		if input.Capacity > locked.Capacity {
+			promoted, err = promoteWaitlisted(ctx, tx, section)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	section.Teachers = teachers
+
+	// Emails only go out once the promotions are committed.
+	notifyPromoted(promoted, section)
+	return mapToSectionDTO(section), nil
+}
+
+func (s *SectionService) DeleteSection(ctx context.Context, id uint) error {
+	if err := s.sections.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrSectionNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrSectionInUse
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SectionService) list(ctx context.Context, filter repositories.SectionFilter) ([]contracts.SectionDTO, error) {
+	sections, err := s.sections.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.SectionDTO, 0, len(sections))
+	for i := range sections {
+		dtos = append(dtos, *mapToSectionDTO(&sections[i]))
+	}
+	return dtos, nil
+}
+
+func findSection(ctx context.Context, sections repositories.SectionRepository, id uint) (*models.CourseSection, error) {
+	section, err := sections.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrSectionNotFound
+		}
+		return nil, err
+	}
+	return section, nil
+}
+
+// teachesSection reports whether the user teaches the section, either as one
+// of its own teachers or as a teacher of its subject. Teachers and
+// Subject.Teachers must be preloaded.
+func teachesSection(section *models.CourseSection, userID uint) bool {
+	if section.HasTeacher(userID) {
+		return true
+	}
+	if section.Subject != nil {
+		for _, t := range section.Subject.Teachers {
+			if t.ID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mapToSectionDTO(section *models.CourseSection) *contracts.SectionDTO {
+	dto := &contracts.SectionDTO{
+		ID:           section.ID,
+		SubjectID:    section.SubjectID,
+		TermID:       section.TermID,
+		Code:         section.Code,
+		Capacity:     section.Capacity,
+		DeliveryMode: section.DeliveryMode,
+		Teachers:     make([]contracts.TeacherDTO, 0, len(section.Teachers)),
+	}
+	if section.Subject != nil {
+		dto.SubjectName = section.Subject.Name
+	}
+	if section.Term != nil {
+		dto.TermName = section.Term.Name
+	}
+	for _, t := range section.Teachers {
+		dto.Teachers = append(dto.Teachers, contracts.TeacherDTO{ID: t.ID, Name: t.Name, Email: t.Email})
+	}
+	return dto
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/pkg/auth"
+	"gorm.io/gorm"
+)
+
+// revokeUserSessions invalidates every access and refresh token the user
+// currently holds.
+func revokeUserSessions(ctx context.Context, refreshTokens repositories.RefreshTokenRepository, userID uint) error {
+	if err := auth.RevokeUserTokens(ctx, userID); err != nil {
+		return err
+	}
+	return refreshTokens.RevokeAllForUser(ctx, userID)
+}
+
+// tokenVersionStore keeps token versions in the users table for package
+// auth.
+type tokenVersionStore struct {
+	users repositories.UserRepository
+}
+
+// NewTokenVersionStore returns the store to pass to auth.SetVersionStore.
+func NewTokenVersionStore(users repositories.UserRepository) auth.VersionStore {
+	return &tokenVersionStore{users: users}
+}
+
+func (s *tokenVersionStore) TokenVersion(ctx context.Context, userID uint) (int64, error) {
+	version, err := s.users.TokenVersion(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, auth.ErrTokenRevoked
+	}
+	return version, err
+}
+
+func (s *tokenVersionStore) BumpTokenVersion(ctx context.Context, userID uint, atLeast int64) (int64, error) {
+	return s.users.BumpTokenVersion(ctx, userID, atLeast)
+}
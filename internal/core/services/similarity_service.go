@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/queue"
+	"github.com/arman300s/uni-portal/pkg/similarity"
+	"github.com/arman300s/uni-portal/pkg/storage"
+	"github.com/arman300s/uni-portal/pkg/tasks"
+)
+
+const (
+	defaultSimilarityMinPercent = 30
+	// maxSimilarityFileBytes caps how much of each file is compared.
+	maxSimilarityFileBytes   = 2 << 20
+	maxSimilaritySpans       = 50
+	similarityExcerptLength  = 200
+	maxSimilarityErrorLength = 500
+)
+
+// codeExtensions and textExtensions decide how a submitted file is
+// compared; other files are skipped unless they were sniffed as plain text.
+var (
+	codeExtensions = map[string]bool{
+		"c": true, "h": true, "cc": true, "cpp": true, "cxx": true, "hpp": true, "cs": true, "go": true,
+		"java": true, "kt": true, "kts": true, "scala": true, "js": true, "jsx": true, "ts": true,
+		"tsx": true, "py": true, "rb": true, "php": true, "rs": true, "swift": true, "m": true,
+		"sql": true, "sh": true, "lua": true, "pl": true, "r": true, "dart": true,
+	}
+	textExtensions = map[string]bool{
+		"txt": true, "md": true, "markdown": true, "rst": true, "tex": true, "docx": true,
+	}
+)
+
+// SimilarityService checks the submissions of an assignment for copied
+// work. Checks run in the worker: one is scheduled for when an assignment
+// closes, and teachers can ask for another at any time. Reports are only
+// shown to the teachers of the assignment.
+type SimilarityService struct {
+	similarity  repositories.SimilarityRepository
+	assignments repositories.AssignmentRepository
+	blobs       storage.BlobStore
+}
+
+func NewSimilarityService(
+	similarity repositories.SimilarityRepository,
+	assignments repositories.AssignmentRepository,
+	blobs storage.BlobStore,
+) *SimilarityService {
+	return &SimilarityService{similarity: similarity, assignments: assignments, blobs: blobs}
+}
+
+// scheduleSimilarityCheck enqueues a check for when the assignment stops
+// accepting submissions without an extension.
+func scheduleSimilarityCheck(assignment *models.Assignment) {
+	payload := tasks.CheckSimilarityPayload{AssignmentID: assignment.ID, Scheduled: true}
+	_ = queue.Enqueue(tasks.TypeCheckSimilarity, payload, max(0, time.Until(assignment.ClosesAt(nil))))
+}
+
+// GetReport returns the latest similarity report of an assignment the
+// teacher manages.
+func (s *SimilarityService) GetReport(ctx context.Context, teacherID, assignmentID uint) (*contracts.SimilarityReportDTO, error) {
+	if _, err := teacherAssignment(ctx, s.assignments, teacherID, assignmentID); err != nil {
+		return nil, err
+	}
+	report, err := s.similarity.FindReport(ctx, assignmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrNoSimilarityReport
+		}
+		return nil, err
+	}
+	return mapToSimilarityReportDTO(report), nil
+}
+
+// RequestCheck queues a new check of an assignment's submissions, for
+// example after students with extensions have handed in. The previous
+// matches stay visible until it finishes.
+func (s *SimilarityService) RequestCheck(ctx context.Context, teacherID, assignmentID uint) (*contracts.SimilarityReportDTO, error) {
+	if _, err := teacherAssignment(ctx, s.assignments, teacherID, assignmentID); err != nil {
+		return nil, err
+	}
+
+	report := &models.SimilarityReport{AssignmentID: assignmentID, Status: models.SimilarityQueued, RequestedAt: time.Now()}
+	if err := s.similarity.SaveReport(ctx, report, "status", "error", "requested_at"); err != nil {
+		return nil, err
+	}
+	if err := queue.Enqueue(tasks.TypeCheckSimilarity, tasks.CheckSimilarityPayload{AssignmentID: assignmentID}, 0); err != nil {
+		return nil, err
+	}
+	return s.GetReport(ctx, teacherID, assignmentID)
+}
+
+// Run compares every pair of submissions to an assignment, text with text
+// and code with code, and replaces the assignment's report. Pairs scoring
+// below SIMILARITY_MIN_PERCENT are left out.
+func (s *SimilarityService) Run(ctx context.Context, payload tasks.CheckSimilarityPayload) error {
+	assignment, err := s.assignments.FindByID(ctx, payload.AssignmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	now := time.Now()
+	if payload.Scheduled && now.Before(assignment.ClosesAt(nil)) {
+		return nil
+	}
+
+	report := &models.SimilarityReport{AssignmentID: assignment.ID, Status: models.SimilarityRunning, RequestedAt: now, StartedAt: &now}
+	if err := s.similarity.SaveReport(ctx, report, "status", "error", "started_at", "finished_at"); err != nil {
+		return err
+	}
+
+	matches, err := s.compare(ctx, assignment, report)
+	finished := time.Now()
+	report.FinishedAt = &finished
+	if err != nil {
+		report.Status = models.SimilarityFailed
+		report.Error = truncate(err.Error(), maxSimilarityErrorLength)
+		if saveErr := s.similarity.SaveReport(ctx, report, "status", "error", "finished_at"); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		return err
+	}
+
+	report.Status = models.SimilarityDone
+	return s.similarity.Transaction(ctx, func(tx repositories.SimilarityRepository) error {
+		if err := tx.ReplaceMatches(ctx, report.ID, matches); err != nil {
+			return err
+		}
+		return tx.SaveReport(ctx, report, "status", "error", "submissions", "skipped_files", "finished_at")
+	})
+}
+
+// corpus is the text or code of one submission: its files joined into one
+// document, with where each file starts and ends.
+type corpus struct {
+	content string
+	files   []corpusFile
+	doc     *similarity.Document
+}
+
+type corpusFile struct {
+	id         uint
+	start, end int
+}
+
+func (s *SimilarityService) compare(ctx context.Context, assignment *models.Assignment, report *models.SimilarityReport) ([]models.SimilarityMatch, error) {
+	submissions, err := s.assignments.ListSubmissions(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	report.Submissions = len(submissions)
+	report.SkippedFiles = 0
+
+	contents := map[string]string{}
+	texts := make([]*corpus, len(submissions))
+	codes := make([]*corpus, len(submissions))
+	for i, sub := range submissions {
+		texts[i], codes[i] = &corpus{}, &corpus{}
+		for _, file := range sub.Files {
+			kind := similarityKind(&file)
+			if kind == "" {
+				report.SkippedFiles++
+				continue
+			}
+			content, ok := contents[file.Checksum]
+			if !ok {
+				content, err = s.readText(ctx, &file)
+				if err != nil {
+					return nil, err
+				}
+				contents[file.Checksum] = content
+			}
+			if content == "" {
+				report.SkippedFiles++
+				continue
+			}
+			target := texts[i]
+			if kind == models.SimilarityCode {
+				target = codes[i]
+			}
+			target.add(file.ID, content)
+		}
+		texts[i].doc = texts[i].fingerprint(similarity.KindText, similarity.NewText)
+		codes[i].doc = codes[i].fingerprint(similarity.KindCode, similarity.NewCode)
+	}
+
+	minScore := float64(envInt("SIMILARITY_MIN_PERCENT", defaultSimilarityMinPercent)) / 100
+	var matches []models.SimilarityMatch
+	for i := range submissions {
+		for j := i + 1; j < len(submissions); j++ {
+			for _, kind := range []string{models.SimilarityText, models.SimilarityCode} {
+				a, b := texts[i], texts[j]
+				if kind == models.SimilarityCode {
+					a, b = codes[i], codes[j]
+				}
+				score := a.doc.Similarity(b.doc)
+				if score == 0 || score < minScore {
+					continue
+				}
+				matches = append(matches, models.SimilarityMatch{
+					Kind:          kind,
+					SubmissionAID: submissions[i].ID,
+					SubmissionBID: submissions[j].ID,
+					Score:         score,
+					Spans:         matchSpans(a, b),
+				})
+			}
+		}
+	}
+	return matches, nil
+}
+
+// readText returns the text of a file to compare, or "" when it has none.
+func (s *SimilarityService) readText(ctx context.Context, file *models.SubmissionFile) (string, error) {
+	body, err := s.blobs.Get(ctx, blobKey(file.Checksum))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, maxSimilarityFileBytes))
+	if err != nil {
+		return "", err
+	}
+
+	if fileExtension(file.Name) == "docx" {
+		text, err := similarity.ExtractDocx(data)
+		if err != nil {
+			return "", nil
+		}
+		return text, nil
+	}
+	if !utf8.Valid(data) {
+		return strings.ToValidUTF8(string(data), ""), nil
+	}
+	return string(data), nil
+}
+
+func (c *corpus) add(fileID uint, content string) {
+	start := len(c.content)
+	c.content += content
+	c.files = append(c.files, corpusFile{id: fileID, start: start, end: len(c.content)})
+}
+
+// fingerprint fingerprints every file on its own, so that the end of one
+// file and the start of the next never form a shingle, and joins the
+// results.
+func (c *corpus) fingerprint(kind similarity.Kind, fingerprint func(string) *similarity.Document) *similarity.Document {
+	docs := make([]*similarity.Document, len(c.files))
+	offsets := make([]int, len(c.files))
+	for i, file := range c.files {
+		docs[i] = fingerprint(c.content[file.start:file.end])
+		offsets[i] = file.start
+	}
+	return similarity.Join(kind, docs, offsets)
+}
+
+// locate maps a span of the corpus to the file it starts in, cutting it off
+// at the file's bounds. It reports false when nothing of the span is left.
+func (c *corpus) locate(span similarity.Span) (corpusFile, int, int, bool) {
+	i := sort.Search(len(c.files), func(i int) bool { return c.files[i].end > span.Start })
+	if i == len(c.files) {
+		return corpusFile{}, 0, 0, false
+	}
+	file := c.files[i]
+	start, end := max(span.Start, file.start), min(span.End, file.end)
+	return file, start, end, start < end
+}
+
+// matchSpans lists the passages two submissions share, the longest first
+// when there are too many to keep.
+func matchSpans(a, b *corpus) []models.SimilaritySpan {
+	matches := a.doc.Matches(b.doc)
+	if len(matches) > maxSimilaritySpans {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].A.End-matches[i].A.Start > matches[j].A.End-matches[j].A.Start
+		})
+		matches = matches[:maxSimilaritySpans]
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].A.Start < matches[j].A.Start })
+	}
+
+	spans := make([]models.SimilaritySpan, 0, len(matches))
+	for _, m := range matches {
+		fileA, startA, endA, okA := a.locate(m.A)
+		fileB, startB, endB, okB := b.locate(m.B)
+		if !okA || !okB {
+			continue
+		}
+		spans = append(spans, models.SimilaritySpan{
+			FileAID:  fileA.id,
+			StartA:   startA - fileA.start,
+			EndA:     endA - fileA.start,
+			ExcerptA: truncate(a.content[startA:endA], similarityExcerptLength),
+			FileBID:  fileB.id,
+			StartB:   startB - fileB.start,
+			EndB:     endB - fileB.start,
+			ExcerptB: truncate(b.content[startB:endB], similarityExcerptLength),
+		})
+	}
+	return spans
+}
+
+// similarityKind tells whether a file is compared as text or code, or ""
+// when it is skipped.
+func similarityKind(file *models.SubmissionFile) string {
+	ext := fileExtension(file.Name)
+	switch {
+	case codeExtensions[ext]:
+		return models.SimilarityCode
+	case textExtensions[ext], strings.HasPrefix(file.ContentType, "text/plain"):
+		return models.SimilarityText
+	}
+	return ""
+}
+
+// truncate cuts s to at most n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func mapToSimilarityReportDTO(report *models.SimilarityReport) *contracts.SimilarityReportDTO {
+	dto := &contracts.SimilarityReportDTO{
+		AssignmentID: report.AssignmentID,
+		Status:       report.Status,
+		Error:        report.Error,
+		Submissions:  report.Submissions,
+		SkippedFiles: report.SkippedFiles,
+		RequestedAt:  report.RequestedAt,
+		StartedAt:    report.StartedAt,
+		FinishedAt:   report.FinishedAt,
+		Matches:      make([]contracts.SimilarityMatchDTO, 0, len(report.Matches)),
+	}
+	for _, m := range report.Matches {
+		match := contracts.SimilarityMatchDTO{
+			ID:           m.ID,
+			Kind:         m.Kind,
+			ScorePercent: roundScore(m.Score * 100),
+			SubmissionA:  mapToSimilarSubmissionDTO(m.SubmissionAID, m.SubmissionA, report.StartedAt),
+			SubmissionB:  mapToSimilarSubmissionDTO(m.SubmissionBID, m.SubmissionB, report.StartedAt),
+			Spans:        make([]contracts.SimilaritySpanDTO, 0, len(m.Spans)),
+		}
+		for _, span := range m.Spans {
+			match.Spans = append(match.Spans, contracts.SimilaritySpanDTO{
+				A: mapToSpanLocationDTO(span.FileAID, span.FileA, span.StartA, span.EndA, span.ExcerptA),
+				B: mapToSpanLocationDTO(span.FileBID, span.FileB, span.StartB, span.EndB, span.ExcerptB),
+			})
+		}
+		dto.Matches = append(dto.Matches, match)
+	}
+	return dto
+}
+
+func mapToSimilarSubmissionDTO(id uint, submission *models.Submission, checkedAt *time.Time) contracts.SimilarSubmissionDTO {
+	dto := contracts.SimilarSubmissionDTO{SubmissionID: id}
+	if submission == nil {
+		return dto
+	}
+	dto.StudentID = submission.StudentID
+	dto.Resubmitted = checkedAt != nil && submission.SubmittedAt.After(*checkedAt)
+	if submission.Student != nil {
+		dto.StudentName = submission.Student.Name
+	}
+	return dto
+}
+
+func mapToSpanLocationDTO(fileID uint, file *models.SubmissionFile, start, end int, excerpt string) contracts.SpanLocationDTO {
+	dto := contracts.SpanLocationDTO{FileID: fileID, Start: start, End: end, Excerpt: excerpt}
+	if file != nil {
+		dto.FileName = file.Name
+	}
+	return dto
+}
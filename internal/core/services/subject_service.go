@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -18,12 +17,15 @@ import (
 
 // SubjectService manages subject-related logic.
 type SubjectService struct {
-	subjects repositories.SubjectRepository
-	users    repositories.UserRepository
+	subjects    repositories.SubjectRepository
+	users       repositories.UserRepository
+	terms       repositories.TermRepository
+	enrollments repositories.EnrollmentRepository
+	cache       cache.Cache
 }
 
-func NewSubjectService(subjects repositories.SubjectRepository, users repositories.UserRepository) *SubjectService {
-	return &SubjectService{subjects: subjects, users: users}
+func NewSubjectService(subjects repositories.SubjectRepository, users repositories.UserRepository, terms repositories.TermRepository, enrollments repositories.EnrollmentRepository, c cache.Cache) *SubjectService {
+	return &SubjectService{subjects: subjects, users: users, terms: terms, enrollments: enrollments, cache: c}
 }
 
 func (s *SubjectService) CreateSubject(ctx context.Context, input contracts.SubjectInput) (*models.Subject, error) {
@@ -34,43 +36,57 @@ func (s *SubjectService) CreateSubject(ctx context.Context, input contracts.Subj
 	subject := &models.Subject{
 		Name:        strings.TrimSpace(input.Name),
 		Description: strings.TrimSpace(input.Description),
+		CreditHours: input.CreditHours,
+	}
+	if subject.CreditHours == 0 {
+		subject.CreditHours = defaultCreditHours
 	}
 
 	if len(input.TeacherIDs) > 0 {
-		teachers, err := s.fetchTeacherUsers(ctx, input.TeacherIDs)
+		teachers, err := loadTeachers(ctx, s.users, input.TeacherIDs)
 		if err != nil {
 			return nil, err
 		}
 		subject.Teachers = teachers
 	}
 
+	if len(input.TermIDs) > 0 {
+		terms, err := s.fetchTerms(ctx, input.TermIDs)
+		if err != nil {
+			return nil, err
+		}
+		subject.Terms = terms
+	}
+
 	if err := s.subjects.Create(ctx, subject); err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
 
 	return subject, nil
 }
 
-func (s *SubjectService) ListSubjects(ctx context.Context) ([]models.Subject, error) {
-	cacheKey := "subjects:all"
+// ListSubjects lists the catalog, optionally restricted to the subjects
+// offered in a term (see resolveTermParam).
+func (s *SubjectService) ListSubjects(ctx context.Context, term string) ([]models.Subject, error) {
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
 
-	cached, err := cache.RDB.Get(ctx, cacheKey).Bytes()
-	if err == nil {
-		var subjects []models.Subject
-		if json.Unmarshal(cached, &subjects) == nil {
-			return subjects, nil
-		}
+	cacheKey := "subjects:all"
+	if termID != 0 {
+		cacheKey = fmt.Sprintf("subjects:term:%d", termID)
 	}
 
-	subjects, err := s.subjects.List(ctx)
+	var subjects []models.Subject
+	err = cache.Remember(ctx, s.cache, cacheKey, []string{cache.TagSubjects, cache.TagUsers}, 5*time.Minute, &subjects,
+		func(ctx context.Context) (any, error) {
+			return s.subjects.List(ctx, repositories.SubjectFilter{TermID: termID})
+		})
 	if err != nil {
 		return nil, err
 	}
-
-	// Save to Redis
-	data, _ := json.Marshal(subjects)
-	cache.RDB.Set(ctx, cacheKey, data, 5*time.Minute)
-
 	return subjects, nil
 }
 
@@ -86,6 +102,10 @@ func (s *SubjectService) GetSubject(ctx context.Context, id uint) (*models.Subje
 }
 
 func (s *SubjectService) UpdateSubject(ctx context.Context, id uint, input contracts.SubjectInput) error {
+	if input.CreditHours < 0 || input.CreditHours > maxCreditHours {
+		return contracts.ValidationErrors{{Field: "credit_hours", Message: fmt.Sprintf("credit_hours must be between 0 and %d", maxCreditHours)}}
+	}
+
 	subject, err := s.subjects.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -100,9 +120,12 @@ func (s *SubjectService) UpdateSubject(ctx context.Context, id uint, input contr
 	if desc := strings.TrimSpace(input.Description); desc != "" {
 		subject.Description = desc
 	}
+	if input.CreditHours > 0 {
+		subject.CreditHours = input.CreditHours
+	}
 
 	if len(input.TeacherIDs) > 0 {
-		teachers, err := s.fetchTeacherUsers(ctx, input.TeacherIDs)
+		teachers, err := loadTeachers(ctx, s.users, input.TeacherIDs)
 		if err != nil {
 			return err
 		}
@@ -112,7 +135,22 @@ func (s *SubjectService) UpdateSubject(ctx context.Context, id uint, input contr
 		subject.Teachers = teachers
 	}
 
-	return s.subjects.Save(ctx, subject)
+	if len(input.TermIDs) > 0 {
+		terms, err := s.fetchTerms(ctx, input.TermIDs)
+		if err != nil {
+			return err
+		}
+		if err := s.subjects.ReplaceTerms(ctx, subject, terms); err != nil {
+			return err
+		}
+		subject.Terms = terms
+	}
+
+	if err := s.subjects.Save(ctx, subject); err != nil {
+		return err
+	}
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
+	return nil
 }
 
 func (s *SubjectService) DeleteSubject(ctx context.Context, id uint) error {
@@ -120,17 +158,33 @@ func (s *SubjectService) DeleteSubject(ctx context.Context, id uint) error {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return contracts.ErrSubjectNotFound
 		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrSubjectInUse
+		}
 		return err
 	}
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
 	return nil
 }
 
-func (s *SubjectService) ListSubjectsForTeacher(ctx context.Context, teacherID uint) ([]models.Subject, error) {
-	return s.subjects.ListByTeacherID(ctx, teacherID)
+func (s *SubjectService) fetchTerms(ctx context.Context, ids []uint) ([]models.Term, error) {
+	terms, err := s.terms.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) != len(ids) {
+		return nil, contracts.ValidationErrors{contracts.ValidationError{
+			Field:   "term_ids",
+			Message: "one or more terms not found",
+		}}
+	}
+	return terms, nil
 }
 
-func (s *SubjectService) fetchTeacherUsers(ctx context.Context, ids []uint) ([]models.User, error) {
-	users, err := s.users.FindByIDs(ctx, ids)
+// loadTeachers loads the users with the given IDs and checks that each of
+// them may teach.
+func loadTeachers(ctx context.Context, repo repositories.UserRepository, ids []uint) ([]models.User, error) {
+	users, err := repo.FindByIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +208,7 @@ func (s *SubjectService) fetchTeacherUsers(ctx context.Context, ids []uint) ([]m
 				Message: fmt.Sprintf("invalid teacher id %d", u.ID),
 			}}
 		}
-		if u.Role == nil || u.Role.Name != "teacher" {
+		if !u.Role.HasPermission(models.PermSubjectsTeach) {
 			return nil, contracts.ValidationErrors{contracts.ValidationError{
 				Field:   "teacher_ids",
 				Message: fmt.Sprintf("user %d is not a teacher", u.ID),
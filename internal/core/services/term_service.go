@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+// TermService manages academic terms.
+type TermService struct {
+	terms repositories.TermRepository
+	cache cache.Cache
+}
+
+func NewTermService(terms repositories.TermRepository, c cache.Cache) *TermService {
+	return &TermService{terms: terms, cache: c}
+}
+
+func (s *TermService) ListTerms(ctx context.Context) ([]contracts.TermDTO, error) {
+	terms, err := s.terms.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dtos := make([]contracts.TermDTO, 0, len(terms))
+	for i := range terms {
+		dtos = append(dtos, *mapToTermDTO(&terms[i], now))
+	}
+	return dtos, nil
+}
+
+func (s *TermService) GetTerm(ctx context.Context, id uint) (*contracts.TermDTO, error) {
+	term, err := findTerm(ctx, s.terms, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapToTermDTO(term, time.Now()), nil
+}
+
+// CurrentTerm returns the term in session right now.
+func (s *TermService) CurrentTerm(ctx context.Context) (*contracts.TermDTO, error) {
+	term, err := currentTerm(ctx, s.terms)
+	if err != nil {
+		return nil, err
+	}
+	return mapToTermDTO(term, time.Now()), nil
+}
+
+func (s *TermService) CreateTerm(ctx context.Context, input contracts.TermInput) (*contracts.TermDTO, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if errs := validateTermInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+	if err := s.ensureNameFree(ctx, input.Name, 0); err != nil {
+		return nil, err
+	}
+
+	term := &models.Term{}
+	applyTermInput(term, input)
+	if err := s.terms.Create(ctx, term); err != nil {
+		return nil, err
+	}
+	// Cached subject listings are keyed by term and show its offerings.
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
+	return mapToTermDTO(term, time.Now()), nil
+}
+
+// UpdateTerm replaces every field of a term.
+func (s *TermService) UpdateTerm(ctx context.Context, id uint, input contracts.TermInput) (*contracts.TermDTO, error) {
+	term, err := findTerm(ctx, s.terms, id)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	if errs := validateTermInput(input); len(errs) > 0 {
+		return nil, errs
+	}
+	if err := s.ensureNameFree(ctx, input.Name, term.ID); err != nil {
+		return nil, err
+	}
+
+	applyTermInput(term, input)
+	if err := s.terms.Save(ctx, term); err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
+	return mapToTermDTO(term, time.Now()), nil
+}
+
+func (s *TermService) DeleteTerm(ctx context.Context, id uint) error {
+	if err := s.terms.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrTermNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return contracts.ErrTermInUse
+		}
+		return err
+	}
+	// Subjects lose their offering in the deleted term.
+	invalidateCache(ctx, s.cache, cache.TagSubjects)
+	return nil
+}
+
+func (s *TermService) ensureNameFree(ctx context.Context, name string, selfID uint) error {
+	existing, err := s.terms.FindByName(ctx, name)
+	if err == nil {
+		if existing.ID != selfID {
+			return contracts.ErrTermExists
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func findTerm(ctx context.Context, terms repositories.TermRepository, id uint) (*models.Term, error) {
+	term, err := terms.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrTermNotFound
+		}
+		return nil, err
+	}
+	return term, nil
+}
+
+// currentTerm resolves the term in session right now. It returns
+// contracts.ErrNoCurrentTerm between terms.
+func currentTerm(ctx context.Context, terms repositories.TermRepository) (*models.Term, error) {
+	term, err := terms.FindCurrent(ctx, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrNoCurrentTerm
+		}
+		return nil, err
+	}
+	return term, nil
+}
+
+// resolveTermParam turns a "term" query parameter into a term ID. An empty
+// value means no filter, "current" selects the term in session and anything
+// else must be the ID of an existing term.
+func resolveTermParam(ctx context.Context, terms repositories.TermRepository, raw string) (uint, error) {
+	raw = strings.TrimSpace(raw)
+	switch raw {
+	case "":
+		return 0, nil
+	case "current":
+		term, err := currentTerm(ctx, terms)
+		if err != nil {
+			return 0, err
+		}
+		return term.ID, nil
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil || id == 0 {
+		return 0, contracts.ValidationErrors{{Field: "term", Message: "term must be a term id or \"current\""}}
+	}
+	term, err := findTerm(ctx, terms, uint(id))
+	if err != nil {
+		return 0, err
+	}
+	return term.ID, nil
+}
+
+func applyTermInput(term *models.Term, input contracts.TermInput) {
+	term.Name = input.Name
+	term.StartDate = input.StartDate
+	term.EndDate = input.EndDate
+	term.RegistrationStart = input.RegistrationStart
+	term.RegistrationEnd = input.RegistrationEnd
+	term.AddDropStart = input.AddDropStart
+	term.AddDropEnd = input.AddDropEnd
+}
+
+func mapToTermDTO(term *models.Term, now time.Time) *contracts.TermDTO {
+	return &contracts.TermDTO{
+		ID:                term.ID,
+		Name:              term.Name,
+		StartDate:         term.StartDate,
+		EndDate:           term.EndDate,
+		RegistrationStart: term.RegistrationStart,
+		RegistrationEnd:   term.RegistrationEnd,
+		AddDropStart:      term.AddDropStart,
+		AddDropEnd:        term.AddDropEnd,
+		Current:           term.Contains(now),
+		RegistrationOpen:  term.RegistrationOpen(now),
+		AddDropOpen:       term.AddDropOpen(now),
+	}
+}
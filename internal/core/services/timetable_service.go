@@ -0,0 +1,520 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+// Kinds of timetable conflicts.
+const (
+	conflictRoom    = "room"
+	conflictTeacher = "teacher"
+	conflictStudent = "student"
+)
+
+const minutesPerDay = 24 * 60
+
+var weekdays = [...]string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// TimetableService manages the weekly meetings of sections and keeps rooms,
+// teachers and students from being booked twice at the same time. Only
+// sections of the same term can clash.
+type TimetableService struct {
+	timetable   repositories.TimetableRepository
+	sections    repositories.SectionRepository
+	rooms       repositories.RoomRepository
+	enrollments repositories.EnrollmentRepository
+	terms       repositories.TermRepository
+}
+
+func NewTimetableService(
+	timetable repositories.TimetableRepository,
+	sections repositories.SectionRepository,
+	rooms repositories.RoomRepository,
+	enrollments repositories.EnrollmentRepository,
+	terms repositories.TermRepository,
+) *TimetableService {
+	return &TimetableService{timetable: timetable, sections: sections, rooms: rooms, enrollments: enrollments, terms: terms}
+}
+
+// GetMeetings returns the weekly meetings of a section.
+func (s *TimetableService) GetMeetings(ctx context.Context, sectionID uint) ([]contracts.MeetingDTO, error) {
+	if _, err := findSection(ctx, s.sections, sectionID); err != nil {
+		return nil, err
+	}
+	meetings, err := s.timetable.ListMeetings(ctx, []uint{sectionID})
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]contracts.MeetingDTO, 0, len(meetings))
+	for i := range meetings {
+		dtos = append(dtos, mapToMeetingDTO(&meetings[i]))
+	}
+	return dtos, nil
+}
+
+// ReplaceMeetings replaces the weekly meetings of a section. It fails with a
+// *contracts.ScheduleConflictError when the new pattern would double-book a
+// room, one of the section's teachers or one of its enrolled students.
+func (s *TimetableService) ReplaceMeetings(ctx context.Context, sectionID uint, input contracts.MeetingsInput) ([]contracts.MeetingDTO, error) {
+	section, err := findSection(ctx, s.sections, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	meetings, err := s.parseMeetings(ctx, section, input.Meetings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.timetable.Transaction(ctx, func(tx repositories.TimetableRepository) error {
+		if err := tx.LockTerm(ctx, section.TermID); err != nil {
+			return err
+		}
+		schedule, err := tx.LoadTerm(ctx, section.TermID)
+		if err != nil {
+			return err
+		}
+
+		candidate := make([]models.SectionMeeting, 0, len(schedule.Meetings)+len(meetings))
+		for _, m := range schedule.Meetings {
+			if m.SectionID != section.ID {
+				candidate = append(candidate, m)
+			}
+		}
+		for _, m := range meetings {
+			m.Section = section
+			candidate = append(candidate, m)
+		}
+		if conflicts := findConflicts(candidate, schedule.Students, section.ID); len(conflicts) > 0 {
+			return &contracts.ScheduleConflictError{Conflicts: conflicts}
+		}
+
+		return tx.ReplaceMeetings(ctx, section.ID, meetings)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetMeetings(ctx, section.ID)
+}
+
+// StudentTimetable returns the weekly meetings of the sections a student is
+// enrolled in for a term, the current one by default. Waitlisted sections
+// are left out. Between terms the timetable is empty.
+func (s *TimetableService) StudentTimetable(ctx context.Context, studentID uint, term string) ([]contracts.TimetableEntryDTO, error) {
+	termID, err := s.timetableTerm(ctx, term)
+	if err != nil || termID == 0 {
+		return []contracts.TimetableEntryDTO{}, err
+	}
+
+	enrollments, err := s.enrollments.ListByStudent(ctx, studentID, termID)
+	if err != nil {
+		return nil, err
+	}
+	sections := make([]models.CourseSection, 0, len(enrollments))
+	for _, e := range enrollments {
+		if e.Status == models.EnrollmentEnrolled && e.Section != nil {
+			sections = append(sections, *e.Section)
+		}
+	}
+	return s.timetableFor(ctx, sections)
+}
+
+// TeacherTimetable returns the weekly meetings of the sections a teacher
+// teaches in a term, the current one by default. Between terms the
+// timetable is empty.
+func (s *TimetableService) TeacherTimetable(ctx context.Context, teacherID uint, term string) ([]contracts.TimetableEntryDTO, error) {
+	termID, err := s.timetableTerm(ctx, term)
+	if err != nil || termID == 0 {
+		return []contracts.TimetableEntryDTO{}, err
+	}
+
+	sections, err := s.sections.List(ctx, repositories.SectionFilter{TermID: termID, TeacherID: teacherID})
+	if err != nil {
+		return nil, err
+	}
+	return s.timetableFor(ctx, sections)
+}
+
+// ConflictReport lists every clash in a term, the current one by default.
+// Clashes can predate the checks, for example after students were enrolled
+// before meetings were scheduled.
+func (s *TimetableService) ConflictReport(ctx context.Context, term string) (*contracts.ConflictReportDTO, error) {
+	if term == "" {
+		term = "current"
+	}
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if err != nil {
+		return nil, err
+	}
+	t, err := findTerm(ctx, s.terms, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.timetable.LoadTerm(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := findConflicts(schedule.Meetings, schedule.Students, 0)
+	if conflicts == nil {
+		conflicts = []contracts.ConflictDTO{}
+	}
+	return &contracts.ConflictReportDTO{TermID: t.ID, TermName: t.Name, Conflicts: conflicts}, nil
+}
+
+// CheckTeachers reports, as a *contracts.ScheduleConflictError, whether
+// giving the section these teachers would make any of them teach two
+// sections at once. tx must hold the term's lock (see LockTerm) until the
+// teachers are saved.
+func (s *TimetableService) CheckTeachers(ctx context.Context, tx repositories.TimetableRepository, section *models.CourseSection, teachers []models.User) error {
+	schedule, err := tx.LoadTerm(ctx, section.TermID)
+	if err != nil {
+		return err
+	}
+
+	changed := *section
+	changed.Teachers = teachers
+	for i := range schedule.Meetings {
+		if schedule.Meetings[i].SectionID == section.ID {
+			schedule.Meetings[i].Section = &changed
+		}
+	}
+	return conflictsOfKind(findConflicts(schedule.Meetings, schedule.Students, section.ID), conflictTeacher)
+}
+
+// CheckStudent reports, as a *contracts.ScheduleConflictError, whether the
+// section meets at the same time as one the student is already enrolled in.
+// tx must hold the term's lock (see LockTerm) until the enrollment is saved.
+func (s *TimetableService) CheckStudent(ctx context.Context, tx repositories.TimetableRepository, studentID uint, section *models.CourseSection) error {
+	schedule, err := tx.LoadTerm(ctx, section.TermID)
+	if err != nil {
+		return err
+	}
+	return studentConflicts(schedule, studentID, section.ID)
+}
+
+func (s *TimetableService) timetableTerm(ctx context.Context, term string) (uint, error) {
+	if term == "" {
+		term = "current"
+	}
+	termID, err := resolveTermParam(ctx, s.terms, term)
+	if errors.Is(err, contracts.ErrNoCurrentTerm) {
+		return 0, nil
+	}
+	return termID, err
+}
+
+func (s *TimetableService) timetableFor(ctx context.Context, sections []models.CourseSection) ([]contracts.TimetableEntryDTO, error) {
+	byID := make(map[uint]*models.CourseSection, len(sections))
+	ids := make([]uint, 0, len(sections))
+	for i := range sections {
+		byID[sections[i].ID] = &sections[i]
+		ids = append(ids, sections[i].ID)
+	}
+
+	meetings, err := s.timetable.ListMeetings(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]contracts.TimetableEntryDTO, 0, len(meetings))
+	for i := range meetings {
+		section := byID[meetings[i].SectionID]
+		entry := contracts.TimetableEntryDTO{
+			SectionID:    section.ID,
+			SectionCode:  section.Code,
+			DeliveryMode: section.DeliveryMode,
+			MeetingDTO:   mapToMeetingDTO(&meetings[i]),
+		}
+		if section.Subject != nil {
+			entry.SubjectName = section.Subject.Name
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseMeetings validates the meeting pattern of a section. Online sections
+// meet without a room; every other meeting needs one, and in-person
+// sections need a room that seats the whole section.
+func (s *TimetableService) parseMeetings(ctx context.Context, section *models.CourseSection, inputs []contracts.MeetingInput) ([]models.SectionMeeting, error) {
+	var (
+		errs    contracts.ValidationErrors
+		roomIDs []uint
+	)
+	meetings := make([]models.SectionMeeting, len(inputs))
+	for i, in := range inputs {
+		field := fmt.Sprintf("meetings[%d]", i)
+		m := &meetings[i]
+
+		var ok bool
+		if m.Weekday, ok = parseWeekday(in.Day); !ok {
+			errs = append(errs, contracts.ValidationError{Field: field + ".day", Message: "day must be a weekday such as monday or mon"})
+		}
+		start, startOK := parseClock(in.Start)
+		end, endOK := parseClock(in.End)
+		if !startOK {
+			errs = append(errs, contracts.ValidationError{Field: field + ".start", Message: "start must be a time such as 09:00"})
+		}
+		if !endOK {
+			errs = append(errs, contracts.ValidationError{Field: field + ".end", Message: "end must be a time such as 10:30"})
+		}
+		if startOK && endOK && start >= end {
+			errs = append(errs, contracts.ValidationError{Field: field + ".end", Message: "end must be after start"})
+		}
+		m.StartMinute, m.EndMinute = start, end
+
+		switch {
+		case section.DeliveryMode == models.DeliveryOnline && in.RoomID != nil:
+			errs = append(errs, contracts.ValidationError{Field: field + ".room_id", Message: "online sections do not meet in a room"})
+		case section.DeliveryMode != models.DeliveryOnline && in.RoomID == nil:
+			errs = append(errs, contracts.ValidationError{Field: field + ".room_id", Message: "room_id is required"})
+		case in.RoomID != nil:
+			m.RoomID = in.RoomID
+			roomIDs = append(roomIDs, *in.RoomID)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for i := range meetings {
+		for j := i + 1; j < len(meetings); j++ {
+			if meetings[i].Overlaps(&meetings[j]) {
+				errs = append(errs, contracts.ValidationError{
+					Field:   fmt.Sprintf("meetings[%d]", j),
+					Message: fmt.Sprintf("overlaps meetings[%d]", i),
+				})
+			}
+		}
+	}
+
+	rooms, err := s.rooms.FindRooms(ctx, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Room, len(rooms))
+	for i := range rooms {
+		byID[rooms[i].ID] = &rooms[i]
+	}
+	for i := range meetings {
+		m := &meetings[i]
+		if m.RoomID == nil {
+			continue
+		}
+		field := fmt.Sprintf("meetings[%d].room_id", i)
+		if m.Room = byID[*m.RoomID]; m.Room == nil {
+			errs = append(errs, contracts.ValidationError{Field: field, Message: "room not found"})
+			continue
+		}
+		if section.DeliveryMode == models.DeliveryInPerson && m.Room.Capacity < section.Capacity {
+			errs = append(errs, contracts.ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("room %s seats %d but the section holds %d", m.Room.Label(), m.Room.Capacity, section.Capacity),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return meetings, nil
+}
+
+// findConflicts finds every clash between two sections that meet at the same
+// time: a shared room, a shared teacher or shared students. Sections must
+// be preloaded with their teachers and rooms with their buildings. When
+// only is non-zero, just the clashes involving that section are returned.
+func findConflicts(meetings []models.SectionMeeting, students map[uint][]uint, only uint) []contracts.ConflictDTO {
+	sorted := make([]*models.SectionMeeting, len(meetings))
+	for i := range meetings {
+		sorted[i] = &meetings[i]
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].StartMinute < sorted[j].StartMinute
+	})
+
+	var conflicts []contracts.ConflictDTO
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if b.Weekday != a.Weekday || b.StartMinute >= a.EndMinute {
+				break
+			}
+			if a.SectionID == b.SectionID || (only != 0 && a.SectionID != only && b.SectionID != only) {
+				continue
+			}
+
+			base := contracts.ConflictDTO{
+				Day:      weekdayName(a.Weekday),
+				Start:    formatClock(max(a.StartMinute, b.StartMinute)),
+				End:      formatClock(min(a.EndMinute, b.EndMinute)),
+				Sections: []contracts.ConflictSectionDTO{conflictSection(a), conflictSection(b)},
+			}
+
+			if a.RoomID != nil && b.RoomID != nil && *a.RoomID == *b.RoomID {
+				c := base
+				c.Kind = conflictRoom
+				c.RoomID = *a.RoomID
+				if a.Room != nil {
+					c.Room = a.Room.Label()
+				}
+				conflicts = append(conflicts, c)
+			}
+			if a.Section != nil && b.Section != nil {
+				for _, t := range a.Section.Teachers {
+					if b.Section.HasTeacher(t.ID) {
+						c := base
+						c.Kind = conflictTeacher
+						c.TeacherID = t.ID
+						c.TeacherName = t.Name
+						conflicts = append(conflicts, c)
+					}
+				}
+			}
+			if shared := sharedIDs(students[a.SectionID], students[b.SectionID]); len(shared) > 0 {
+				c := base
+				c.Kind = conflictStudent
+				c.StudentIDs = shared
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+	return conflicts
+}
+
+// studentConflicts reports the clashes between the section and the sections
+// of the schedule the student is enrolled in.
+func studentConflicts(schedule *repositories.TermSchedule, studentID, sectionID uint) error {
+	students := map[uint][]uint{sectionID: {studentID}}
+	for id, ids := range schedule.Students {
+		if containsID(ids, studentID) {
+			students[id] = []uint{studentID}
+		}
+	}
+	return conflictsOfKind(findConflicts(schedule.Meetings, students, sectionID), conflictStudent)
+}
+
+func conflictsOfKind(conflicts []contracts.ConflictDTO, kind string) error {
+	var matching []contracts.ConflictDTO
+	for _, c := range conflicts {
+		if c.Kind == kind {
+			matching = append(matching, c)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	return &contracts.ScheduleConflictError{Conflicts: matching}
+}
+
+func conflictSection(m *models.SectionMeeting) contracts.ConflictSectionDTO {
+	dto := contracts.ConflictSectionDTO{SectionID: m.SectionID}
+	if m.Section != nil {
+		dto.SectionCode = m.Section.Code
+		if m.Section.Subject != nil {
+			dto.SubjectName = m.Section.Subject.Name
+		}
+	}
+	return dto
+}
+
+// sharedIDs returns the IDs present in both lists.
+func sharedIDs(a, b []uint) []uint {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	seen := make(map[uint]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	var shared []uint
+	for _, id := range b {
+		if seen[id] {
+			shared = append(shared, id)
+			seen[id] = false
+		}
+	}
+	return shared
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func mapToMeetingDTO(m *models.SectionMeeting) contracts.MeetingDTO {
+	dto := contracts.MeetingDTO{
+		ID:     m.ID,
+		Day:    weekdayName(m.Weekday),
+		Start:  formatClock(m.StartMinute),
+		End:    formatClock(m.EndMinute),
+		RoomID: m.RoomID,
+	}
+	if m.Room != nil {
+		dto.Room = m.Room.Label()
+	}
+	return dto
+}
+
+// parseWeekday accepts full or three-letter English weekday names and
+// returns the ISO weekday, 1 for Monday through 7 for Sunday.
+func parseWeekday(raw string) (int, bool) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if len(raw) < 3 {
+		return 0, false
+	}
+	for i, day := range weekdays {
+		if raw == day || raw == day[:3] {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+func weekdayName(weekday int) string {
+	if weekday < 1 || weekday > len(weekdays) {
+		return ""
+	}
+	return weekdays[weekday-1]
+}
+
+// parseClock parses "HH:MM" into minutes after midnight. "24:00" is allowed
+// so that a meeting can run until midnight.
+func parseClock(raw string) (int, bool) {
+	h, m, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	if !ok || len(m) != 2 || len(h) == 0 || len(h) > 2 {
+		return 0, false
+	}
+	hours, err := strconv.Atoi(h)
+	if err != nil || hours < 0 {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(m)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+	total := hours*60 + minutes
+	if total > minutesPerDay {
+		return 0, false
+	}
+	return total, true
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
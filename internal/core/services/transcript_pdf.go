@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/pkg/pdf"
+)
+
+// Layout of the transcript PDF, in points.
+const (
+	pdfMargin     = 50.0
+	pdfTop        = pdf.PageHeight - 50
+	pdfBottom     = 80.0
+	pdfLineHeight = 14.0
+	pdfRight      = pdf.PageWidth - pdfMargin
+)
+
+// Column positions of the course table. Numeric columns are right-aligned
+// at their position.
+const (
+	colSubject = pdfMargin
+	colSection = 300.0
+	colCredits = 400.0
+	colScore   = 455.0
+	colGrade   = 475.0
+	colPoints  = pdfRight
+)
+
+func verificationURL(hash string) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		base = "http://localhost:8079"
+	}
+	return strings.TrimRight(base, "/") + "/verify/transcript/" + hash
+}
+
+// transcriptWriter lays out text top to bottom and starts a new page when
+// the current one is full.
+type transcriptWriter struct {
+	doc   *pdf.Document
+	pages []*pdf.Page
+	y     float64
+}
+
+func (w *transcriptWriter) page() *pdf.Page {
+	return w.pages[len(w.pages)-1]
+}
+
+func (w *transcriptWriter) newPage() {
+	w.pages = append(w.pages, w.doc.AddPage())
+	w.y = pdfTop
+}
+
+// need starts a new page unless lines more lines fit on the current one.
+func (w *transcriptWriter) need(lines int) {
+	if w.y-float64(lines)*pdfLineHeight < pdfBottom {
+		w.newPage()
+	}
+}
+
+func (w *transcriptWriter) line(font pdf.Font, size float64, text string) {
+	w.need(1)
+	w.page().Text(pdfMargin, w.y, font, size, text)
+	w.y -= pdfLineHeight
+}
+
+func (w *transcriptWriter) rule() {
+	w.page().Line(pdfMargin, w.y+pdfLineHeight/2, pdfRight, w.y+pdfLineHeight/2, 0.5)
+	w.y -= pdfLineHeight / 2
+}
+
+func (w *transcriptWriter) courseHeader() {
+	p := w.page()
+	p.Text(colSubject, w.y, pdf.Bold, 9, "Subject")
+	p.Text(colSection, w.y, pdf.Bold, 9, "Section")
+	p.TextRight(colCredits, w.y, pdf.Bold, 9, "Credits")
+	p.TextRight(colScore, w.y, pdf.Bold, 9, "Score")
+	p.Text(colGrade, w.y, pdf.Bold, 9, "Grade")
+	p.TextRight(colPoints, w.y, pdf.Bold, 9, "Points")
+	w.y -= pdfLineHeight
+}
+
+func (w *transcriptWriter) course(c contracts.TranscriptCourseDTO) {
+	p := w.page()
+	name := c.SubjectName
+	if !c.Counted && !c.InProgress {
+		name += " (R)"
+	}
+	p.Text(colSubject, w.y, pdf.Regular, 9, w.doc.Truncate(name, pdf.Regular, 9, colSection-colSubject-10))
+	p.Text(colSection, w.y, pdf.Regular, 9, c.SectionCode)
+	p.TextRight(colCredits, w.y, pdf.Regular, 9, formatNumber(c.CreditHours))
+
+	if c.InProgress {
+		p.Text(colGrade, w.y, pdf.Regular, 9, "In progress")
+	} else {
+		p.TextRight(colScore, w.y, pdf.Regular, 9, fmt.Sprintf("%.2f", *c.FinalScore))
+		p.Text(colGrade, w.y, pdf.Regular, 9, c.Letter)
+		p.TextRight(colPoints, w.y, pdf.Regular, 9, fmt.Sprintf("%.2f", *c.GradePoints))
+	}
+	w.y -= pdfLineHeight
+}
+
+// renderTranscriptPDF lays out a transcript. Every page carries the
+// verification hash so that a single page can be checked on its own. It
+// fails with pdf.ErrUnsupportedText when fonts cannot show the transcript.
+func renderTranscriptPDF(t *contracts.TranscriptDTO, fonts *pdf.Fonts) ([]byte, error) {
+	w := &transcriptWriter{doc: pdf.New("Academic Transcript - "+t.Student.Name, fonts)}
+	w.newPage()
+
+	w.page().Text(pdfMargin, w.y, pdf.Bold, 18, "Official Academic Transcript")
+	w.y -= pdfLineHeight * 2
+	w.line(pdf.Regular, 10, fmt.Sprintf("Student: %s (ID %d)", t.Student.Name, t.Student.ID))
+	w.line(pdf.Regular, 10, "Email: "+t.Student.Email)
+	if t.IssuedAt != nil {
+		w.line(pdf.Regular, 10, "Issued: "+t.IssuedAt.UTC().Format("2 January 2006"))
+	}
+	w.line(pdf.Regular, 10, "Retake policy: "+t.RetakePolicy+" attempt counts towards the cumulative GPA")
+	w.y -= pdfLineHeight / 2
+	w.rule()
+
+	if len(t.Terms) == 0 {
+		w.line(pdf.Regular, 10, "No courses on record.")
+	}
+	for _, term := range t.Terms {
+		// Keep the term heading with its column header and first course.
+		w.need(4)
+		w.y -= pdfLineHeight / 2
+		w.line(pdf.Bold, 12, term.Name)
+		w.courseHeader()
+		for _, c := range term.Courses {
+			if w.y-pdfLineHeight < pdfBottom {
+				w.newPage()
+				w.line(pdf.Bold, 10, term.Name+" (continued)")
+				w.courseHeader()
+			}
+			w.course(c)
+		}
+		w.line(pdf.Regular, 9, fmt.Sprintf("Term GPA: %s    Credits earned: %s", formatGPA(term.GPA), formatNumber(term.CreditsEarned)))
+	}
+
+	w.need(5)
+	w.y -= pdfLineHeight / 2
+	w.rule()
+	w.line(pdf.Bold, 11, "Cumulative GPA: "+formatGPA(t.CumulativeGPA))
+	w.line(pdf.Regular, 10, fmt.Sprintf("Credits attempted: %s    Credits earned: %s",
+		formatNumber(t.CreditsAttempted), formatNumber(t.CreditsEarned)))
+	w.line(pdf.Regular, 8, "(R) marks an attempt replaced by a retake; it is excluded from the cumulative GPA.")
+
+	for i, p := range w.pages {
+		p.Line(pdfMargin, 60, pdfRight, 60, 0.5)
+		p.Text(pdfMargin, 46, pdf.Regular, 7, "Verification hash: "+t.VerificationHash)
+		p.Text(pdfMargin, 36, pdf.Regular, 7, "Verify at "+verificationURL(t.VerificationHash))
+		p.TextRight(pdfRight, 46, pdf.Regular, 7, fmt.Sprintf("Page %d of %d", i+1, len(w.pages)))
+	}
+	return w.doc.Bytes()
+}
+
+func formatGPA(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *v)
+}
+
+func formatNumber(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/pdf"
+)
+
+var transcriptHashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// TranscriptService builds academic transcripts with term and cumulative
+// GPAs, and issues them with a verification hash.
+type TranscriptService struct {
+	enrollments repositories.EnrollmentRepository
+	users       repositories.UserRepository
+	scale       repositories.GradeScaleRepository
+	transcripts repositories.TranscriptRepository
+	// fonts draw the PDF; nil selects the standard fonts, which only cover
+	// Western European scripts.
+	fonts *pdf.Fonts
+}
+
+func NewTranscriptService(
+	enrollments repositories.EnrollmentRepository,
+	users repositories.UserRepository,
+	scale repositories.GradeScaleRepository,
+	transcripts repositories.TranscriptRepository,
+	fonts *pdf.Fonts,
+) *TranscriptService {
+	return &TranscriptService{enrollments: enrollments, users: users, scale: scale, transcripts: transcripts, fonts: fonts}
+}
+
+// retakePolicy is read from RETAKE_POLICY and defaults to counting the best
+// attempt.
+func retakePolicy() string {
+	if os.Getenv("RETAKE_POLICY") == models.RetakeLatest {
+		return models.RetakeLatest
+	}
+	return models.RetakeBest
+}
+
+// GetTranscript builds the student's transcript for viewing. It is not
+// issued, so it carries no verification hash.
+func (s *TranscriptService) GetTranscript(ctx context.Context, studentID uint) (*contracts.TranscriptDTO, error) {
+	return s.build(ctx, studentID)
+}
+
+// TranscriptPDF issues the student's transcript as a PDF document. A
+// transcript with text the fonts cannot show is refused before it is
+// recorded, rather than printed with substitute characters.
+func (s *TranscriptService) TranscriptPDF(ctx context.Context, studentID uint) ([]byte, *contracts.TranscriptDTO, error) {
+	transcript, err := s.build(ctx, studentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.render(transcript); err != nil {
+		return nil, nil, err
+	}
+	if err := s.issue(ctx, transcript); err != nil {
+		return nil, nil, err
+	}
+	doc, err := s.render(transcript)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, transcript, nil
+}
+
+func (s *TranscriptService) render(transcript *contracts.TranscriptDTO) ([]byte, error) {
+	doc, err := renderTranscriptPDF(transcript, s.fonts)
+	if errors.Is(err, pdf.ErrUnsupportedText) {
+		return nil, contracts.ErrUnprintableText
+	}
+	return doc, err
+}
+
+// VerifyTranscript confirms that a transcript with the hash was issued and
+// reports whether it still matches the student's record.
+func (s *TranscriptService) VerifyTranscript(ctx context.Context, hash string) (*contracts.TranscriptVerificationDTO, error) {
+	if !transcriptHashPattern.MatchString(hash) {
+		return nil, contracts.ErrTranscriptNotFound
+	}
+	record, err := s.transcripts.FindByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrTranscriptNotFound
+		}
+		return nil, err
+	}
+
+	var issued contracts.TranscriptDTO
+	if err := json.Unmarshal([]byte(record.Snapshot), &issued); err != nil {
+		return nil, err
+	}
+
+	verification := &contracts.TranscriptVerificationDTO{
+		Hash:          record.Hash,
+		StudentName:   issued.Student.Name,
+		IssuedAt:      record.CreatedAt,
+		CumulativeGPA: issued.CumulativeGPA,
+		CreditsEarned: issued.CreditsEarned,
+	}
+	current, err := s.build(ctx, record.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	if currentHash, _, err := transcriptHash(current); err == nil {
+		verification.Current = currentHash == record.Hash
+	}
+	return verification, nil
+}
+
+func (s *TranscriptService) build(ctx context.Context, studentID uint) (*contracts.TranscriptDTO, error) {
+	student, err := s.users.FindByID(ctx, studentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, contracts.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	all, err := s.enrollments.ListByStudent(ctx, studentID, 0)
+	if err != nil {
+		return nil, err
+	}
+	bands, err := s.scale.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments := make([]models.Enrollment, 0, len(all))
+	for _, e := range all {
+		if e.Status == models.EnrollmentEnrolled && e.Section != nil && e.Section.Subject != nil && e.Section.Term != nil {
+			enrollments = append(enrollments, e)
+		}
+	}
+	sort.SliceStable(enrollments, func(i, j int) bool {
+		a, b := enrollments[i].Section, enrollments[j].Section
+		if !a.Term.StartDate.Equal(b.Term.StartDate) {
+			return a.Term.StartDate.Before(b.Term.StartDate)
+		}
+		return a.Subject.Name < b.Subject.Name
+	})
+
+	return transcriptFor(student, enrollments, bands, retakePolicy(), passingScore()), nil
+}
+
+// transcriptFor groups enrollments, in chronological order, into terms and
+// computes the term and cumulative GPAs. Every graded attempt counts towards
+// its term's GPA; only the attempts the retake policy picks count towards the
+// cumulative one.
+func transcriptFor(student *models.User, enrollments []models.Enrollment, bands []models.GradeBand, policy string, pass float64) *contracts.TranscriptDTO {
+	counted := countedAttempts(enrollments, bands, policy)
+	transcript := &contracts.TranscriptDTO{
+		Student:      contracts.TranscriptStudentDTO{ID: student.ID, Name: student.Name, Email: student.Email},
+		RetakePolicy: policy,
+		Terms:        []contracts.TranscriptTermDTO{},
+	}
+	var cumPoints, cumCredits float64
+	var termPoints, termCredits float64
+	for _, e := range enrollments {
+		section := e.Section
+		if n := len(transcript.Terms); n == 0 || transcript.Terms[n-1].TermID != section.TermID {
+			termPoints, termCredits = 0, 0
+			transcript.Terms = append(transcript.Terms, contracts.TranscriptTermDTO{
+				TermID:  section.TermID,
+				Name:    section.Term.Name,
+				Courses: []contracts.TranscriptCourseDTO{},
+			})
+		}
+		term := &transcript.Terms[len(transcript.Terms)-1]
+
+		course := contracts.TranscriptCourseDTO{
+			SubjectID:   section.SubjectID,
+			SubjectName: section.Subject.Name,
+			SectionCode: section.Code,
+			CreditHours: section.Subject.CreditHours,
+			FinalScore:  e.FinalScore,
+			InProgress:  e.FinalScore == nil,
+			Counted:     counted[e.ID],
+		}
+		if e.FinalScore != nil {
+			band, _ := gradeBandFor(bands, *e.FinalScore)
+			points := band.GradePoints
+			course.Letter = band.Letter
+			course.GradePoints = &points
+			credits := section.Subject.CreditHours
+
+			termPoints += points * credits
+			termCredits += credits
+			if *e.FinalScore >= pass {
+				term.CreditsEarned += credits
+			}
+			term.GPA = gpa(termPoints, termCredits)
+
+			if course.Counted {
+				cumPoints += points * credits
+				cumCredits += credits
+				transcript.CreditsAttempted += credits
+				if *e.FinalScore >= pass {
+					transcript.CreditsEarned += credits
+				}
+			}
+		}
+		term.Courses = append(term.Courses, course)
+	}
+	transcript.CumulativeGPA = gpa(cumPoints, cumCredits)
+	return transcript
+}
+
+// issue records the transcript and fills in its verification hash and the
+// time it was first issued.
+func (s *TranscriptService) issue(ctx context.Context, transcript *contracts.TranscriptDTO) error {
+	hash, snapshot, err := transcriptHash(transcript)
+	if err != nil {
+		return err
+	}
+	record := &models.TranscriptRecord{Hash: hash, StudentID: transcript.Student.ID, Snapshot: string(snapshot)}
+	if err := s.transcripts.Record(ctx, record); err != nil {
+		return err
+	}
+	transcript.VerificationHash = record.Hash
+	transcript.IssuedAt = &record.CreatedAt
+	return nil
+}
+
+// transcriptHash hashes the transcript's content, leaving out the issue
+// metadata, so that unchanged records always hash the same.
+func transcriptHash(transcript *contracts.TranscriptDTO) (string, []byte, error) {
+	content := *transcript
+	content.VerificationHash = ""
+	content.IssuedAt = nil
+	snapshot, err := json.Marshal(content)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(snapshot)
+	return hex.EncodeToString(sum[:]), snapshot, nil
+}
+
+// countedAttempts picks, for every subject, the graded attempt that counts
+// towards the cumulative GPA. Enrollments must be in chronological order.
+func countedAttempts(enrollments []models.Enrollment, bands []models.GradeBand, policy string) map[uint]bool {
+	chosen := map[uint]*models.Enrollment{}
+	for i := range enrollments {
+		e := &enrollments[i]
+		if e.FinalScore == nil {
+			continue
+		}
+		prev, ok := chosen[e.Section.SubjectID]
+		if !ok || policy == models.RetakeLatest || !betterAttempt(prev, e, bands) {
+			chosen[e.Section.SubjectID] = e
+		}
+	}
+
+	counted := make(map[uint]bool, len(chosen))
+	for _, e := range chosen {
+		counted[e.ID] = true
+	}
+	return counted
+}
+
+// betterAttempt reports whether a beats the later attempt b; ties go to the
+// later attempt.
+func betterAttempt(a, b *models.Enrollment, bands []models.GradeBand) bool {
+	bandA, _ := gradeBandFor(bands, *a.FinalScore)
+	bandB, _ := gradeBandFor(bands, *b.FinalScore)
+	if bandA.GradePoints != bandB.GradePoints {
+		return bandA.GradePoints > bandB.GradePoints
+	}
+	return *a.FinalScore > *b.FinalScore
+}
+
+func gpa(points, credits float64) *float64 {
+	if credits == 0 {
+		return nil
+	}
+	v := roundScore(points / credits)
+	return &v
+}
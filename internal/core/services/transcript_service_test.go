@@ -0,0 +1,169 @@
+package services
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+var testBands = []models.GradeBand{
+	{Letter: "A", MinScore: 90, GradePoints: 4},
+	{Letter: "B", MinScore: 80, GradePoints: 3},
+	{Letter: "C", MinScore: 70, GradePoints: 2},
+	{Letter: "D", MinScore: 60, GradePoints: 1},
+	{Letter: "F", MinScore: 0, GradePoints: 0},
+}
+
+var (
+	calculus = &models.Subject{Model: gorm.Model{ID: 1}, Name: "Calculus", CreditHours: 4}
+	history  = &models.Subject{Model: gorm.Model{ID: 2}, Name: "History", CreditHours: 3}
+	physics  = &models.Subject{Model: gorm.Model{ID: 3}, Name: "Physics", CreditHours: 4}
+)
+
+// attempt is an enrollment in a section of subject in term termID; a nil
+// score means the section has not been graded yet.
+func attempt(id uint, subject *models.Subject, termID uint, score *float64) models.Enrollment {
+	return models.Enrollment{
+		ID:     id,
+		Status: models.EnrollmentEnrolled,
+		Section: &models.CourseSection{
+			SubjectID: subject.ID,
+			Subject:   subject,
+			TermID:    termID,
+			Term:      &models.Term{ID: termID},
+		},
+		FinalScore: score,
+	}
+}
+
+func TestCountedAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		enrollments []models.Enrollment
+		policy      string
+		want        []uint
+	}{
+		{
+			name:        "single attempts all count",
+			enrollments: []models.Enrollment{attempt(1, calculus, 1, float(55)), attempt(2, history, 1, float(85))},
+			policy:      models.RetakeBest,
+			want:        []uint{1, 2},
+		},
+		{
+			name:        "best keeps the higher grade",
+			enrollments: []models.Enrollment{attempt(1, calculus, 1, float(92)), attempt(2, calculus, 2, float(75))},
+			policy:      models.RetakeBest,
+			want:        []uint{1},
+		},
+		{
+			name:        "latest keeps the retake",
+			enrollments: []models.Enrollment{attempt(1, calculus, 1, float(92)), attempt(2, calculus, 2, float(75))},
+			policy:      models.RetakeLatest,
+			want:        []uint{2},
+		},
+		{
+			name:        "same grade goes to the higher score",
+			enrollments: []models.Enrollment{attempt(1, calculus, 1, float(98)), attempt(2, calculus, 2, float(91))},
+			policy:      models.RetakeBest,
+			want:        []uint{1},
+		},
+		{
+			name:        "ties go to the later attempt",
+			enrollments: []models.Enrollment{attempt(1, calculus, 1, float(91)), attempt(2, calculus, 2, float(91))},
+			policy:      models.RetakeBest,
+			want:        []uint{2},
+		},
+		{
+			name:        "an ungraded retake does not replace a grade",
+			enrollments: []models.Enrollment{attempt(1, calculus, 1, float(55)), attempt(2, calculus, 2, nil)},
+			policy:      models.RetakeLatest,
+			want:        []uint{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counted := countedAttempts(tt.enrollments, testBands, tt.policy)
+			if len(counted) != len(tt.want) {
+				t.Fatalf("counted %v, want %v", counted, tt.want)
+			}
+			for _, id := range tt.want {
+				if !counted[id] {
+					t.Errorf("counted %v, want %v", counted, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTranscriptFor(t *testing.T) {
+	student := &models.User{ID: 7, Name: "Aigerim"}
+	// Calculus is failed in term 1, passed with an A in term 2 and retaken
+	// for a C in term 3; Physics is still in progress.
+	enrollments := []models.Enrollment{
+		attempt(1, calculus, 1, float(55)),
+		attempt(2, history, 1, float(85)),
+		attempt(3, calculus, 2, float(92)),
+		attempt(4, physics, 2, nil),
+		attempt(5, calculus, 3, float(75)),
+	}
+	tests := []struct {
+		policy     string
+		cumulative *float64
+	}{
+		// A in Calculus and B in History: (4*4 + 3*3) / 7.
+		{policy: models.RetakeBest, cumulative: float(3.57)},
+		// C in Calculus and B in History: (2*4 + 3*3) / 7.
+		{policy: models.RetakeLatest, cumulative: float(2.43)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			transcript := transcriptFor(student, enrollments, testBands, tt.policy, 60)
+
+			if !sameScore(transcript.CumulativeGPA, tt.cumulative) {
+				t.Errorf("cumulative GPA = %v, want %v", formatPoints(transcript.CumulativeGPA), formatPoints(tt.cumulative))
+			}
+			if transcript.CreditsAttempted != 7 || transcript.CreditsEarned != 7 {
+				t.Errorf("credits attempted %v, earned %v, want 7 and 7", transcript.CreditsAttempted, transcript.CreditsEarned)
+			}
+
+			// Term GPAs count every graded attempt, whichever the policy.
+			wantTerms := []struct {
+				gpa     *float64
+				earned  float64
+				courses int
+			}{
+				{gpa: float(1.29), earned: 3, courses: 2}, // (0*4 + 3*3) / 7
+				{gpa: float(4), earned: 4, courses: 2},
+				{gpa: float(2), earned: 4, courses: 1},
+			}
+			if len(transcript.Terms) != len(wantTerms) {
+				t.Fatalf("%d terms, want %d", len(transcript.Terms), len(wantTerms))
+			}
+			for i, want := range wantTerms {
+				term := transcript.Terms[i]
+				if !sameScore(term.GPA, want.gpa) || term.CreditsEarned != want.earned || len(term.Courses) != want.courses {
+					t.Errorf("term %d: GPA %v, earned %v, %d courses; want %v, %v, %d", i+1,
+						formatPoints(term.GPA), term.CreditsEarned, len(term.Courses), formatPoints(want.gpa), want.earned, want.courses)
+				}
+			}
+
+			inProgress := transcript.Terms[1].Courses[1]
+			if !inProgress.InProgress || inProgress.GradePoints != nil || inProgress.Counted {
+				t.Errorf("ungraded course = %+v", inProgress)
+			}
+		})
+	}
+}
+
+func TestTranscriptForWithoutGrades(t *testing.T) {
+	transcript := transcriptFor(&models.User{ID: 7}, []models.Enrollment{attempt(1, physics, 1, nil)}, testBands, models.RetakeBest, 60)
+	if transcript.CumulativeGPA != nil || transcript.Terms[0].GPA != nil {
+		t.Errorf("GPA of ungraded courses: cumulative %v, term %v",
+			formatPoints(transcript.CumulativeGPA), formatPoints(transcript.Terms[0].GPA))
+	}
+	if transcript.CreditsAttempted != 0 {
+		t.Errorf("credits attempted %v, want 0", transcript.CreditsAttempted)
+	}
+}
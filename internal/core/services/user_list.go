@@ -0,0 +1,163 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/repositories"
+	"github.com/arman300s/uni-portal/internal/models"
+)
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+	maxUserSearchLength = 100
+)
+
+// userCursor is the opaque next_cursor handed to clients. It records the
+// sort it was issued for so that it cannot be replayed against another one.
+type userCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    uint   `json:"id"`
+}
+
+// parseUserListQuery validates the raw query parameters of GET /admin/users
+// and turns them into repository options.
+func parseUserListQuery(q contracts.UserListQuery) (repositories.UserListOptions, contracts.ValidationErrors) {
+	var errs contracts.ValidationErrors
+	opts := repositories.UserListOptions{
+		RoleName:  strings.TrimSpace(strings.ToLower(q.Role)),
+		Search:    strings.TrimSpace(q.Search),
+		SortField: "id",
+		Limit:     defaultUserPageSize,
+	}
+
+	if len(opts.Search) > maxUserSearchLength {
+		errs = append(errs, contracts.ValidationError{Field: "q", Message: "search term is too long"})
+	}
+
+	if q.Sort != "" {
+		if _, ok := repositories.UserSortColumns[q.Sort]; !ok {
+			errs = append(errs, contracts.ValidationError{Field: "sort", Message: "sort must be one of id, name, email, created_at"})
+		} else {
+			opts.SortField = q.Sort
+		}
+	}
+
+	switch strings.ToLower(q.Order) {
+	case "", "asc":
+	case "desc":
+		opts.Desc = true
+	default:
+		errs = append(errs, contracts.ValidationError{Field: "order", Message: "order must be asc or desc"})
+	}
+
+	if q.Limit != "" {
+		limit, err := strconv.Atoi(q.Limit)
+		if err != nil || limit < 1 || limit > maxUserPageSize {
+			errs = append(errs, contracts.ValidationError{Field: "limit", Message: fmt.Sprintf("limit must be between 1 and %d", maxUserPageSize)})
+		} else {
+			opts.Limit = limit
+		}
+	}
+
+	if q.CreatedFrom != "" {
+		from, _, err := parseDateParam(q.CreatedFrom)
+		if err != nil {
+			errs = append(errs, contracts.ValidationError{Field: "created_from", Message: err.Error()})
+		} else {
+			opts.CreatedFrom = &from
+		}
+	}
+	if q.CreatedTo != "" {
+		to, dateOnly, err := parseDateParam(q.CreatedTo)
+		if err != nil {
+			errs = append(errs, contracts.ValidationError{Field: "created_to", Message: err.Error()})
+		} else {
+			// A bare date includes the whole day.
+			if dateOnly {
+				to = to.AddDate(0, 0, 1)
+			} else {
+				to = to.Add(time.Nanosecond)
+			}
+			opts.CreatedBefore = &to
+		}
+	}
+	if opts.CreatedFrom != nil && opts.CreatedBefore != nil && !opts.CreatedFrom.Before(*opts.CreatedBefore) {
+		errs = append(errs, contracts.ValidationError{Field: "created_to", Message: "created_to must not be before created_from"})
+	}
+
+	if q.Cursor != "" && len(errs) == 0 {
+		if err := applyUserCursor(&opts, q.Cursor); err != nil {
+			errs = append(errs, contracts.ValidationError{Field: "cursor", Message: err.Error()})
+		}
+	}
+	return opts, errs
+}
+
+// parseDateParam accepts RFC 3339 timestamps and plain YYYY-MM-DD dates.
+func parseDateParam(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
+
+func applyUserCursor(opts *repositories.UserListOptions, raw string) error {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+	var c userCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return fmt.Errorf("invalid cursor")
+	}
+	if c.Sort != opts.SortField || c.Desc != opts.Desc {
+		return fmt.Errorf("cursor was issued for a different sort order")
+	}
+
+	switch c.Sort {
+	case "created_at":
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return fmt.Errorf("invalid cursor")
+		}
+		opts.After = t
+	case "name", "email":
+		opts.After = c.Value
+	}
+	opts.AfterID = c.ID
+	return nil
+}
+
+func encodeUserCursor(opts repositories.UserListOptions, last *models.User) string {
+	c := userCursor{Sort: opts.SortField, Desc: opts.Desc, ID: last.ID}
+	switch opts.SortField {
+	case "created_at":
+		c.Value = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "name":
+		c.Value = last.Name
+	case "email":
+		c.Value = last.Email
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// userListCacheKey derives a cache key from every option that affects the
+// page contents.
+func userListCacheKey(opts repositories.UserListOptions) string {
+	data, _ := json.Marshal(opts)
+	return fmt.Sprintf("users:list:%x", sha256.Sum256(data))
+}
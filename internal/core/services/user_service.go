@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"strings"
 	"time"
@@ -18,12 +17,14 @@ import (
 
 // UserService encapsulates admin/user flows.
 type UserService struct {
-	users repositories.UserRepository
-	roles repositories.RoleRepository
+	users         repositories.UserRepository
+	roles         repositories.RoleRepository
+	refreshTokens repositories.RefreshTokenRepository
+	cache         cache.Cache
 }
 
-func NewUserService(users repositories.UserRepository, roles repositories.RoleRepository) *UserService {
-	return &UserService{users: users, roles: roles}
+func NewUserService(users repositories.UserRepository, roles repositories.RoleRepository, refreshTokens repositories.RefreshTokenRepository, c cache.Cache) *UserService {
+	return &UserService{users: users, roles: roles, refreshTokens: refreshTokens, cache: c}
 }
 
 func (s *UserService) GetCurrentUser(ctx context.Context, id uint) (*contracts.UserDTO, error) {
@@ -37,30 +38,43 @@ func (s *UserService) GetCurrentUser(ctx context.Context, id uint) (*contracts.U
 	return mapToUserDTO(user), nil
 }
 
-func (s *UserService) ListUsers(ctx context.Context) ([]contracts.UserDTO, error) {
-	cacheKey := "users:all"
-	cached, err := cache.RDB.Get(ctx, cacheKey).Bytes()
-	if err == nil {
-		var dtos []contracts.UserDTO
-		if json.Unmarshal(cached, &dtos) == nil {
-			return dtos, nil
-		}
+// ListUsers returns one page of users. Pages are cached per normalized query
+// so that different filters never share an entry.
+func (s *UserService) ListUsers(ctx context.Context, query contracts.UserListQuery) (*contracts.UserListDTO, error) {
+	opts, errs := parseUserListQuery(query)
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
-	users, err := s.users.List(ctx)
+	var page contracts.UserListDTO
+	err := cache.Remember(ctx, s.cache, userListCacheKey(opts), []string{cache.TagUsers}, 5*time.Minute, &page,
+		func(ctx context.Context) (any, error) {
+			return s.loadUserPage(ctx, opts)
+		})
 	if err != nil {
 		return nil, err
 	}
+	return &page, nil
+}
 
-	dtos := make([]contracts.UserDTO, 0, len(users))
-	for _, u := range users {
-		dtos = append(dtos, *mapToUserDTO(&u))
+func (s *UserService) loadUserPage(ctx context.Context, opts repositories.UserListOptions) (*contracts.UserListDTO, error) {
+	// Fetch one extra row to learn whether another page follows.
+	limit := opts.Limit
+	opts.Limit++
+	users, total, err := s.users.ListPage(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	data, _ := json.Marshal(dtos)
-	cache.RDB.Set(ctx, cacheKey, data, 5*time.Minute)
-
-	return dtos, nil
+	page := &contracts.UserListDTO{Items: make([]contracts.UserDTO, 0, limit), Total: total}
+	if len(users) > limit {
+		users = users[:limit]
+		page.NextCursor = encodeUserCursor(opts, &users[limit-1])
+	}
+	for _, u := range users {
+		page.Items = append(page.Items, *mapToUserDTO(&u))
+	}
+	return page, nil
 }
 
 func (s *UserService) GetUser(ctx context.Context, id uint) (*contracts.UserDTO, error) {
@@ -102,16 +116,20 @@ func (s *UserService) CreateUser(ctx context.Context, input contracts.CreateUser
 		return nil, err
 	}
 
+	// Accounts provisioned by an admin are trusted and skip email verification.
+	now := time.Now()
 	user := models.User{
-		Name:     input.Name,
-		Email:    input.Email,
-		Password: hashedPassword,
-		RoleID:   &role.ID,
+		Name:            input.Name,
+		Email:           input.Email,
+		Password:        hashedPassword,
+		RoleID:          &role.ID,
+		EmailVerifiedAt: &now,
 	}
 
 	if err := s.users.Create(ctx, &user); err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, s.cache, cache.TagUsers)
 
 	return mapToUserDTO(&user), nil
 }
@@ -140,25 +158,55 @@ func (s *UserService) UpdateUserRole(ctx context.Context, id uint, input contrac
 		return err
 	}
 
+	roleChanged := user.RoleID == nil || *user.RoleID != role.ID
 	user.RoleID = &role.ID
-	return s.users.Save(ctx, user)
+	if err := s.users.Save(ctx, user); err != nil {
+		return err
+	}
+	invalidateCache(ctx, s.cache, cache.TagUsers)
+
+	if roleChanged {
+		return revokeUserSessions(ctx, s.refreshTokens, user.ID)
+	}
+	return nil
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
+	// Tokens are revoked first, while the user's token version can still
+	// be bumped.
+	if err := auth.RevokeUserTokens(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrUserNotFound
+		}
+		return err
+	}
 	if err := s.users.Delete(ctx, id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return contracts.ErrUserNotFound
 		}
 		return err
 	}
+	invalidateCache(ctx, s.cache, cache.TagUsers)
 	return nil
 }
 
+// RevokeSessions signs the user out everywhere.
+func (s *UserService) RevokeSessions(ctx context.Context, id uint) error {
+	if _, err := s.users.FindByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contracts.ErrUserNotFound
+		}
+		return err
+	}
+	return revokeUserSessions(ctx, s.refreshTokens, id)
+}
+
 func mapToUserDTO(user *models.User) *contracts.UserDTO {
 	dto := &contracts.UserDTO{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
 	}
 	if user.Role != nil {
 		dto.Role = user.Role.Name
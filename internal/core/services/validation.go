@@ -1,11 +1,14 @@
 package services
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/models"
 )
 
 const (
@@ -13,8 +16,18 @@ const (
 	maxPasswordLength = 128
 	maxNameLength     = 100
 	maxEmailLength    = 255
+	maxRoleNameLength = 50
+	maxTermNameLength = 100
+
+	maxSectionCodeLength = 20
+	maxSectionCapacity   = 1000
+
+	defaultCreditHours = 3
+	maxCreditHours     = 30
 )
 
+var roleNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_\-]*$`)
+
 func validateSignupInput(input contracts.SignupInput) contracts.ValidationErrors {
 	var errs contracts.ValidationErrors
 	if err := validateName(input.Name); err != nil {
@@ -51,6 +64,78 @@ func validateSubjectInput(input contracts.SubjectInput) contracts.ValidationErro
 	if strings.TrimSpace(input.Name) == "" {
 		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is required"})
 	}
+	if input.CreditHours < 0 || input.CreditHours > maxCreditHours {
+		errs = append(errs, contracts.ValidationError{Field: "credit_hours", Message: fmt.Sprintf("credit_hours must be between 0 and %d", maxCreditHours)})
+	}
+	return errs
+}
+
+func validateTermInput(input contracts.TermInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	switch {
+	case input.Name == "":
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is required"})
+	case len(input.Name) > maxTermNameLength:
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is too long"})
+	}
+
+	ranges := []struct {
+		field      string
+		start, end time.Time
+	}{
+		{"end_date", input.StartDate, input.EndDate},
+		{"registration_end", input.RegistrationStart, input.RegistrationEnd},
+		{"add_drop_end", input.AddDropStart, input.AddDropEnd},
+	}
+	for _, r := range ranges {
+		if r.start.IsZero() || r.end.IsZero() {
+			errs = append(errs, contracts.ValidationError{Field: r.field, Message: "start and end are required"})
+		} else if !r.end.After(r.start) {
+			errs = append(errs, contracts.ValidationError{Field: r.field, Message: "end must be after start"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if input.RegistrationEnd.After(input.EndDate) {
+		errs = append(errs, contracts.ValidationError{Field: "registration_end", Message: "registration must close before the term ends"})
+	}
+	if input.AddDropStart.Before(input.RegistrationStart) || input.AddDropEnd.After(input.EndDate) {
+		errs = append(errs, contracts.ValidationError{Field: "add_drop_start", Message: "add/drop must open after registration and close before the term ends"})
+	}
+	return errs
+}
+
+func validateSectionFields(code string, capacity int, deliveryMode string) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	switch {
+	case code == "":
+		errs = append(errs, contracts.ValidationError{Field: "code", Message: "code is required"})
+	case len(code) > maxSectionCodeLength:
+		errs = append(errs, contracts.ValidationError{Field: "code", Message: "code is too long"})
+	}
+	if capacity < 1 || capacity > maxSectionCapacity {
+		errs = append(errs, contracts.ValidationError{Field: "capacity", Message: fmt.Sprintf("capacity must be between 1 and %d", maxSectionCapacity)})
+	}
+	switch deliveryMode {
+	case models.DeliveryInPerson, models.DeliveryOnline, models.DeliveryHybrid:
+	default:
+		errs = append(errs, contracts.ValidationError{Field: "delivery_mode", Message: "delivery_mode must be in_person, online or hybrid"})
+	}
+	return errs
+}
+
+func validateRoleInput(input contracts.RoleInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	switch {
+	case input.Name == "":
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is required"})
+	case len(input.Name) > maxRoleNameLength:
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name is too long"})
+	case !roleNameRegex.MatchString(input.Name):
+		errs = append(errs, contracts.ValidationError{Field: "name", Message: "name may only contain lowercase letters, digits, '-' and '_'"})
+	}
 	return errs
 }
 
@@ -65,6 +150,33 @@ func validateLoginInput(input contracts.LoginInput) contracts.ValidationErrors {
 	return errs
 }
 
+func validateRefreshInput(input contracts.RefreshInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	if strings.TrimSpace(input.RefreshToken) == "" {
+		errs = append(errs, contracts.ValidationError{Field: "refresh_token", Message: "refresh_token is required"})
+	}
+	return errs
+}
+
+func validateForgotPasswordInput(input contracts.ForgotPasswordInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	if err := validateEmail(input.Email); err != nil {
+		errs = append(errs, extractValidationErrors(err, "email")...)
+	}
+	return errs
+}
+
+func validateResetPasswordInput(input contracts.ResetPasswordInput) contracts.ValidationErrors {
+	var errs contracts.ValidationErrors
+	if strings.TrimSpace(input.Token) == "" {
+		errs = append(errs, contracts.ValidationError{Field: "token", Message: "token is required"})
+	}
+	if err := validatePassword(input.Password); err != nil {
+		errs = append(errs, extractValidationErrors(err, "password")...)
+	}
+	return errs
+}
+
 func validateUpdateUserInput(input contracts.UpdateUserInput) contracts.ValidationErrors {
 	var errs contracts.ValidationErrors
 	if trimmed := strings.TrimSpace(input.Email); trimmed != "" {
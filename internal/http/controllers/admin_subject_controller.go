@@ -55,11 +55,14 @@ func (c *AdminSubjectController) CreateSubject(w http.ResponseWriter, r *http.Re
 // @Tags admin-subjects
 // @Produce json
 // @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
 // @Success 200 {array} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/subjects [get]
 func (c *AdminSubjectController) ListSubjects(w http.ResponseWriter, r *http.Request) {
-	subjects, err := c.service.ListSubjects(r.Context())
+	subjects, err := c.service.ListSubjects(r.Context(), r.URL.Query().Get("term"))
 	if err != nil {
 		handleSubjectError(w, err)
 		return
@@ -141,6 +144,7 @@ func (c *AdminSubjectController) UpdateSubject(w http.ResponseWriter, r *http.Re
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /admin/subjects/{id} [delete]
 func (c *AdminSubjectController) DeleteSubject(w http.ResponseWriter, r *http.Request) {
 	id, err := parseSubjectID(r)
@@ -157,6 +161,102 @@ func (c *AdminSubjectController) DeleteSubject(w http.ResponseWriter, r *http.Re
 	writeJSON(w, http.StatusOK, map[string]string{"message": "subject deleted successfully"})
 }
 
+// GetRequisites godoc
+// @Summary Get subject requisites
+// @Description Each group is satisfied by any one of its options; every group must be satisfied.
+// @Tags admin-subjects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subject ID"
+// @Success 200 {object} contracts.RequisitesDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/subjects/{id}/requisites [get]
+func (c *AdminSubjectController) GetRequisites(w http.ResponseWriter, r *http.Request) {
+	id, err := parseSubjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	requisites, err := c.service.GetRequisites(r.Context(), id)
+	if err != nil {
+		handleSubjectError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, requisites)
+}
+
+// ReplaceRequisites godoc
+// @Summary Replace subject requisites
+// @Description Replaces all prerequisite and corequisite groups. Changes that would create a cycle through a prerequisite are rejected.
+// @Tags admin-subjects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subject ID"
+// @Param requisites body contracts.RequisitesInput true "Requisite groups"
+// @Success 200 {object} contracts.RequisitesDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/subjects/{id}/requisites [put]
+func (c *AdminSubjectController) ReplaceRequisites(w http.ResponseWriter, r *http.Request) {
+	id, err := parseSubjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.RequisitesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	requisites, err := c.service.ReplaceRequisites(r.Context(), id, input)
+	if err != nil {
+		handleSubjectError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, requisites)
+}
+
+// CheckEligibility godoc
+// @Summary Check a student's eligibility for a subject
+// @Tags admin-subjects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subject ID"
+// @Param student_id query int true "Student ID"
+// @Param term query string false "Term ID or \"current\" used for corequisites"
+// @Success 200 {object} contracts.EligibilityDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/subjects/{id}/eligibility [get]
+func (c *AdminSubjectController) CheckEligibility(w http.ResponseWriter, r *http.Request) {
+	id, err := parseSubjectID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	studentID, err := strconv.ParseUint(r.URL.Query().Get("student_id"), 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid student_id", nil)
+		return
+	}
+
+	eligibility, err := c.service.CheckEligibility(r.Context(), uint(studentID), id, r.URL.Query().Get("term"))
+	if err != nil {
+		handleSubjectError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, eligibility)
+}
+
 func parseSubjectID(r *http.Request) (uint, error) {
 	idStr := mux.Vars(r)["id"]
 	val, err := strconv.ParseUint(idStr, 10, 32)
@@ -175,11 +275,20 @@ func subjectResponse(subject *models.Subject) map[string]interface{} {
 			"email": t.Email,
 		})
 	}
+	terms := make([]map[string]interface{}, 0, len(subject.Terms))
+	for _, t := range subject.Terms {
+		terms = append(terms, map[string]interface{}{
+			"id":   t.ID,
+			"name": t.Name,
+		})
+	}
 	return map[string]interface{}{
-		"id":          subject.ID,
-		"name":        subject.Name,
-		"description": subject.Description,
-		"teachers":    teachers,
+		"id":           subject.ID,
+		"name":         subject.Name,
+		"description":  subject.Description,
+		"credit_hours": subject.CreditHours,
+		"teachers":     teachers,
+		"terms":        terms,
 	}
 }
 
@@ -191,8 +300,10 @@ func handleSubjectError(w http.ResponseWriter, err error) {
 	}
 
 	switch err {
-	case contracts.ErrSubjectNotFound:
+	case contracts.ErrSubjectNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
 		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrSubjectInUse:
+		writeError(w, http.StatusConflict, err.Error(), nil)
 	default:
 		writeError(w, http.StatusInternalServerError, "internal server error", nil)
 	}
@@ -0,0 +1,537 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// multipartMemory is how much of a multipart form is buffered in memory;
+// larger files spill to temporary files.
+const multipartMemory = 8 << 20
+
+// AssignmentController serves assignments to teachers and students and
+// accepts file submissions.
+type AssignmentController struct {
+	service *services.AssignmentService
+}
+
+func NewAssignmentController(service *services.AssignmentService) *AssignmentController {
+	return &AssignmentController{service: service}
+}
+
+// ListTeacherAssignments godoc
+// @Summary List assignments of my sections and subjects
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Success 200 {array} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments [get]
+func (c *AssignmentController) ListTeacherAssignments(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assignments, err := c.service.ListTeacherAssignments(r.Context(), teacherID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignments)
+}
+
+// GetTeacherAssignment godoc
+// @Summary Get an assignment of my section or subject
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id} [get]
+func (c *AssignmentController) GetTeacherAssignment(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	assignment, err := c.service.GetTeacherAssignment(r.Context(), teacherID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignment)
+}
+
+// CreateAssignment godoc
+// @Summary Hand out an assignment
+// @Description Set section_id for one section, or subject_id (and optionally term) for every section of the subject in the term. A gradebook component can be fed by only one assignment.
+// @Tags teacher-assignments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param assignment body contracts.AssignmentInput true "Assignment payload"
+// @Success 201 {object} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/assignments [post]
+func (c *AssignmentController) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.AssignmentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	assignment, err := c.service.CreateAssignment(r.Context(), teacherID, input)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, assignment)
+}
+
+// UpdateAssignment godoc
+// @Summary Update an assignment
+// @Description The rubric cannot change once a submission has been graded with it. A gradebook component can be fed by only one assignment.
+// @Tags teacher-assignments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Param assignment body contracts.UpdateAssignmentInput true "Assignment payload"
+// @Success 200 {object} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/assignments/{id} [put]
+func (c *AssignmentController) UpdateAssignment(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.UpdateAssignmentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	assignment, err := c.service.UpdateAssignment(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignment)
+}
+
+// DeleteAssignment godoc
+// @Summary Delete an assignment without submissions
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/assignments/{id} [delete]
+func (c *AssignmentController) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteAssignment(r.Context(), teacherID, id); err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "assignment deleted successfully"})
+}
+
+// ListSubmissions godoc
+// @Summary List the submissions to an assignment
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {array} contracts.SubmissionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id}/submissions [get]
+func (c *AssignmentController) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	submissions, err := c.service.ListSubmissions(r.Context(), teacherID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, submissions)
+}
+
+// GradeSubmission godoc
+// @Summary Grade a submission
+// @Description Records the raw score of an assignment without a rubric. The late penalty is worked out from the assignment's late policy and the student's extension at this moment, and shown next to the raw score.
+// @Tags teacher-assignments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Submission ID"
+// @Param grade body contracts.GradeSubmissionInput true "Raw score and feedback"
+// @Success 200 {object} contracts.SubmissionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/submissions/{id}/grade [put]
+func (c *AssignmentController) GradeSubmission(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.GradeSubmissionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	submission, err := c.service.GradeSubmission(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, submission)
+}
+
+// GradeWithRubric godoc
+// @Summary Grade a submission with the assignment's rubric
+// @Description Scores every criterion by level, optionally adjusting the points, with a comment. The total is scaled to the assignment's max points and recorded as the raw score, so the late penalty applies as usual.
+// @Tags teacher-assignments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Submission ID"
+// @Param grade body contracts.RubricGradeInput true "Criterion scores and feedback"
+// @Success 200 {object} contracts.SubmissionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/submissions/{id}/rubric [put]
+func (c *AssignmentController) GradeWithRubric(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.RubricGradeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	submission, err := c.service.GradeWithRubric(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, submission)
+}
+
+// ReleaseGrades godoc
+// @Summary Release the grades of an assignment
+// @Description Shows grades and filled-in rubrics to students, and copies the scores into the assignment's gradebook component. Submissions graded later are shown and copied straight away. Releasing again replaces the component's scores with the assignment's, clearing those of students without a graded submission.
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id}/release [post]
+func (c *AssignmentController) ReleaseGrades(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	assignment, err := c.service.ReleaseGrades(r.Context(), teacherID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignment)
+}
+
+// GrantExtension godoc
+// @Summary Grant a student an extension
+// @Description Moves the student's due date and late window. Extensions are kept as an audit trail; the latest one applies.
+// @Tags teacher-assignments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Param extension body contracts.ExtensionInput true "Extension payload"
+// @Success 201 {object} contracts.ExtensionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id}/extensions [post]
+func (c *AssignmentController) GrantExtension(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.ExtensionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	extension, err := c.service.GrantExtension(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, extension)
+}
+
+// ListExtensions godoc
+// @Summary List the extensions granted for an assignment
+// @Description Every extension ever granted, newest first, with the teacher who granted it.
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {array} contracts.ExtensionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id}/extensions [get]
+func (c *AssignmentController) ListExtensions(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	extensions, err := c.service.ListExtensions(r.Context(), teacherID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, extensions)
+}
+
+// DownloadTeacherFile godoc
+// @Summary Download a submitted file
+// @Tags teacher-assignments
+// @Produce octet-stream
+// @Security ApiKeyAuth
+// @Param id path int true "Submission file ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/submission-files/{id} [get]
+func (c *AssignmentController) DownloadTeacherFile(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	file, err := c.service.TeacherFile(r.Context(), teacherID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeFile(w, file)
+}
+
+// ListStudentAssignments godoc
+// @Summary List my assignments
+// @Description Assignments of the sections the student is enrolled in, each with the student's own submission. Empty between terms.
+// @Tags student-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\" (default)"
+// @Success 200 {array} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/assignments [get]
+func (c *AssignmentController) ListStudentAssignments(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assignments, err := c.service.ListStudentAssignments(r.Context(), studentID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignments)
+}
+
+// GetStudentAssignment godoc
+// @Summary Get one of my assignments
+// @Tags student-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} contracts.AssignmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/assignments/{id} [get]
+func (c *AssignmentController) GetStudentAssignment(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	assignment, err := c.service.GetStudentAssignment(r.Context(), studentID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, assignment)
+}
+
+// Submit godoc
+// @Summary Submit files for an assignment
+// @Description Upload one or more files in the "files" form field. Submitting again while the assignment is open replaces the earlier files and clears any grade. Work handed in after the grace period loses the late penalty when graded.
+// @Tags student-assignments
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Param files formData file true "Submitted files"
+// @Success 201 {object} contracts.SubmissionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /student/assignments/{id}/submissions [post]
+func (c *AssignmentController) Submit(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	maxSize, maxFiles := services.SubmissionLimits()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize*int64(maxFiles)+multipartMemory)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, contracts.ErrFileTooLarge.Error(), nil)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid multipart form", nil)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	headers := r.MultipartForm.File["files"]
+	uploads := make([]contracts.UploadedFile, 0, len(headers))
+	for _, fh := range headers {
+		uploads = append(uploads, contracts.UploadedFile{
+			Name: fh.Filename,
+			Size: fh.Size,
+			Open: func() (io.ReadCloser, error) { return fh.Open() },
+		})
+	}
+
+	submission, err := c.service.Submit(r.Context(), studentID, id, uploads)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, submission)
+}
+
+// DownloadStudentFile godoc
+// @Summary Download a file of my submission
+// @Tags student-assignments
+// @Produce octet-stream
+// @Security ApiKeyAuth
+// @Param id path int true "Submission file ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/submission-files/{id} [get]
+func (c *AssignmentController) DownloadStudentFile(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	file, err := c.service.StudentFile(r.Context(), studentID, id)
+	if err != nil {
+		handleAssignmentError(w, err)
+		return
+	}
+	writeFile(w, file)
+}
+
+// writeFile streams a stored file as an attachment.
+func writeFile(w http.ResponseWriter, file *contracts.FileContent) {
+	defer file.Body.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, file.Body)
+}
+
+func handleAssignmentError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrAssignmentNotFound, contracts.ErrSubmissionNotFound, contracts.ErrSectionNotFound,
+		contracts.ErrSubjectNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm, contracts.ErrFileNotFound,
+		contracts.ErrRubricNotFound:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrNotSectionTeacher, contracts.ErrNotSubjectTeacher, contracts.ErrDeadlinePassed:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	case contracts.ErrAssignmentInUse, contracts.ErrRubricGraded, contracts.ErrComponentLinked:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	case contracts.ErrFileTooLarge:
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
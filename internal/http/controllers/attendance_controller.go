@@ -0,0 +1,313 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// AttendanceController runs class sessions for teachers and QR check-ins
+// for students.
+type AttendanceController struct {
+	service *services.AttendanceService
+}
+
+func NewAttendanceController(service *services.AttendanceService) *AttendanceController {
+	return &AttendanceController{service: service}
+}
+
+// OpenSession godoc
+// @Summary Open a class session
+// @Description Starts taking attendance for the section. A section has at most one open session.
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Param session body contracts.OpenSessionInput false "Session options"
+// @Success 201 {object} contracts.ClassSessionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/sections/{id}/sessions [post]
+func (c *AttendanceController) OpenSession(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.OpenSessionInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body", nil)
+			return
+		}
+	}
+
+	session, err := c.service.OpenSession(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, session)
+}
+
+// ListSessions godoc
+// @Summary List class sessions of a section
+// @Tags teacher-attendance
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {array} contracts.ClassSessionDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sections/{id}/sessions [get]
+func (c *AttendanceController) ListSessions(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := c.service.ListSessions(r.Context(), teacherID, id)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// SectionReport godoc
+// @Summary Attendance report of a section
+// @Description Per-student counts and attendance percentage over closed sessions. Excused sessions are left out of the percentage.
+// @Tags teacher-attendance
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {object} contracts.SectionAttendanceReportDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sections/{id}/attendance [get]
+func (c *AttendanceController) SectionReport(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.SectionReport(r.Context(), teacherID, id)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// CloseSession godoc
+// @Summary Close a class session
+// @Description Stops check-ins. Students without a record are counted absent.
+// @Tags teacher-attendance
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} contracts.ClassSessionDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/sessions/{id}/close [post]
+func (c *AttendanceController) CloseSession(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := c.service.CloseSession(r.Context(), teacherID, id)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// CheckInCode godoc
+// @Summary Get the current check-in QR payload
+// @Description The payload is signed with the server key and rotates every rotation_seconds; render it as a QR code and fetch it again after refresh_in seconds.
+// @Tags teacher-attendance
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} contracts.CheckInCodeDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/sessions/{id}/qr [get]
+func (c *AttendanceController) CheckInCode(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := c.service.CheckInCode(r.Context(), teacherID, id)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, code)
+}
+
+// SessionAttendance godoc
+// @Summary List attendance at a session
+// @Tags teacher-attendance
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Session ID"
+// @Success 200 {array} contracts.SessionAttendanceDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sessions/{id}/attendance [get]
+func (c *AttendanceController) SessionAttendance(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	attendance, err := c.service.SessionAttendance(r.Context(), teacherID, id)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attendance)
+}
+
+// OverrideAttendance godoc
+// @Summary Override attendance at a session
+// @Description Sets students to present, late, excused or absent.
+// @Tags teacher-attendance
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Session ID"
+// @Param records body contracts.AttendanceOverridesInput true "Statuses"
+// @Success 200 {array} contracts.SessionAttendanceDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sessions/{id}/attendance [put]
+func (c *AttendanceController) OverrideAttendance(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.AttendanceOverridesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	attendance, err := c.service.OverrideAttendance(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attendance)
+}
+
+// CheckIn godoc
+// @Summary Check in to a class session
+// @Description Submits the scanned QR payload. Check-ins after the session's late threshold are recorded as late.
+// @Tags student-attendance
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param check_in body contracts.CheckInInput true "Scanned code"
+// @Success 200 {object} contracts.CheckInDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /student/attendance/check-in [post]
+func (c *AttendanceController) CheckIn(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.CheckInInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	checkIn, err := c.service.CheckIn(r.Context(), studentID, input)
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, checkIn)
+}
+
+// MyAttendance godoc
+// @Summary My attendance
+// @Description Attendance counts and percentage per enrolled section.
+// @Tags student-attendance
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Success 200 {array} contracts.MyAttendanceDTO
+// @Failure 404 {object} ErrorResponse
+// @Router /student/attendance [get]
+func (c *AttendanceController) MyAttendance(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	attendance, err := c.service.MyAttendance(r.Context(), studentID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleAttendanceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attendance)
+}
+
+// userAndID reads the caller and the {id} path parameter, writing the error
+// response when either is missing.
+func userAndID(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return 0, 0, false
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+func handleAttendanceError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrSectionNotFound, contracts.ErrSessionNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrNotSectionTeacher, contracts.ErrNotEnrolled:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	case contracts.ErrSessionOpen, contracts.ErrSessionClosed:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	case contracts.ErrInvalidCheckIn:
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
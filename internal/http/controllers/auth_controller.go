@@ -2,10 +2,17 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/arman300s/uni-portal/internal/core/contracts"
 	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/auth"
+	"github.com/arman300s/uni-portal/pkg/middleware"
 )
 
 // AuthController handles signup/login requests.
@@ -54,6 +61,7 @@ func (c *AuthController) Signup(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} contracts.AuthResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /login [post]
 func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 	var input contracts.LoginInput
@@ -61,6 +69,7 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid request body", nil)
 		return
 	}
+	input.IP = clientIP(r)
 
 	resp, err := c.service.Login(r.Context(), input)
 	if err != nil {
@@ -71,16 +80,266 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and a rotated refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body contracts.RefreshInput true "Refresh token"
+// @Success 200 {object} contracts.AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /auth/refresh [post]
+func (c *AuthController) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input contracts.RefreshInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	resp, err := c.service.Refresh(r.Context(), input)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Logout godoc
+// @Summary User logout
+// @Description Revoke the current access token and, optionally, its refresh token family
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param logout body contracts.LogoutInput false "Refresh token to revoke"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /logout [post]
+func (c *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.LogoutInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := c.service.Logout(r.Context(), claims, input); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "logged out successfully"})
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Email a password reset link. The response is the same whether or not the email is registered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body contracts.ForgotPasswordInput true "Account email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Router /password/forgot [post]
+func (c *AuthController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input contracts.ForgotPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := c.service.ForgotPassword(r.Context(), input); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"message": "if the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a reset token. All existing sessions are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body contracts.ResetPasswordInput true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Router /password/reset [post]
+func (c *AuthController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input contracts.ResetPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := c.service.ResetPassword(r.Context(), input); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "password reset successfully"})
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Tags auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Router /verify-email [get]
+func (c *AuthController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if err := c.service.VerifyEmail(r.Context(), r.URL.Query().Get("token")); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "email verified successfully"})
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /verify-email/resend [post]
+func (c *AuthController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := c.service.ResendVerification(r.Context(), userID); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"message": "verification email sent"})
+}
+
+// LoginStatus godoc
+// @Summary Get login lockout status of a user
+// @Tags admin-users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} contracts.LoginStatusDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/login-attempts [get]
+func (c *AuthController) LoginStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	status, err := c.service.LoginStatus(r.Context(), id)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// UnlockAccount godoc
+// @Summary Unlock a user locked out after failed logins
+// @Tags admin-users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/unlock [post]
+func (c *AuthController) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.UnlockAccount(r.Context(), id); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "account unlocked successfully"})
+}
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys that verify access tokens issued by this server
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (c *AuthController) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, auth.JWKS())
+}
+
+// clientIP returns the caller's address. Proxy headers are only honoured when
+// TRUST_PROXY_HEADERS is set, since clients can forge them.
+func clientIP(r *http.Request) string {
+	if os.Getenv("TRUST_PROXY_HEADERS") == "true" {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			return strings.TrimSpace(strings.Split(fwd, ",")[0])
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return strings.TrimSpace(realIP)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func handleAuthError(w http.ResponseWriter, err error) {
 	switch e := err.(type) {
 	case contracts.ValidationErrors:
 		writeError(w, http.StatusBadRequest, "validation failed", e)
+	case *contracts.RateLimitError:
+		writeRateLimited(w, e)
 	default:
 		switch err {
 		case contracts.ErrEmailInUse:
 			writeError(w, http.StatusConflict, err.Error(), nil)
-		case contracts.ErrInvalidCredentials:
+		case contracts.ErrInvalidResetToken, contracts.ErrInvalidVerifyToken:
+			writeError(w, http.StatusBadRequest, err.Error(), nil)
+		case contracts.ErrEmailVerified:
+			writeError(w, http.StatusConflict, err.Error(), nil)
+		case contracts.ErrUserNotFound:
+			writeError(w, http.StatusNotFound, err.Error(), nil)
+		case contracts.ErrInvalidCredentials, contracts.ErrInvalidRefresh, contracts.ErrRefreshReused,
+			contracts.ErrInvalidMFAToken, contracts.ErrInvalidMFACode:
 			writeError(w, http.StatusUnauthorized, err.Error(), nil)
+		case contracts.ErrMFANotEnabled, contracts.ErrMFANotStarted:
+			writeError(w, http.StatusBadRequest, err.Error(), nil)
+		case contracts.ErrMFAAlreadyEnabled:
+			writeError(w, http.StatusConflict, err.Error(), nil)
+		case contracts.ErrMFARequired:
+			writeError(w, http.StatusForbidden, err.Error(), nil)
 		default:
 			writeError(w, http.StatusInternalServerError, "internal server error", nil)
 		}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// EnrollmentController lets students register for course sections.
+type EnrollmentController struct {
+	service *services.EnrollmentService
+}
+
+func NewEnrollmentController(service *services.EnrollmentService) *EnrollmentController {
+	return &EnrollmentController{service: service}
+}
+
+// ListEnrollments godoc
+// @Summary List my enrollments
+// @Tags student-enrollments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Success 200 {array} contracts.EnrollmentDTO
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/enrollments [get]
+func (c *EnrollmentController) ListEnrollments(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	enrollments, err := c.service.ListEnrollments(r.Context(), studentID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleEnrollmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, enrollments)
+}
+
+// Enroll godoc
+// @Summary Enroll in a course section
+// @Description Takes a seat, or a waitlist place when the section is full. Only allowed while the term's registration or add/drop window is open and once the subject's prerequisites are met. Sections that clash with the student's timetable are rejected with 409.
+// @Tags student-enrollments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param enrollment body contracts.EnrollmentInput true "Enrollment payload"
+// @Success 201 {object} contracts.EnrollmentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /student/enrollments [post]
+func (c *EnrollmentController) Enroll(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.EnrollmentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	enrollment, err := c.service.Enroll(r.Context(), studentID, input)
+	if err != nil {
+		handleEnrollmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, enrollment)
+}
+
+// Drop godoc
+// @Summary Drop an enrollment or leave a waitlist
+// @Tags student-enrollments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Enrollment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/enrollments/{id} [delete]
+func (c *EnrollmentController) Drop(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.Drop(r.Context(), studentID, id); err != nil {
+		handleEnrollmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "enrollment dropped successfully"})
+}
+
+func handleEnrollmentError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	case *contracts.EligibilityError:
+		writeIneligible(w, e)
+		return
+	case *contracts.ScheduleConflictError:
+		writeScheduleConflict(w, e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrEnrollmentNotFound, contracts.ErrSectionNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrAlreadyEnrolled:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	case contracts.ErrRegistrationClosed:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
+
+// writeIneligible lists every unmet prerequisite group in the error details.
+func writeIneligible(w http.ResponseWriter, err *contracts.EligibilityError) {
+	details := make(contracts.ValidationErrors, 0, len(err.Missing))
+	for _, m := range err.Missing {
+		details = append(details, contracts.ValidationError{Field: "requisites", Message: m.Description})
+	}
+	writeError(w, http.StatusForbidden, err.Error(), details)
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+)
+
+// GradeScaleController manages the letter grade scale.
+type GradeScaleController struct {
+	service *services.GradeScaleService
+}
+
+func NewGradeScaleController(service *services.GradeScaleService) *GradeScaleController {
+	return &GradeScaleController{service: service}
+}
+
+// GetScale godoc
+// @Summary Get the letter grade scale
+// @Tags admin-grades
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} contracts.GradeBandDTO
+// @Router /admin/grade-scale [get]
+func (c *GradeScaleController) GetScale(w http.ResponseWriter, r *http.Request) {
+	bands, err := c.service.GetScale(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, bands)
+}
+
+// ReplaceScale godoc
+// @Summary Replace the letter grade scale
+// @Description A final mark gets the letter of the highest band whose min_score it reaches; one band must start at 0.
+// @Tags admin-grades
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param scale body contracts.GradeScaleInput true "Grade bands"
+// @Success 200 {array} contracts.GradeBandDTO
+// @Failure 400 {object} ErrorResponse
+// @Router /admin/grade-scale [put]
+func (c *GradeScaleController) ReplaceScale(w http.ResponseWriter, r *http.Request) {
+	var input contracts.GradeScaleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	bands, err := c.service.ReplaceScale(r.Context(), input)
+	if err != nil {
+		if errs, ok := err.(contracts.ValidationErrors); ok {
+			writeError(w, http.StatusBadRequest, "validation failed", errs)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, bands)
+}
@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// GradebookController lets teachers grade their sections and students read
+// their own marks.
+type GradebookController struct {
+	service *services.GradebookService
+}
+
+func NewGradebookController(service *services.GradebookService) *GradebookController {
+	return &GradebookController{service: service}
+}
+
+// ListComponents godoc
+// @Summary List assessment components of a section
+// @Tags teacher-gradebook
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {array} contracts.ComponentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sections/{id}/components [get]
+func (c *GradebookController) ListComponents(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	components, err := c.service.ListComponents(r.Context(), teacherID, id)
+	if err != nil {
+		handleGradebookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, components)
+}
+
+// ReplaceComponents godoc
+// @Summary Replace assessment components of a section
+// @Description Weights are percent and must add up to 100. Components passed with their id keep their scores; omitted components are deleted with their scores.
+// @Tags teacher-gradebook
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Param components body contracts.ComponentsInput true "Components"
+// @Success 200 {array} contracts.ComponentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sections/{id}/components [put]
+func (c *GradebookController) ReplaceComponents(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.ComponentsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	components, err := c.service.ReplaceComponents(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleGradebookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, components)
+}
+
+// GetGradebook godoc
+// @Summary Get the gradebook of a section
+// @Description Scores, weighted marks and letter grades of every enrolled student.
+// @Tags teacher-gradebook
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {object} contracts.GradebookDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sections/{id}/gradebook [get]
+func (c *GradebookController) GetGradebook(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	gradebook, err := c.service.GetGradebook(r.Context(), teacherID, id)
+	if err != nil {
+		handleGradebookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gradebook)
+}
+
+// RecordScores godoc
+// @Summary Enter scores in bulk
+// @Description Sets or clears (null points) scores of enrolled students. The batch is applied in full or not at all. Components fed by an assignment take their scores from it and are rejected.
+// @Tags teacher-gradebook
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Param scores body contracts.BulkScoresInput true "Score entries"
+// @Success 200 {object} contracts.GradebookDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/sections/{id}/scores [put]
+func (c *GradebookController) RecordScores(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.BulkScoresInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	gradebook, err := c.service.RecordScores(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleGradebookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gradebook)
+}
+
+// ListMyGrades godoc
+// @Summary List my grades
+// @Tags student-grades
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Success 200 {array} contracts.SectionGradeDTO
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/grades [get]
+func (c *GradebookController) ListMyGrades(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	grades, err := c.service.ListStudentGrades(r.Context(), studentID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleGradebookError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, grades)
+}
+
+func handleGradebookError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrSectionNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrNotSectionTeacher:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
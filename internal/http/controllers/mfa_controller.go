@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// MFAController handles two-factor enrollment and the second login step.
+type MFAController struct {
+	service *services.AuthService
+}
+
+func NewMFAController(service *services.AuthService) *MFAController {
+	return &MFAController{service: service}
+}
+
+// Login godoc
+// @Summary Complete two-factor login
+// @Description Exchange the mfa_token returned by /login and a TOTP or recovery code for a JWT
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body contracts.MFALoginInput true "MFA token and code"
+// @Success 200 {object} contracts.AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /login/mfa [post]
+func (c *MFAController) Login(w http.ResponseWriter, r *http.Request) {
+	var input contracts.MFALoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+	input.IP = clientIP(r)
+
+	resp, err := c.service.LoginMFA(r.Context(), input)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// EnrollTOTP godoc
+// @Summary Start TOTP enrollment
+// @Description Generate a TOTP secret and the otpauth:// provisioning URI to render as a QR code
+// @Tags mfa
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} contracts.TOTPEnrollmentDTO
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /mfa/totp/enroll [post]
+func (c *MFAController) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	resp, err := c.service.EnrollTOTP(r.Context(), userID)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirm TOTP enrollment
+// @Description Enable two-factor authentication with a code from the authenticator app. Returns recovery codes and new tokens.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body contracts.MFACodeInput true "TOTP code"
+// @Success 200 {object} contracts.TOTPConfirmResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /mfa/totp/confirm [post]
+func (c *MFAController) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.MFACodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+	input.IP = clientIP(r)
+
+	resp, err := c.service.ConfirmTOTP(r.Context(), userID, input)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// DisableTOTP godoc
+// @Summary Disable TOTP
+// @Description Turn two-factor authentication off. Every session is signed out, so the current tokens stop working too.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body contracts.MFACodeInput true "TOTP or recovery code"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /mfa/totp [delete]
+func (c *MFAController) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.MFACodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+	input.IP = clientIP(r)
+
+	if err := c.service.DisableTOTP(r.Context(), userID, input); err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body contracts.MFACodeInput true "TOTP code"
+// @Success 200 {object} contracts.RecoveryCodesDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /mfa/recovery-codes [post]
+func (c *MFAController) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.MFACodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+	input.IP = clientIP(r)
+
+	resp, err := c.service.RegenerateRecoveryCodes(r.Context(), userID, input)
+	if err != nil {
+		handleAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,501 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// QuizController lets teachers build quizzes and review attempts, and
+// students take them.
+type QuizController struct {
+	service *services.QuizService
+}
+
+func NewQuizController(service *services.QuizService) *QuizController {
+	return &QuizController{service: service}
+}
+
+// ListTeacherQuizzes godoc
+// @Summary List quizzes of my sections and subjects
+// @Tags teacher-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Success 200 {array} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quizzes [get]
+func (c *QuizController) ListTeacherQuizzes(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	quizzes, err := c.service.ListTeacherQuizzes(r.Context(), teacherID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quizzes)
+}
+
+// GetTeacherQuiz godoc
+// @Summary Get a quiz of my section or subject
+// @Description Includes the questions with their answer key.
+// @Tags teacher-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Success 200 {object} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quizzes/{id} [get]
+func (c *QuizController) GetTeacherQuiz(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	quiz, err := c.service.GetTeacherQuiz(r.Context(), teacherID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quiz)
+}
+
+// CreateQuiz godoc
+// @Summary Create a quiz
+// @Description Sets a quiz for one of my sections (section_id) or for every section of one of my subjects (subject_id). Single and multi choice, true/false, numeric and short answer questions are supported.
+// @Tags teacher-quizzes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param quiz body contracts.QuizInput true "Quiz payload"
+// @Success 201 {object} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quizzes [post]
+func (c *QuizController) CreateQuiz(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.QuizInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	quiz, err := c.service.CreateQuiz(r.Context(), teacherID, input)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, quiz)
+}
+
+// UpdateQuiz godoc
+// @Summary Update a quiz
+// @Description Replaces the title, description and settings. Attempts already started keep their deadline.
+// @Tags teacher-quizzes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Param quiz body contracts.UpdateQuizInput true "Quiz payload"
+// @Success 200 {object} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quizzes/{id} [put]
+func (c *QuizController) UpdateQuiz(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.UpdateQuizInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	quiz, err := c.service.UpdateQuiz(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quiz)
+}
+
+// ReplaceQuestions godoc
+// @Summary Replace the questions of a quiz
+// @Description Questions cannot change once a student has started an attempt.
+// @Tags teacher-quizzes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Param questions body contracts.QuizQuestionsInput true "Questions payload"
+// @Success 200 {object} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/quizzes/{id}/questions [put]
+func (c *QuizController) ReplaceQuestions(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.QuizQuestionsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	quiz, err := c.service.ReplaceQuestions(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quiz)
+}
+
+// DeleteQuiz godoc
+// @Summary Delete a quiz
+// @Description Quizzes that have been attempted cannot be deleted.
+// @Tags teacher-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/quizzes/{id} [delete]
+func (c *QuizController) DeleteQuiz(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteQuiz(r.Context(), teacherID, id); err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "quiz deleted successfully"})
+}
+
+// ListAttempts godoc
+// @Summary List attempts at a quiz
+// @Tags teacher-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Success 200 {array} contracts.QuizAttemptSummaryDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quizzes/{id}/attempts [get]
+func (c *QuizController) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	attempts, err := c.service.ListAttempts(r.Context(), teacherID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attempts)
+}
+
+// Statistics godoc
+// @Summary Get item statistics of a quiz
+// @Description Difficulty and discrimination index of every question, and how often each option was picked, from each student's first submitted attempt.
+// @Tags teacher-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Success 200 {object} contracts.QuizStatisticsDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quizzes/{id}/statistics [get]
+func (c *QuizController) Statistics(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := c.service.Statistics(r.Context(), teacherID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// GetTeacherAttempt godoc
+// @Summary Get an attempt at a quiz of mine
+// @Tags teacher-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Attempt ID"
+// @Success 200 {object} contracts.QuizAttemptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quiz-attempts/{id} [get]
+func (c *QuizController) GetTeacherAttempt(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	attempt, err := c.service.GetTeacherAttempt(r.Context(), teacherID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attempt)
+}
+
+// GradeAnswer godoc
+// @Summary Grade an answer by hand
+// @Description Grades a short answer that had no accepted answers to match, or overrides an automatic grade. The attempt is scored once every answer is graded.
+// @Tags teacher-quizzes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Answer ID"
+// @Param grade body contracts.GradeAnswerInput true "Grade payload"
+// @Success 200 {object} contracts.QuizAttemptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/quiz-answers/{id}/grade [put]
+func (c *QuizController) GradeAnswer(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.GradeAnswerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	attempt, err := c.service.GradeAnswer(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attempt)
+}
+
+// ListStudentQuizzes godoc
+// @Summary List quizzes of my sections
+// @Tags student-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Success 200 {array} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/quizzes [get]
+func (c *QuizController) ListStudentQuizzes(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	quizzes, err := c.service.ListStudentQuizzes(r.Context(), studentID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quizzes)
+}
+
+// GetStudentQuiz godoc
+// @Summary Get a quiz with my attempts
+// @Tags student-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Success 200 {object} contracts.QuizDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/quizzes/{id} [get]
+func (c *QuizController) GetStudentQuiz(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	quiz, err := c.service.GetStudentQuiz(r.Context(), studentID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quiz)
+}
+
+// StartAttempt godoc
+// @Summary Start an attempt at a quiz
+// @Description The attempt's deadline is fixed when it starts. Answers arriving after it are rejected and the attempt is submitted with the answers saved in time.
+// @Tags student-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Quiz ID"
+// @Success 201 {object} contracts.QuizAttemptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /student/quizzes/{id}/attempts [post]
+func (c *QuizController) StartAttempt(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	attempt, err := c.service.StartAttempt(r.Context(), studentID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, attempt)
+}
+
+// GetStudentAttempt godoc
+// @Summary Get one of my quiz attempts
+// @Tags student-quizzes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Attempt ID"
+// @Success 200 {object} contracts.QuizAttemptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/quiz-attempts/{id} [get]
+func (c *QuizController) GetStudentAttempt(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	attempt, err := c.service.GetStudentAttempt(r.Context(), studentID, id)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attempt)
+}
+
+// SaveAnswers godoc
+// @Summary Save answers of a quiz attempt
+// @Description Questions left out keep their saved answer. After the deadline the answers are rejected and the attempt is submitted.
+// @Tags student-quizzes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Attempt ID"
+// @Param answers body contracts.QuizAnswersInput true "Answers payload"
+// @Success 200 {object} contracts.QuizAttemptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /student/quiz-attempts/{id}/answers [put]
+func (c *QuizController) SaveAnswers(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.QuizAnswersInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	attempt, err := c.service.SaveAnswers(r.Context(), studentID, id, input)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attempt)
+}
+
+// SubmitAttempt godoc
+// @Summary Submit a quiz attempt
+// @Description Saves the answers in the body, if any, and submits the attempt for grading.
+// @Tags student-quizzes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Attempt ID"
+// @Param answers body contracts.QuizAnswersInput false "Last answers"
+// @Success 200 {object} contracts.QuizAttemptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /student/quiz-attempts/{id}/submit [post]
+func (c *QuizController) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
+	studentID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.QuizAnswersInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	attempt, err := c.service.SubmitAttempt(r.Context(), studentID, id, input)
+	if err != nil {
+		handleQuizError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attempt)
+}
+
+func handleQuizError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrQuizNotFound, contracts.ErrAttemptNotFound, contracts.ErrAnswerNotFound, contracts.ErrSectionNotFound,
+		contracts.ErrSubjectNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrNotSectionTeacher, contracts.ErrNotSubjectTeacher, contracts.ErrQuizNotOpen, contracts.ErrNoAttemptsLeft:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	case contracts.ErrQuizInUse, contracts.ErrQuizAttempted, contracts.ErrAttemptInProgress, contracts.ErrAttemptSubmitted,
+		contracts.ErrTimeUp:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
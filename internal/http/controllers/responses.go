@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/arman300s/uni-portal/internal/core/contracts"
 )
@@ -24,3 +26,11 @@ func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 func writeError(w http.ResponseWriter, status int, message string, details contracts.ValidationErrors) {
 	writeJSON(w, status, ErrorResponse{Error: message, Details: details})
 }
+
+func writeRateLimited(w http.ResponseWriter, err *contracts.RateLimitError) {
+	if err.RetryAfter > 0 {
+		seconds := int(math.Ceil(err.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	writeError(w, http.StatusTooManyRequests, err.Message, nil)
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+)
+
+// RoleController manages roles and permissions for admins.
+type RoleController struct {
+	service *services.RoleService
+}
+
+func NewRoleController(service *services.RoleService) *RoleController {
+	return &RoleController{service: service}
+}
+
+// ListPermissions godoc
+// @Summary List permissions
+// @Tags admin-roles
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} contracts.PermissionDTO
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/permissions [get]
+func (c *RoleController) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := c.service.ListPermissions(r.Context())
+	if err != nil {
+		handleRoleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, permissions)
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Tags admin-roles
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} contracts.RoleDTO
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/roles [get]
+func (c *RoleController) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := c.service.ListRoles(r.Context())
+	if err != nil {
+		handleRoleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+// GetRole godoc
+// @Summary Get role
+// @Tags admin-roles
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} contracts.RoleDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/roles/{id} [get]
+func (c *RoleController) GetRole(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	role, err := c.service.GetRole(r.Context(), id)
+	if err != nil {
+		handleRoleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, role)
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Tags admin-roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param role body contracts.RoleInput true "Role payload"
+// @Success 201 {object} contracts.RoleDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/roles [post]
+func (c *RoleController) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var input contracts.RoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	role, err := c.service.CreateRole(r.Context(), input)
+	if err != nil {
+		handleRoleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, role)
+}
+
+// UpdateRole godoc
+// @Summary Rename role or replace its permissions
+// @Tags admin-roles
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Role ID"
+// @Param role body contracts.RoleInput true "Role payload"
+// @Success 200 {object} contracts.RoleDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/roles/{id} [put]
+func (c *RoleController) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.RoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	role, err := c.service.UpdateRole(r.Context(), id, input)
+	if err != nil {
+		handleRoleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, role)
+}
+
+// DeleteRole godoc
+// @Summary Delete role
+// @Description Roles that still have users are only deleted when reassign_to names a role to move them to.
+// @Tags admin-roles
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Role ID"
+// @Param reassign_to query int false "Role ID to move the role's users to"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/roles/{id} [delete]
+func (c *RoleController) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var reassignTo *uint
+	if raw := r.URL.Query().Get("reassign_to"); raw != "" {
+		target, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid reassign_to", nil)
+			return
+		}
+		t := uint(target)
+		reassignTo = &t
+	}
+
+	if err := c.service.DeleteRole(r.Context(), id, reassignTo); err != nil {
+		handleRoleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "role deleted successfully"})
+}
+
+func handleRoleError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrRoleNotFound:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrRoleExists, contracts.ErrRoleInUse:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	case contracts.ErrSystemRole:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+)
+
+// RoomController manages buildings and rooms for admins.
+type RoomController struct {
+	service *services.RoomService
+}
+
+func NewRoomController(service *services.RoomService) *RoomController {
+	return &RoomController{service: service}
+}
+
+// ListBuildings godoc
+// @Summary List buildings
+// @Tags admin-rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} contracts.BuildingDTO
+// @Router /admin/buildings [get]
+func (c *RoomController) ListBuildings(w http.ResponseWriter, r *http.Request) {
+	buildings, err := c.service.ListBuildings(r.Context())
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildings)
+}
+
+// CreateBuilding godoc
+// @Summary Create building
+// @Tags admin-rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param building body contracts.BuildingInput true "Building payload"
+// @Success 201 {object} contracts.BuildingDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/buildings [post]
+func (c *RoomController) CreateBuilding(w http.ResponseWriter, r *http.Request) {
+	var input contracts.BuildingInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	building, err := c.service.CreateBuilding(r.Context(), input)
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, building)
+}
+
+// UpdateBuilding godoc
+// @Summary Update building
+// @Tags admin-rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Building ID"
+// @Param building body contracts.BuildingInput true "Building payload"
+// @Success 200 {object} contracts.BuildingDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/buildings/{id} [put]
+func (c *RoomController) UpdateBuilding(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.BuildingInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	building, err := c.service.UpdateBuilding(r.Context(), id, input)
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, building)
+}
+
+// DeleteBuilding godoc
+// @Summary Delete building
+// @Tags admin-rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Building ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/buildings/{id} [delete]
+func (c *RoomController) DeleteBuilding(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.DeleteBuilding(r.Context(), id); err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "building deleted successfully"})
+}
+
+// ListRooms godoc
+// @Summary List rooms
+// @Tags admin-rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param building_id query int false "Building ID"
+// @Success 200 {array} contracts.RoomDTO
+// @Failure 400 {object} ErrorResponse
+// @Router /admin/rooms [get]
+func (c *RoomController) ListRooms(w http.ResponseWriter, r *http.Request) {
+	var buildingID uint
+	if raw := r.URL.Query().Get("building_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid building_id", nil)
+			return
+		}
+		buildingID = uint(id)
+	}
+
+	rooms, err := c.service.ListRooms(r.Context(), buildingID)
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rooms)
+}
+
+// GetRoom godoc
+// @Summary Get room
+// @Tags admin-rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} contracts.RoomDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/rooms/{id} [get]
+func (c *RoomController) GetRoom(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	room, err := c.service.GetRoom(r.Context(), id)
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, room)
+}
+
+// CreateRoom godoc
+// @Summary Create room
+// @Tags admin-rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param room body contracts.RoomInput true "Room payload"
+// @Success 201 {object} contracts.RoomDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/rooms [post]
+func (c *RoomController) CreateRoom(w http.ResponseWriter, r *http.Request) {
+	var input contracts.RoomInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	room, err := c.service.CreateRoom(r.Context(), input)
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, room)
+}
+
+// UpdateRoom godoc
+// @Summary Update room
+// @Tags admin-rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Param room body contracts.RoomInput true "Room payload"
+// @Success 200 {object} contracts.RoomDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/rooms/{id} [put]
+func (c *RoomController) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.RoomInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	room, err := c.service.UpdateRoom(r.Context(), id, input)
+	if err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, room)
+}
+
+// DeleteRoom godoc
+// @Summary Delete room
+// @Tags admin-rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/rooms/{id} [delete]
+func (c *RoomController) DeleteRoom(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.DeleteRoom(r.Context(), id); err != nil {
+		handleRoomError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "room deleted successfully"})
+}
+
+func handleRoomError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrBuildingNotFound, contracts.ErrRoomNotFound:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrBuildingExists, contracts.ErrBuildingInUse, contracts.ErrRoomExists, contracts.ErrRoomInUse:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
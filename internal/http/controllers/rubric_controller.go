@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// RubricController lets teachers keep reusable grading rubrics.
+type RubricController struct {
+	service *services.RubricService
+}
+
+func NewRubricController(service *services.RubricService) *RubricController {
+	return &RubricController{service: service}
+}
+
+// ListRubrics godoc
+// @Summary List my rubrics
+// @Tags teacher-rubrics
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} contracts.RubricDTO
+// @Failure 401 {object} ErrorResponse
+// @Router /teacher/rubrics [get]
+func (c *RubricController) ListRubrics(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	rubrics, err := c.service.ListRubrics(r.Context(), teacherID)
+	if err != nil {
+		handleRubricError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rubrics)
+}
+
+// GetRubric godoc
+// @Summary Get one of my rubrics
+// @Tags teacher-rubrics
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Rubric ID"
+// @Success 200 {object} contracts.RubricDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/rubrics/{id} [get]
+func (c *RubricController) GetRubric(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	rubric, err := c.service.GetRubric(r.Context(), teacherID, id)
+	if err != nil {
+		handleRubricError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rubric)
+}
+
+// CreateRubric godoc
+// @Summary Create a rubric
+// @Description Criteria and their levels are kept in the given order. A criterion is worth the points of its best level.
+// @Tags teacher-rubrics
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param rubric body contracts.RubricInput true "Rubric payload"
+// @Success 201 {object} contracts.RubricDTO
+// @Failure 400 {object} ErrorResponse
+// @Router /teacher/rubrics [post]
+func (c *RubricController) CreateRubric(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var input contracts.RubricInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	rubric, err := c.service.CreateRubric(r.Context(), teacherID, input)
+	if err != nil {
+		handleRubricError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, rubric)
+}
+
+// UpdateRubric godoc
+// @Summary Replace a rubric
+// @Description Rubrics that have been used for grading cannot be changed.
+// @Tags teacher-rubrics
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Rubric ID"
+// @Param rubric body contracts.RubricInput true "Rubric payload"
+// @Success 200 {object} contracts.RubricDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/rubrics/{id} [put]
+func (c *RubricController) UpdateRubric(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	var input contracts.RubricInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	rubric, err := c.service.UpdateRubric(r.Context(), teacherID, id, input)
+	if err != nil {
+		handleRubricError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rubric)
+}
+
+// DeleteRubric godoc
+// @Summary Delete a rubric
+// @Description Rubrics attached to an assignment cannot be deleted.
+// @Tags teacher-rubrics
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Rubric ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /teacher/rubrics/{id} [delete]
+func (c *RubricController) DeleteRubric(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteRubric(r.Context(), teacherID, id); err != nil {
+		handleRubricError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "rubric deleted successfully"})
+}
+
+func handleRubricError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrRubricNotFound:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrRubricInUse, contracts.ErrRubricGraded:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
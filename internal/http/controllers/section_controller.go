@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+)
+
+// AdminSectionController manages course sections for admins.
+type AdminSectionController struct {
+	service *services.SectionService
+}
+
+func NewAdminSectionController(service *services.SectionService) *AdminSectionController {
+	return &AdminSectionController{service: service}
+}
+
+// ListSections godoc
+// @Summary List course sections
+// @Tags admin-sections
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
+// @Param subject_id query int false "Subject ID"
+// @Success 200 {array} contracts.SectionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/sections [get]
+func (c *AdminSectionController) ListSections(w http.ResponseWriter, r *http.Request) {
+	var subjectID uint
+	if raw := r.URL.Query().Get("subject_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid subject_id", nil)
+			return
+		}
+		subjectID = uint(id)
+	}
+
+	sections, err := c.service.ListSections(r.Context(), r.URL.Query().Get("term"), subjectID)
+	if err != nil {
+		handleSectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sections)
+}
+
+// GetSection godoc
+// @Summary Get course section
+// @Tags admin-sections
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {object} contracts.SectionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/sections/{id} [get]
+func (c *AdminSectionController) GetSection(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	section, err := c.service.GetSection(r.Context(), id)
+	if err != nil {
+		handleSectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, section)
+}
+
+// CreateSection godoc
+// @Summary Open a course section in a term
+// @Tags admin-sections
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param section body contracts.SectionInput true "Section payload"
+// @Success 201 {object} contracts.SectionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/sections [post]
+func (c *AdminSectionController) CreateSection(w http.ResponseWriter, r *http.Request) {
+	var input contracts.SectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	section, err := c.service.CreateSection(r.Context(), input)
+	if err != nil {
+		handleSectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, section)
+}
+
+// UpdateSection godoc
+// @Summary Update course section
+// @Tags admin-sections
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Param section body contracts.UpdateSectionInput true "Section payload"
+// @Success 200 {object} contracts.SectionDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/sections/{id} [put]
+func (c *AdminSectionController) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.UpdateSectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	section, err := c.service.UpdateSection(r.Context(), id, input)
+	if err != nil {
+		handleSectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, section)
+}
+
+// DeleteSection godoc
+// @Summary Delete course section
+// @Tags admin-sections
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/sections/{id} [delete]
+func (c *AdminSectionController) DeleteSection(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.DeleteSection(r.Context(), id); err != nil {
+		handleSectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "section deleted successfully"})
+}
+
+func handleSectionError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	case *contracts.ScheduleConflictError:
+		writeScheduleConflict(w, e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrSectionNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrSectionExists, contracts.ErrSectionInUse:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
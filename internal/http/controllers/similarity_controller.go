@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+)
+
+// SimilarityController shows teachers which submissions look copied.
+type SimilarityController struct {
+	service *services.SimilarityService
+}
+
+func NewSimilarityController(service *services.SimilarityService) *SimilarityController {
+	return &SimilarityController{service: service}
+}
+
+// GetReport godoc
+// @Summary Get the similarity report of an assignment
+// @Description Lists pairs of submissions whose text or code is similar, highest score first, with the passages they share. Submissions are checked when the assignment closes.
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} contracts.SimilarityReportDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id}/similarity [get]
+func (c *SimilarityController) GetReport(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.GetReport(r.Context(), teacherID, id)
+	if err != nil {
+		handleSimilarityError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RequestCheck godoc
+// @Summary Check an assignment's submissions for similarity again
+// @Description Queues a new check, for example after late or extended submissions came in. The previous matches are shown until it finishes.
+// @Tags teacher-assignments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Assignment ID"
+// @Success 202 {object} contracts.SimilarityReportDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/assignments/{id}/similarity [post]
+func (c *SimilarityController) RequestCheck(w http.ResponseWriter, r *http.Request) {
+	teacherID, id, ok := userAndID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := c.service.RequestCheck(r.Context(), teacherID, id)
+	if err != nil {
+		handleSimilarityError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, report)
+}
+
+func handleSimilarityError(w http.ResponseWriter, err error) {
+	switch err {
+	case contracts.ErrAssignmentNotFound, contracts.ErrNoSimilarityReport:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrNotSectionTeacher, contracts.ErrNotSubjectTeacher:
+		writeError(w, http.StatusForbidden, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
@@ -6,6 +6,7 @@ import (
     "github.com/arman300s/uni-portal/internal/core/contracts"
     "github.com/arman300s/uni-portal/internal/core/services"
     "github.com/arman300s/uni-portal/internal/models"
+    "github.com/arman300s/uni-portal/pkg/middleware"
 )
 
 // StudentController exposes student endpoints.
@@ -22,11 +23,14 @@ func NewStudentController(service *services.SubjectService) *StudentController {
 // @Tags student-subjects
 // @Produce json
 // @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\""
 // @Success 200 {array} contracts.SubjectDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /student/subjects [get]
 func (c *StudentController) ListSubjects(w http.ResponseWriter, r *http.Request) {
-    subjects, err := c.service.ListSubjects(r.Context())
+    subjects, err := c.service.ListSubjects(r.Context(), r.URL.Query().Get("term"))
     if err != nil {
         handleSubjectError(w, err)
         return
@@ -38,13 +42,54 @@ func (c *StudentController) ListSubjects(w http.ResponseWriter, r *http.Request)
             ID:          s.ID,
             Name:        s.Name,
             Description: s.Description,
+            CreditHours: s.CreditHours,
             Teachers:    extractTeacherNames(s.Teachers),
+            Terms:       extractTermNames(s.Terms),
         })
     }
 
     writeJSON(w, http.StatusOK, resp)
 }
 
+// CheckEligibility godoc
+// @Summary Check my eligibility for a subject
+// @Description Lists the prerequisite and corequisite groups the student has not met yet.
+// @Tags student-subjects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subject ID"
+// @Param term query string false "Term ID or \"current\" used for corequisites"
+// @Success 200 {object} contracts.EligibilityDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/subjects/{id}/eligibility [get]
+func (c *StudentController) CheckEligibility(w http.ResponseWriter, r *http.Request) {
+    studentID, ok := middleware.UserIDFromContext(r.Context())
+    if !ok {
+        writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+        return
+    }
+
+    id, err := parseSubjectID(r)
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err.Error(), nil)
+        return
+    }
+
+    term := r.URL.Query().Get("term")
+    if term == "" {
+        term = "current"
+    }
+
+    eligibility, err := c.service.CheckEligibility(r.Context(), studentID, id, term)
+    if err != nil {
+        handleSubjectError(w, err)
+        return
+    }
+
+    writeJSON(w, http.StatusOK, eligibility)
+}
+
 func extractTeacherNames(teachers []models.User) []string {
     names := make([]string, 0, len(teachers))
     for _, t := range teachers {
@@ -52,3 +97,11 @@ func extractTeacherNames(teachers []models.User) []string {
     }
     return names
 }
+
+func extractTermNames(terms []models.Term) []string {
+    names := make([]string, 0, len(terms))
+    for _, t := range terms {
+        names = append(names, t.Name)
+    }
+    return names
+}
@@ -3,27 +3,30 @@ package controllers
 import (
 	"net/http"
 
-	"github.com/arman300s/uni-portal/internal/core/contracts"
 	"github.com/arman300s/uni-portal/internal/core/services"
 	"github.com/arman300s/uni-portal/pkg/middleware"
 )
 
 // TeacherController exposes teacher-specific endpoints.
 type TeacherController struct {
-	service *services.SubjectService
+	sections *services.SectionService
 }
 
-func NewTeacherController(service *services.SubjectService) *TeacherController {
-	return &TeacherController{service: service}
+func NewTeacherController(sections *services.SectionService) *TeacherController {
+	return &TeacherController{sections: sections}
 }
 
 // ListMySubjects godoc
-// @Summary List teacher subjects
+// @Summary List teacher sections
+// @Description Sections the caller teaches in the current term, or in the given term.
 // @Tags teacher-subjects
 // @Produce json
 // @Security ApiKeyAuth
-// @Success 200 {array} contracts.SubjectDTO
+// @Param term query string false "Term ID or \"current\" (default)"
+// @Success 200 {array} contracts.SectionDTO
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /teacher/subjects [get]
 func (c *TeacherController) ListMySubjects(w http.ResponseWriter, r *http.Request) {
@@ -33,21 +36,11 @@ func (c *TeacherController) ListMySubjects(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	subjects, err := c.service.ListSubjectsForTeacher(r.Context(), teacherID)
+	sections, err := c.sections.ListTeacherSections(r.Context(), teacherID, r.URL.Query().Get("term"))
 	if err != nil {
-		handleSubjectError(w, err)
+		handleSectionError(w, err)
 		return
 	}
 
-	resp := make([]contracts.SubjectDTO, 0, len(subjects))
-	for _, s := range subjects {
-		resp = append(resp, contracts.SubjectDTO{
-			ID:          s.ID,
-			Name:        s.Name,
-			Description: s.Description,
-			Teachers:    extractTeacherNames(s.Teachers),
-		})
-	}
-
-	writeJSON(w, http.StatusOK, resp)
+	writeJSON(w, http.StatusOK, sections)
 }
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+)
+
+// TermController manages academic terms for admins.
+type TermController struct {
+	service *services.TermService
+}
+
+func NewTermController(service *services.TermService) *TermController {
+	return &TermController{service: service}
+}
+
+// ListTerms godoc
+// @Summary List terms
+// @Tags admin-terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} contracts.TermDTO
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/terms [get]
+func (c *TermController) ListTerms(w http.ResponseWriter, r *http.Request) {
+	terms, err := c.service.ListTerms(r.Context())
+	if err != nil {
+		handleTermError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, terms)
+}
+
+// CurrentTerm godoc
+// @Summary Get the term currently in session
+// @Tags admin-terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} contracts.TermDTO
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/terms/current [get]
+func (c *TermController) CurrentTerm(w http.ResponseWriter, r *http.Request) {
+	term, err := c.service.CurrentTerm(r.Context())
+	if err != nil {
+		handleTermError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, term)
+}
+
+// GetTerm godoc
+// @Summary Get term
+// @Tags admin-terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Term ID"
+// @Success 200 {object} contracts.TermDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/terms/{id} [get]
+func (c *TermController) GetTerm(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	term, err := c.service.GetTerm(r.Context(), id)
+	if err != nil {
+		handleTermError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, term)
+}
+
+// CreateTerm godoc
+// @Summary Create term
+// @Tags admin-terms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term body contracts.TermInput true "Term payload"
+// @Success 201 {object} contracts.TermDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/terms [post]
+func (c *TermController) CreateTerm(w http.ResponseWriter, r *http.Request) {
+	var input contracts.TermInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	term, err := c.service.CreateTerm(r.Context(), input)
+	if err != nil {
+		handleTermError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, term)
+}
+
+// UpdateTerm godoc
+// @Summary Replace term
+// @Tags admin-terms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Term ID"
+// @Param term body contracts.TermInput true "Term payload"
+// @Success 200 {object} contracts.TermDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/terms/{id} [put]
+func (c *TermController) UpdateTerm(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.TermInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	term, err := c.service.UpdateTerm(r.Context(), id, input)
+	if err != nil {
+		handleTermError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, term)
+}
+
+// DeleteTerm godoc
+// @Summary Delete term
+// @Tags admin-terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Term ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/terms/{id} [delete]
+func (c *TermController) DeleteTerm(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.DeleteTerm(r.Context(), id); err != nil {
+		handleTermError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "term deleted successfully"})
+}
+
+func handleTermError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrTermExists, contracts.ErrTermInUse:
+		writeError(w, http.StatusConflict, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// TimetableController serves section meeting patterns, personal timetables
+// and the term conflict report.
+type TimetableController struct {
+	service *services.TimetableService
+}
+
+func NewTimetableController(service *services.TimetableService) *TimetableController {
+	return &TimetableController{service: service}
+}
+
+// GetMeetings godoc
+// @Summary List the weekly meetings of a section
+// @Tags admin-sections
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Success 200 {array} contracts.MeetingDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/sections/{id}/meetings [get]
+func (c *TimetableController) GetMeetings(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	meetings, err := c.service.GetMeetings(r.Context(), id)
+	if err != nil {
+		handleTimetableError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, meetings)
+}
+
+// ReplaceMeetings godoc
+// @Summary Replace the weekly meetings of a section
+// @Description Rejected with 409 when a room, one of the section's teachers or one of its enrolled students would be booked twice at the same time. Online sections meet without a room.
+// @Tags admin-sections
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Section ID"
+// @Param meetings body contracts.MeetingsInput true "Weekly meetings"
+// @Success 200 {array} contracts.MeetingDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/sections/{id}/meetings [put]
+func (c *TimetableController) ReplaceMeetings(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var input contracts.MeetingsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", nil)
+		return
+	}
+
+	meetings, err := c.service.ReplaceMeetings(r.Context(), id, input)
+	if err != nil {
+		handleTimetableError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, meetings)
+}
+
+// ConflictReport godoc
+// @Summary List the timetable conflicts of a term
+// @Tags admin-sections
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\" (default)"
+// @Success 200 {object} contracts.ConflictReportDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/timetable/conflicts [get]
+func (c *TimetableController) ConflictReport(w http.ResponseWriter, r *http.Request) {
+	report, err := c.service.ConflictReport(r.Context(), r.URL.Query().Get("term"))
+	if err != nil {
+		handleTimetableError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// StudentTimetable godoc
+// @Summary Get my weekly timetable
+// @Description Meetings of the sections the student is enrolled in. Empty between terms.
+// @Tags student-timetable
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\" (default)"
+// @Success 200 {array} contracts.TimetableEntryDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /student/timetable [get]
+func (c *TimetableController) StudentTimetable(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	entries, err := c.service.StudentTimetable(r.Context(), userID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleTimetableError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// TeacherTimetable godoc
+// @Summary Get my weekly teaching timetable
+// @Description Meetings of the sections the teacher teaches. Empty between terms.
+// @Tags teacher-timetable
+// @Produce json
+// @Security ApiKeyAuth
+// @Param term query string false "Term ID or \"current\" (default)"
+// @Success 200 {array} contracts.TimetableEntryDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /teacher/timetable [get]
+func (c *TimetableController) TeacherTimetable(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	entries, err := c.service.TeacherTimetable(r.Context(), userID, r.URL.Query().Get("term"))
+	if err != nil {
+		handleTimetableError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func handleTimetableError(w http.ResponseWriter, err error) {
+	switch e := err.(type) {
+	case contracts.ValidationErrors:
+		writeError(w, http.StatusBadRequest, "validation failed", e)
+		return
+	case *contracts.ScheduleConflictError:
+		writeScheduleConflict(w, e)
+		return
+	}
+
+	switch err {
+	case contracts.ErrSectionNotFound, contracts.ErrTermNotFound, contracts.ErrNoCurrentTerm:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
+
+// writeScheduleConflict lists every clash in the error details, one per
+// double-booked room, teacher or set of students.
+func writeScheduleConflict(w http.ResponseWriter, err *contracts.ScheduleConflictError) {
+	details := make(contracts.ValidationErrors, 0, len(err.Conflicts))
+	for _, c := range err.Conflicts {
+		details = append(details, contracts.ValidationError{Field: c.Kind, Message: describeConflict(c)})
+	}
+	writeError(w, http.StatusConflict, err.Error(), details)
+}
+
+func describeConflict(c contracts.ConflictDTO) string {
+	sections := make([]string, 0, len(c.Sections))
+	for _, s := range c.Sections {
+		sections = append(sections, fmt.Sprintf("%s %s", s.SubjectName, s.SectionCode))
+	}
+
+	var who string
+	switch c.Kind {
+	case "room":
+		who = "room " + c.Room
+	case "teacher":
+		who = "teacher " + c.TeacherName
+	default:
+		who = fmt.Sprintf("%d student(s)", len(c.StudentIDs))
+	}
+	return fmt.Sprintf("%s booked for %s on %s %s-%s", who, strings.Join(sections, " and "), c.Day, c.Start, c.End)
+}
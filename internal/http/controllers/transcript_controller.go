@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/arman300s/uni-portal/internal/core/contracts"
+	"github.com/arman300s/uni-portal/internal/core/services"
+	"github.com/arman300s/uni-portal/pkg/middleware"
+)
+
+// TranscriptController serves academic transcripts and their public
+// verification.
+type TranscriptController struct {
+	service *services.TranscriptService
+}
+
+func NewTranscriptController(service *services.TranscriptService) *TranscriptController {
+	return &TranscriptController{service: service}
+}
+
+// GetMyTranscript godoc
+// @Summary Get my transcript
+// @Description Term and cumulative GPA of the caller. Pass format=pdf to download the official PDF; only that issues the transcript with a verification hash.
+// @Tags student-grades
+// @Produce json
+// @Produce application/pdf
+// @Security ApiKeyAuth
+// @Param format query string false "json (default) or pdf"
+// @Success 200 {object} contracts.TranscriptDTO
+// @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /student/transcript [get]
+func (c *TranscriptController) GetMyTranscript(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	c.writeTranscript(w, r, studentID)
+}
+
+// GetUserTranscript godoc
+// @Summary Get a user's transcript
+// @Description Pass format=pdf to download the official PDF; only that issues the transcript with a verification hash.
+// @Tags admin-users
+// @Produce json
+// @Produce application/pdf
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param format query string false "json (default) or pdf"
+// @Success 200 {object} contracts.TranscriptDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /admin/users/{id}/transcript [get]
+func (c *TranscriptController) GetUserTranscript(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	c.writeTranscript(w, r, id)
+}
+
+// VerifyTranscript godoc
+// @Summary Verify an issued transcript
+// @Description Public check of the verification hash printed on a transcript.
+// @Tags transcripts
+// @Produce json
+// @Param hash path string true "Verification hash"
+// @Success 200 {object} contracts.TranscriptVerificationDTO
+// @Failure 404 {object} ErrorResponse
+// @Router /verify/transcript/{hash} [get]
+func (c *TranscriptController) VerifyTranscript(w http.ResponseWriter, r *http.Request) {
+	verification, err := c.service.VerifyTranscript(r.Context(), mux.Vars(r)["hash"])
+	if err != nil {
+		handleTranscriptError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, verification)
+}
+
+func (c *TranscriptController) writeTranscript(w http.ResponseWriter, r *http.Request, studentID uint) {
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		transcript, err := c.service.GetTranscript(r.Context(), studentID)
+		if err != nil {
+			handleTranscriptError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, transcript)
+	case "pdf":
+		doc, transcript, err := c.service.TranscriptPDF(r.Context(), studentID)
+		if err != nil {
+			handleTranscriptError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="transcript-%d.pdf"`, studentID))
+		w.Header().Set("X-Transcript-Hash", transcript.VerificationHash)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(doc)
+	default:
+		writeError(w, http.StatusBadRequest, "format must be json or pdf", nil)
+	}
+}
+
+func handleTranscriptError(w http.ResponseWriter, err error) {
+	switch err {
+	case contracts.ErrUserNotFound, contracts.ErrTranscriptNotFound:
+		writeError(w, http.StatusNotFound, err.Error(), nil)
+	case contracts.ErrUnprintableText:
+		writeError(w, http.StatusUnprocessableEntity, err.Error(), nil)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error", nil)
+	}
+}
@@ -48,14 +48,34 @@ func (c *UserController) Me(w http.ResponseWriter, r *http.Request) {
 
 // ListUsers godoc
 // @Summary List users
+// @Description Cursor-paginated user list. Pass next_cursor from the previous page as cursor, keeping the same sort and filters.
 // @Tags admin-users
 // @Produce json
 // @Security ApiKeyAuth
-// @Success 200 {array} contracts.UserDTO
+// @Param role query string false "Role name"
+// @Param q query string false "Substring of name or email"
+// @Param created_from query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "Sort field" Enums(id, name, email, created_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param cursor query string false "Cursor from a previous page"
+// @Success 200 {object} contracts.UserListDTO
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/users [get]
 func (c *UserController) ListUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := c.service.ListUsers(r.Context())
+	q := r.URL.Query()
+	users, err := c.service.ListUsers(r.Context(), contracts.UserListQuery{
+		Role:        q.Get("role"),
+		Search:      q.Get("q"),
+		CreatedFrom: q.Get("created_from"),
+		CreatedTo:   q.Get("created_to"),
+		Sort:        q.Get("sort"),
+		Order:       q.Get("order"),
+		Cursor:      q.Get("cursor"),
+		Limit:       q.Get("limit"),
+	})
 	if err != nil {
 		handleUserError(w, err)
 		return
@@ -177,6 +197,31 @@ func (c *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "user deleted successfully"})
 }
 
+// RevokeSessions godoc
+// @Summary Revoke all sessions of a user
+// @Tags admin-users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/sessions/revoke [post]
+func (c *UserController) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := c.service.RevokeSessions(r.Context(), id); err != nil {
+		handleUserError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "sessions revoked successfully"})
+}
+
 func parseIDParam(r *http.Request) (uint, error) {
 	idStr := mux.Vars(r)["id"]
 	id64, err := strconv.ParseUint(idStr, 10, 32)
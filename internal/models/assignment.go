@@ -0,0 +1,150 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Assignment is work handed out to the students of one section, or of every
+// section of a subject in a term when SectionID is nil.
+//
+// Submissions are accepted without penalty until DueAt plus GraceMinutes.
+// With a CutoffAt they are then accepted until the cutoff, losing
+// LatePenaltyPercent of the score for every started day past the grace
+// period; without one the assignment closes when the grace period ends.
+//
+// Students see grades once GradesReleasedAt is set. From then on the
+// scores are also copied into GradebookComponent, if the assignment feeds
+// one of its section's gradebook components. A component is fed by at most
+// one assignment, and its scores then come only from that assignment.
+type Assignment struct {
+	ID          uint           `gorm:"primary_key"`
+	SubjectID   uint           `gorm:"not null;index"`
+	Subject     *Subject       `gorm:"constraint:OnDelete:RESTRICT;"`
+	TermID      uint           `gorm:"not null;index"`
+	Term        *Term          `gorm:"constraint:OnDelete:RESTRICT;"`
+	SectionID   *uint          `gorm:"index"`
+	Section     *CourseSection `gorm:"constraint:OnDelete:RESTRICT;"`
+	Title       string         `gorm:"size:200;not null"`
+	Description string         `gorm:"type:text"`
+	DueAt       time.Time      `gorm:"not null"`
+	MaxPoints   float64        `gorm:"not null"`
+	// AllowedTypes is a comma-separated list of lower-case file extensions
+	// without dots, such as "pdf,docx". Empty allows any file.
+	AllowedTypes       string  `gorm:"size:255;not null;default:''"`
+	GraceMinutes       int     `gorm:"not null;default:0"`
+	LatePenaltyPercent float64 `gorm:"not null;default:0"`
+	CutoffAt           *time.Time
+	// RubricID is the rubric submissions are graded with, if any.
+	RubricID             *uint                `gorm:"index"`
+	Rubric               *Rubric              `gorm:"constraint:OnDelete:RESTRICT;"`
+	GradebookComponentID *uint                `gorm:"uniqueIndex:idx_assignment_gradebook_component"`
+	GradebookComponent   *AssessmentComponent `gorm:"constraint:OnDelete:SET NULL;"`
+	GradesReleasedAt     *time.Time
+	CreatedByID          *uint
+	CreatedBy            *User `gorm:"constraint:OnDelete:SET NULL;"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// FileTypes returns the allowed file extensions.
+func (a *Assignment) FileTypes() []string {
+	if a.AllowedTypes == "" {
+		return []string{}
+	}
+	return strings.Split(a.AllowedTypes, ",")
+}
+
+// Allows reports whether files with the extension, without the dot, can be
+// submitted.
+func (a *Assignment) Allows(ext string) bool {
+	if a.AllowedTypes == "" {
+		return true
+	}
+	ext = strings.ToLower(ext)
+	for _, t := range a.FileTypes() {
+		if t == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Due returns when a student's work is due, given the student's latest
+// extension, if any.
+func (a *Assignment) Due(extension *AssignmentExtension) time.Time {
+	if extension != nil {
+		return extension.DueAt
+	}
+	return a.DueAt
+}
+
+// ClosesAt returns when the assignment stops accepting a student's work. An
+// extension moves the whole late window along with the due date.
+func (a *Assignment) ClosesAt(extension *AssignmentExtension) time.Time {
+	due := a.Due(extension)
+	closes := due.Add(time.Duration(a.GraceMinutes) * time.Minute)
+	if a.CutoffAt != nil {
+		if cutoff := due.Add(a.CutoffAt.Sub(a.DueAt)); cutoff.After(closes) {
+			closes = cutoff
+		}
+	}
+	return closes
+}
+
+// AssignmentExtension moves one student's due date. Extensions are never
+// changed or deleted, so they double as the audit trail of who granted
+// what; the latest one for a student applies.
+type AssignmentExtension struct {
+	ID           uint        `gorm:"primary_key"`
+	AssignmentID uint        `gorm:"not null;index:idx_extension_assignment_student"`
+	Assignment   *Assignment `gorm:"constraint:OnDelete:CASCADE;"`
+	StudentID    uint        `gorm:"not null;index:idx_extension_assignment_student"`
+	Student      *User       `gorm:"constraint:OnDelete:CASCADE;"`
+	DueAt        time.Time   `gorm:"not null"`
+	Reason       string      `gorm:"size:500"`
+	GrantedByID  *uint
+	GrantedBy    *User `gorm:"constraint:OnDelete:SET NULL;"`
+	CreatedAt    time.Time
+}
+
+// Submission is a student's current hand-in for an assignment. Resubmitting
+// replaces its files, bumps Version and clears any grade.
+//
+// A grade keeps the raw score the teacher gave and the late penalty worked
+// out when it was recorded; Score is what is left after the penalty.
+type Submission struct {
+	ID             uint        `gorm:"primary_key"`
+	AssignmentID   uint        `gorm:"not null;uniqueIndex:idx_submission_assignment_student"`
+	Assignment     *Assignment `gorm:"constraint:OnDelete:RESTRICT;"`
+	StudentID      uint        `gorm:"not null;uniqueIndex:idx_submission_assignment_student;index"`
+	Student        *User       `gorm:"constraint:OnDelete:CASCADE;"`
+	Version        int         `gorm:"not null;default:1"`
+	SubmittedAt    time.Time   `gorm:"not null"`
+	RawScore       *float64
+	DaysLate       int     `gorm:"not null;default:0"`
+	PenaltyPercent float64 `gorm:"not null;default:0"`
+	Score          *float64
+	Feedback       string `gorm:"type:text"`
+	GradedAt       *time.Time
+	GradedByID     *uint
+	GradedBy       *User `gorm:"constraint:OnDelete:SET NULL;"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	Files        []SubmissionFile `gorm:"constraint:OnDelete:CASCADE;"`
+	RubricScores []RubricScore    `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// SubmissionFile is one uploaded file. The content lives in the blob store
+// under a key derived from Checksum, so identical files are stored once.
+type SubmissionFile struct {
+	ID           uint   `gorm:"primary_key"`
+	SubmissionID uint   `gorm:"not null;index"`
+	Name         string `gorm:"size:255;not null"`
+	ContentType  string `gorm:"size:100;not null"`
+	Size         int64  `gorm:"not null"`
+	// Checksum is the hex SHA-256 of the content.
+	Checksum  string `gorm:"size:64;not null;index"`
+	CreatedAt time.Time
+}
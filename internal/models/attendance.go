@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Attendance statuses.
+const (
+	AttendancePresent = "present"
+	AttendanceLate    = "late"
+	AttendanceExcused = "excused"
+	AttendanceAbsent  = "absent"
+)
+
+// Ways an attendance record was made.
+const (
+	AttendanceSourceQR     = "qr"
+	AttendanceSourceManual = "manual"
+)
+
+// ClassSession is one meeting of a course section during which students
+// check in. A section has at most one open session at a time.
+type ClassSession struct {
+	ID         uint           `gorm:"primary_key"`
+	SectionID  uint           `gorm:"not null;index;uniqueIndex:idx_session_open_section,where:closed_at IS NULL"`
+	Section    *CourseSection `gorm:"constraint:OnDelete:CASCADE;"`
+	OpenedByID *uint
+	OpenedBy   *User     `gorm:"constraint:OnDelete:SET NULL;"`
+	StartedAt  time.Time `gorm:"not null"`
+	ClosedAt   *time.Time
+	// RotationSeconds is how long each check-in code is shown.
+	RotationSeconds int `gorm:"not null"`
+	// Check-ins later than LateAfterMinutes after the start count as late.
+	LateAfterMinutes int `gorm:"not null"`
+	CreatedAt        time.Time
+}
+
+// Open reports whether students can still check in.
+func (s *ClassSession) Open() bool {
+	return s.ClosedAt == nil
+}
+
+// AttendanceRecord is a student's attendance at a session. Enrolled students
+// without a record for a closed session were absent.
+type AttendanceRecord struct {
+	ID           uint          `gorm:"primary_key"`
+	SessionID    uint          `gorm:"not null;uniqueIndex:idx_attendance_session_enrollment"`
+	Session      *ClassSession `gorm:"constraint:OnDelete:CASCADE;"`
+	EnrollmentID uint          `gorm:"not null;uniqueIndex:idx_attendance_session_enrollment;index"`
+	Enrollment   *Enrollment   `gorm:"constraint:OnDelete:CASCADE;"`
+	Status       string        `gorm:"size:20;not null"`
+	Source       string        `gorm:"size:20;not null"`
+	CheckedInAt  *time.Time
+	UpdatedByID  *uint
+	UpdatedBy    *User `gorm:"constraint:OnDelete:SET NULL;"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Delivery modes of a course section.
+const (
+	DeliveryInPerson = "in_person"
+	DeliveryOnline   = "online"
+	DeliveryHybrid   = "hybrid"
+)
+
+// CourseSection is a class of a catalog Subject taught in a given Term, such
+// as "Physics, Fall 2026, section 02".
+type CourseSection struct {
+	ID           uint     `gorm:"primary_key"`
+	SubjectID    uint     `gorm:"not null;uniqueIndex:idx_section_subject_term_code"`
+	Subject      *Subject `gorm:"constraint:OnDelete:RESTRICT;"`
+	TermID       uint     `gorm:"not null;uniqueIndex:idx_section_subject_term_code;index"`
+	Term         *Term    `gorm:"constraint:OnDelete:RESTRICT;"`
+	Code         string   `gorm:"size:20;not null;uniqueIndex:idx_section_subject_term_code"`
+	Capacity     int      `gorm:"not null"`
+	DeliveryMode string   `gorm:"size:20;not null;default:in_person"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Teachers []User           `gorm:"many2many:section_teachers;constraint:OnDelete:CASCADE;"`
+	Meetings []SectionMeeting `gorm:"foreignKey:SectionID;constraint:OnDelete:CASCADE;"`
+}
+
+// HasTeacher reports whether the user teaches the section. Teachers must be
+// preloaded.
+func (s *CourseSection) HasTeacher(userID uint) bool {
+	for _, t := range s.Teachers {
+		if t.ID == userID {
+			return true
+		}
+	}
+	return false
+}
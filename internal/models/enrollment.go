@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Enrollment statuses.
+const (
+	EnrollmentEnrolled   = "enrolled"
+	EnrollmentWaitlisted = "waitlisted"
+	EnrollmentDropped    = "dropped"
+)
+
+// Enrollment records a student's seat, or place on the waitlist, in a course
+// section. A student has at most one enrollment per section; dropping keeps
+// the row so that re-enrolling reuses it.
+type Enrollment struct {
+	ID        uint           `gorm:"primary_key"`
+	StudentID uint           `gorm:"not null;uniqueIndex:idx_enrollment_student_section"`
+	Student   *User          `gorm:"constraint:OnDelete:CASCADE;"`
+	SectionID uint           `gorm:"not null;uniqueIndex:idx_enrollment_student_section;index:idx_enrollment_section_status"`
+	Section   *CourseSection `gorm:"constraint:OnDelete:RESTRICT;"`
+	Status    string         `gorm:"size:20;not null;index:idx_enrollment_section_status"`
+
+	// WaitlistedAt orders the waitlist (first come, first served).
+	WaitlistedAt *time.Time
+	EnrolledAt   *time.Time
+	DroppedAt    *time.Time
+
+	// FinalScore is the student's final mark in percent once the section has
+	// been graded.
+	FinalScore *float64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// AssessmentComponent is a weighted part of a section's final mark, such as
+// "Midterm, 30%". The weights of a section's components add up to 100.
+type AssessmentComponent struct {
+	ID        uint           `gorm:"primary_key"`
+	SectionID uint           `gorm:"not null;uniqueIndex:idx_component_section_name"`
+	Section   *CourseSection `gorm:"constraint:OnDelete:CASCADE;"`
+	Name      string         `gorm:"size:100;not null;uniqueIndex:idx_component_section_name"`
+	// Weight is the share of the final mark in percent.
+	Weight float64 `gorm:"not null"`
+	// MaxPoints is the score that counts as full marks.
+	MaxPoints float64 `gorm:"not null;default:100"`
+	Position  int     `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// AssessmentScore is a student's points in one component.
+type AssessmentScore struct {
+	ID           uint                 `gorm:"primary_key"`
+	ComponentID  uint                 `gorm:"not null;uniqueIndex:idx_score_component_enrollment"`
+	Component    *AssessmentComponent `gorm:"constraint:OnDelete:CASCADE;"`
+	EnrollmentID uint                 `gorm:"not null;uniqueIndex:idx_score_component_enrollment;index"`
+	Enrollment   *Enrollment          `gorm:"constraint:OnDelete:CASCADE;"`
+	Points       float64              `gorm:"not null"`
+	GradedByID   *uint
+	GradedBy     *User `gorm:"constraint:OnDelete:SET NULL;"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// GradeBand maps final marks of at least MinScore percent to a letter grade.
+// GradePoints is the value of the letter on a 4.0 scale.
+type GradeBand struct {
+	ID          uint    `gorm:"primary_key"`
+	Letter      string  `gorm:"size:5;not null;unique"`
+	MinScore    float64 `gorm:"not null;unique"`
+	GradePoints float64 `gorm:"not null"`
+}
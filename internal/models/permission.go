@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Permission names checked by middleware.RequirePermission.
+const (
+	PermUsersRead         = "users:read"
+	PermUsersWrite        = "users:write"
+	PermRolesManage       = "roles:manage"
+	PermSubjectsRead      = "subjects:read"
+	PermSubjectsWrite     = "subjects:write"
+	PermSubjectsTeach     = "subjects:teach"
+	PermSubjectsSelf      = "subjects:self"
+	PermTermsRead         = "terms:read"
+	PermTermsWrite        = "terms:write"
+	PermSectionsRead      = "sections:read"
+	PermSectionsWrite     = "sections:write"
+	PermEnrollSelf        = "enrollments:self"
+	PermGradesWrite       = "grades:write"
+	PermGradesSelf        = "grades:self"
+	PermGradeScale        = "grades:scale"
+	PermTranscriptsRead   = "transcripts:read"
+	PermTranscriptSelf    = "transcripts:self"
+	PermAttendanceManage  = "attendance:manage"
+	PermAttendanceSelf    = "attendance:self"
+	PermRoomsManage       = "rooms:manage"
+	PermTimetableSelf     = "timetable:self"
+	PermTimetableTeach    = "timetable:teach"
+	PermAssignmentsManage = "assignments:manage"
+	PermAssignmentsSelf   = "assignments:self"
+	PermQuizzesManage     = "quizzes:manage"
+	PermQuizzesSelf       = "quizzes:self"
+)
+
+type Permission struct {
+	ID          uint   `gorm:"primary_key"`
+	Name        string `gorm:"size:100;unique;not null"`
+	Description string `gorm:"size:255"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// RolePermission is the join table between roles and permissions.
+type RolePermission struct {
+	RoleID       uint `gorm:"primaryKey"`
+	PermissionID uint `gorm:"primaryKey"`
+	CreatedAt    time.Time
+}
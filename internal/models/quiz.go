@@ -0,0 +1,214 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Quiz question types.
+const (
+	QuestionSingleChoice = "single_choice"
+	QuestionMultiChoice  = "multi_choice"
+	QuestionTrueFalse    = "true_false"
+	QuestionNumeric      = "numeric"
+	QuestionShortAnswer  = "short_answer"
+)
+
+// Quiz is an online test for the students of one section, or of every
+// section of a subject in a term when SectionID is nil.
+//
+// Students can start attempts between OpensAt and ClosesAt, either of
+// which may be unset. An attempt ends TimeLimitMinutes after it starts, or
+// when the quiz closes if that is sooner; a zero limit leaves attempts
+// untimed. MaxAttempts of 0 allows any number of attempts.
+type Quiz struct {
+	ID               uint           `gorm:"primary_key"`
+	SubjectID        uint           `gorm:"not null;index"`
+	Subject          *Subject       `gorm:"constraint:OnDelete:RESTRICT;"`
+	TermID           uint           `gorm:"not null;index"`
+	Term             *Term          `gorm:"constraint:OnDelete:RESTRICT;"`
+	SectionID        *uint          `gorm:"index"`
+	Section          *CourseSection `gorm:"constraint:OnDelete:RESTRICT;"`
+	Title            string         `gorm:"size:200;not null"`
+	Description      string         `gorm:"type:text"`
+	OpensAt          *time.Time
+	ClosesAt         *time.Time
+	TimeLimitMinutes int  `gorm:"not null;default:0"`
+	MaxAttempts      int  `gorm:"not null;default:0"`
+	ShuffleQuestions bool `gorm:"not null;default:false"`
+	ShuffleOptions   bool `gorm:"not null;default:false"`
+	CreatedByID      *uint
+	CreatedBy        *User `gorm:"constraint:OnDelete:SET NULL;"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+
+	Questions []QuizQuestion `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE;"`
+}
+
+// MaxPoints is what a perfect attempt scores.
+func (q *Quiz) MaxPoints() float64 {
+	total := 0.0
+	for _, question := range q.Questions {
+		total += question.Points
+	}
+	return total
+}
+
+// Question returns the quiz's question with the given ID, or nil.
+func (q *Quiz) Question(id uint) *QuizQuestion {
+	for i := range q.Questions {
+		if q.Questions[i].ID == id {
+			return &q.Questions[i]
+		}
+	}
+	return nil
+}
+
+// Open reports whether students can start an attempt at t.
+func (q *Quiz) Open(t time.Time) bool {
+	return (q.OpensAt == nil || !t.Before(*q.OpensAt)) && (q.ClosesAt == nil || t.Before(*q.ClosesAt))
+}
+
+// QuizQuestion is one question of a quiz. Choice and true/false questions
+// are answered by picking Options; numeric questions are right within
+// Tolerance of Answer; short answers are right when they match one of
+// AcceptedAnswers, ignoring case and spacing, and are graded by the
+// teacher when there are none.
+type QuizQuestion struct {
+	ID        uint    `gorm:"primary_key"`
+	QuizID    uint    `gorm:"not null;index"`
+	Position  int     `gorm:"not null"`
+	Type      string  `gorm:"size:20;not null"`
+	Prompt    string  `gorm:"type:text;not null"`
+	Points    float64 `gorm:"not null"`
+	Answer    *float64
+	Tolerance float64 `gorm:"not null;default:0"`
+	// AcceptedAnswers holds one accepted short answer per line.
+	AcceptedAnswers string `gorm:"type:text;not null;default:''"`
+
+	Options []QuizOption `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE;"`
+}
+
+// Accepted returns the accepted short answers.
+func (q *QuizQuestion) Accepted() []string {
+	if q.AcceptedAnswers == "" {
+		return []string{}
+	}
+	return strings.Split(q.AcceptedAnswers, "\n")
+}
+
+// AutoGraded reports whether answers to the question are graded without
+// the teacher.
+func (q *QuizQuestion) AutoGraded() bool {
+	return q.Type != QuestionShortAnswer || q.AcceptedAnswers != ""
+}
+
+// Option returns the question's option with the given ID, or nil.
+func (q *QuizQuestion) Option(id uint) *QuizOption {
+	for i := range q.Options {
+		if q.Options[i].ID == id {
+			return &q.Options[i]
+		}
+	}
+	return nil
+}
+
+// QuizOption is a choice of a choice or true/false question.
+type QuizOption struct {
+	ID         uint   `gorm:"primary_key"`
+	QuestionID uint   `gorm:"not null;index"`
+	Position   int    `gorm:"not null"`
+	Text       string `gorm:"type:text;not null"`
+	Correct    bool   `gorm:"not null;default:false"`
+}
+
+// QuizAttempt is one go of a student at a quiz. Answers must arrive before
+// DeadlineAt, if set; an attempt still open after its deadline is submitted
+// with the answers saved in time. Score is set once the attempt is
+// submitted and every answer has been graded.
+type QuizAttempt struct {
+	ID        uint  `gorm:"primary_key"`
+	QuizID    uint  `gorm:"not null;uniqueIndex:idx_quiz_attempt_number"`
+	Quiz      *Quiz `gorm:"constraint:OnDelete:RESTRICT;"`
+	StudentID uint  `gorm:"not null;uniqueIndex:idx_quiz_attempt_number"`
+	Student   *User `gorm:"constraint:OnDelete:CASCADE;"`
+	// Number counts the student's attempts at the quiz from 1.
+	Number        int       `gorm:"not null;uniqueIndex:idx_quiz_attempt_number"`
+	StartedAt     time.Time `gorm:"not null"`
+	DeadlineAt    *time.Time
+	SubmittedAt   *time.Time
+	AutoSubmitted bool `gorm:"not null;default:false"`
+	Score         *float64
+	// QuestionOrder is the comma-separated question IDs in the order the
+	// student sees them.
+	QuestionOrder string `gorm:"type:text;not null"`
+
+	Answers []QuizAnswer `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE;"`
+}
+
+// Submitted reports whether the attempt is over.
+func (a *QuizAttempt) Submitted() bool {
+	return a.SubmittedAt != nil
+}
+
+// Expired reports whether the attempt's deadline has passed at t.
+func (a *QuizAttempt) Expired(t time.Time) bool {
+	return a.DeadlineAt != nil && t.After(*a.DeadlineAt)
+}
+
+// Answer returns the attempt's answer to the given question, or nil.
+func (a *QuizAttempt) Answer(questionID uint) *QuizAnswer {
+	for i := range a.Answers {
+		if a.Answers[i].QuestionID == questionID {
+			return &a.Answers[i]
+		}
+	}
+	return nil
+}
+
+// QuizAnswer is a student's answer to one question of an attempt. It is
+// created, unanswered, when the attempt starts. Points is set once the
+// answer is graded.
+type QuizAnswer struct {
+	ID         uint          `gorm:"primary_key"`
+	AttemptID  uint          `gorm:"not null;uniqueIndex:idx_quiz_answer_question"`
+	QuestionID uint          `gorm:"not null;uniqueIndex:idx_quiz_answer_question"`
+	Question   *QuizQuestion `gorm:"constraint:OnDelete:RESTRICT;"`
+	// OptionOrder is the comma-separated option IDs in the order the
+	// student sees them, and OptionIDs the comma-separated picked options.
+	OptionOrder string `gorm:"type:text;not null;default:''"`
+	OptionIDs   string `gorm:"type:text;not null;default:''"`
+	Number      *float64
+	Text        string `gorm:"type:text;not null;default:''"`
+	AnsweredAt  *time.Time
+	Points      *float64
+}
+
+// Options returns the IDs of the picked options.
+func (a *QuizAnswer) Options() []uint {
+	return SplitIDs(a.OptionIDs)
+}
+
+// JoinIDs and SplitIDs convert between IDs and their comma-separated form.
+func JoinIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func SplitIDs(s string) []uint {
+	if s == "" {
+		return []uint{}
+	}
+	parts := strings.Split(s, ",")
+	ids := make([]uint, 0, len(parts))
+	for _, p := range parts {
+		if id, err := strconv.ParseUint(p, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
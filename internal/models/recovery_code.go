@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RecoveryCode is a single-use backup code for two-factor login. Only the
+// SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primary_key"`
+	UserID    uint   `gorm:"index;not null"`
+	User      *User  `gorm:"constraint:OnDelete:CASCADE;"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RefreshToken is a server-side record of an opaque refresh token. Only the
+// SHA-256 hash of the token is stored. Tokens issued by rotating one another
+// share the same FamilyID. MFA records whether the family was started by a
+// login that included a second factor.
+type RefreshToken struct {
+	ID           uint   `gorm:"primary_key"`
+	UserID       uint   `gorm:"index;not null"`
+	User         *User  `gorm:"constraint:OnDelete:CASCADE;"`
+	FamilyID     string `gorm:"size:64;index;not null"`
+	TokenHash    string `gorm:"size:64;uniqueIndex;not null"`
+	MFA          bool   `gorm:"not null;default:false"`
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID *uint
+	CreatedAt    time.Time
+}
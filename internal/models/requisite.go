@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Requisite kinds. A prerequisite must be completed before a subject is
+// taken; a corequisite may also be taken in the same term.
+const (
+	RequisitePrerequisite = "prerequisite"
+	RequisiteCorequisite  = "corequisite"
+)
+
+// RequisiteGroup is one requirement of a subject. A subject requires all of
+// its groups, and a group is met by any one of its options, so groups are
+// ANDed and options within a group are ORed.
+type RequisiteGroup struct {
+	ID        uint     `gorm:"primary_key"`
+	SubjectID uint     `gorm:"not null;index"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE;"`
+	Kind      string   `gorm:"size:20;not null"`
+	CreatedAt time.Time
+
+	Options []RequisiteOption `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE;"`
+}
+
+// RequisiteOption names a subject that satisfies its group. MinScore is the
+// lowest final mark, in percent, that counts; nil means any passing mark.
+// A required subject cannot be deleted, as dropping the option could leave
+// a group that nobody can meet.
+type RequisiteOption struct {
+	ID                uint     `gorm:"primary_key"`
+	GroupID           uint     `gorm:"not null;index"`
+	RequiredSubjectID uint     `gorm:"not null;index"`
+	RequiredSubject   *Subject `gorm:"constraint:OnDelete:RESTRICT;"`
+	MinScore          *float64
+}
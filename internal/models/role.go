@@ -2,9 +2,33 @@ package models
 
 import "time"
 
+// Names of the built-in roles created by the seeder.
+const (
+	RoleAdmin   = "admin"
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+)
+
 type Role struct {
 	ID        uint   `gorm:"primary_key"`
 	Name      string `gorm:"size:50;unique;not null"`
+	System    bool   `gorm:"not null;default:false"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	Permissions []Permission `gorm:"many2many:role_permissions;constraint:OnDelete:CASCADE;"`
+}
+
+// HasPermission reports whether the role grants the named permission. The
+// role's Permissions must be preloaded.
+func (r *Role) HasPermission(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, p := range r.Permissions {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Building groups rooms on campus.
+type Building struct {
+	ID        uint   `gorm:"primary_key"`
+	Code      string `gorm:"size:20;not null;unique"`
+	Name      string `gorm:"size:100;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Rooms []Room `gorm:"constraint:OnDelete:RESTRICT;"`
+}
+
+// Room is a bookable classroom in a building.
+type Room struct {
+	ID         uint      `gorm:"primary_key"`
+	BuildingID uint      `gorm:"not null;uniqueIndex:idx_room_building_code"`
+	Building   *Building `gorm:"constraint:OnDelete:RESTRICT;"`
+	Code       string    `gorm:"size:20;not null;uniqueIndex:idx_room_building_code"`
+	Capacity   int       `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Label names the room as "<building>-<room>", such as "ENG-101".
+func (r *Room) Label() string {
+	if r.Building == nil {
+		return r.Code
+	}
+	return r.Building.Code + "-" + r.Code
+}
+
+// SectionMeeting is one weekly meeting of a section, repeated every week of
+// the section's term. Times are minutes after midnight and the range is
+// half-open. Online meetings have no room.
+type SectionMeeting struct {
+	ID          uint           `gorm:"primary_key"`
+	SectionID   uint           `gorm:"not null;index"`
+	Section     *CourseSection `gorm:"constraint:OnDelete:CASCADE;"`
+	Weekday     int            `gorm:"not null"`
+	StartMinute int            `gorm:"not null"`
+	EndMinute   int            `gorm:"not null"`
+	RoomID      *uint          `gorm:"index"`
+	Room        *Room          `gorm:"constraint:OnDelete:RESTRICT;"`
+}
+
+// Overlaps reports whether both meetings take place at the same time.
+func (m *SectionMeeting) Overlaps(o *SectionMeeting) bool {
+	return m.Weekday == o.Weekday && m.StartMinute < o.EndMinute && o.StartMinute < m.EndMinute
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Rubric is a teacher's reusable grading scheme: a list of criteria, each
+// with levels that describe a quality of work and the points it earns.
+type Rubric struct {
+	ID          uint   `gorm:"primary_key"`
+	OwnerID     uint   `gorm:"not null;index"`
+	Owner       *User  `gorm:"constraint:OnDelete:CASCADE;"`
+	Title       string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Criteria []RubricCriterion `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// MaxPoints is the total of the criteria's best levels.
+func (r *Rubric) MaxPoints() float64 {
+	var total float64
+	for i := range r.Criteria {
+		total += r.Criteria[i].MaxPoints()
+	}
+	return total
+}
+
+// Criterion returns the rubric's criterion with the ID, or nil.
+func (r *Rubric) Criterion(id uint) *RubricCriterion {
+	for i := range r.Criteria {
+		if r.Criteria[i].ID == id {
+			return &r.Criteria[i]
+		}
+	}
+	return nil
+}
+
+type RubricCriterion struct {
+	ID          uint   `gorm:"primary_key"`
+	RubricID    uint   `gorm:"not null;index"`
+	Position    int    `gorm:"not null;default:0"`
+	Title       string `gorm:"size:200;not null"`
+	Description string `gorm:"type:text"`
+
+	Levels []RubricLevel `gorm:"foreignKey:CriterionID;constraint:OnDelete:CASCADE;"`
+}
+
+// MaxPoints is the points of the criterion's best level.
+func (c *RubricCriterion) MaxPoints() float64 {
+	var best float64
+	for _, l := range c.Levels {
+		if l.Points > best {
+			best = l.Points
+		}
+	}
+	return best
+}
+
+// Level returns the criterion's level with the ID, or nil.
+func (c *RubricCriterion) Level(id uint) *RubricLevel {
+	for i := range c.Levels {
+		if c.Levels[i].ID == id {
+			return &c.Levels[i]
+		}
+	}
+	return nil
+}
+
+type RubricLevel struct {
+	ID          uint    `gorm:"primary_key"`
+	CriterionID uint    `gorm:"not null;index"`
+	Position    int     `gorm:"not null;default:0"`
+	Points      float64 `gorm:"not null"`
+	Descriptor  string  `gorm:"type:text;not null"`
+}
+
+// RubricScore is the points a submission earned in one rubric criterion.
+// Criteria and levels that have been scored cannot be deleted, so a rubric
+// stops being editable once it has been used for grading.
+type RubricScore struct {
+	ID           uint             `gorm:"primary_key"`
+	SubmissionID uint             `gorm:"not null;uniqueIndex:idx_rubric_score_submission_criterion"`
+	CriterionID  uint             `gorm:"not null;uniqueIndex:idx_rubric_score_submission_criterion;index"`
+	Criterion    *RubricCriterion `gorm:"constraint:OnDelete:RESTRICT;"`
+	// LevelID is the level the teacher picked; Points may differ from the
+	// level's when the teacher adjusted them.
+	LevelID *uint
+	Level   *RubricLevel `gorm:"constraint:OnDelete:RESTRICT;"`
+	Points  float64      `gorm:"not null"`
+	Comment string       `gorm:"type:text"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Similarity report statuses.
+const (
+	SimilarityQueued  = "queued"
+	SimilarityRunning = "running"
+	SimilarityDone    = "done"
+	SimilarityFailed  = "failed"
+)
+
+// Kinds of similarity matches.
+const (
+	SimilarityText = "text"
+	SimilarityCode = "code"
+)
+
+// SimilarityReport is the latest check of an assignment's submissions for
+// copied work. Running the check again replaces its matches.
+type SimilarityReport struct {
+	ID           uint        `gorm:"primary_key"`
+	AssignmentID uint        `gorm:"not null;uniqueIndex"`
+	Assignment   *Assignment `gorm:"constraint:OnDelete:CASCADE;"`
+	Status       string      `gorm:"size:20;not null"`
+	Error        string      `gorm:"size:500;not null;default:''"`
+	// Submissions is how many submissions were compared; SkippedFiles
+	// counts files that were neither text nor code.
+	Submissions  int `gorm:"not null;default:0"`
+	SkippedFiles int `gorm:"not null;default:0"`
+	RequestedAt  time.Time
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Matches []SimilarityMatch `gorm:"foreignKey:ReportID;constraint:OnDelete:CASCADE;"`
+}
+
+// SimilarityMatch is a pair of submissions whose text or code is similar
+// enough to be worth a look. Score is between 0 and 1.
+type SimilarityMatch struct {
+	ID            uint        `gorm:"primary_key"`
+	ReportID      uint        `gorm:"not null;index"`
+	Kind          string      `gorm:"size:10;not null"`
+	SubmissionAID uint        `gorm:"not null"`
+	SubmissionA   *Submission `gorm:"constraint:OnDelete:CASCADE;"`
+	SubmissionBID uint        `gorm:"not null"`
+	SubmissionB   *Submission `gorm:"constraint:OnDelete:CASCADE;"`
+	Score         float64     `gorm:"not null"`
+
+	Spans []SimilaritySpan `gorm:"foreignKey:MatchID;constraint:OnDelete:CASCADE;"`
+}
+
+// SimilaritySpan is a passage found in a file of each submission. Offsets
+// are bytes into the file's text, which for .docx files is the extracted
+// text rather than the file itself. Spans go away when the files are
+// replaced by a resubmission.
+type SimilaritySpan struct {
+	ID       uint            `gorm:"primary_key"`
+	MatchID  uint            `gorm:"not null;index"`
+	FileAID  uint            `gorm:"not null"`
+	FileA    *SubmissionFile `gorm:"constraint:OnDelete:CASCADE;"`
+	StartA   int             `gorm:"not null"`
+	EndA     int             `gorm:"not null"`
+	FileBID  uint            `gorm:"not null"`
+	FileB    *SubmissionFile `gorm:"constraint:OnDelete:CASCADE;"`
+	StartB   int             `gorm:"not null"`
+	EndB     int             `gorm:"not null"`
+	ExcerptA string          `gorm:"type:text;not null"`
+	ExcerptB string          `gorm:"type:text;not null"`
+}
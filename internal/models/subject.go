@@ -6,6 +6,9 @@ type Subject struct {
 	gorm.Model
 	Name        string `json:"name" gorm:"unique;not null"`
 	Description string `json:"description"`
+	// CreditHours weighs the subject in GPA calculations.
+	CreditHours float64 `json:"credit_hours" gorm:"not null;default:3"`
 
 	Teachers []User `json:"teachers" gorm:"many2many:subject_teachers;constraint:OnDelete:CASCADE;"`
+	Terms    []Term `json:"terms" gorm:"many2many:subject_terms;constraint:OnDelete:CASCADE;"`
 }
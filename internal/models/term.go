@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Term is an academic term such as "Fall 2026". Every range is half-open:
+// it includes its start and excludes its end.
+type Term struct {
+	ID        uint      `gorm:"primary_key"`
+	Name      string    `gorm:"size:100;unique;not null"`
+	StartDate time.Time `gorm:"not null;index"`
+	EndDate   time.Time `gorm:"not null;index"`
+
+	RegistrationStart time.Time `gorm:"not null"`
+	RegistrationEnd   time.Time `gorm:"not null"`
+	AddDropStart      time.Time `gorm:"not null"`
+	AddDropEnd        time.Time `gorm:"not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Contains reports whether t falls within the term.
+func (t *Term) Contains(at time.Time) bool {
+	return inRange(at, t.StartDate, t.EndDate)
+}
+
+// RegistrationOpen reports whether students may register at the given time.
+func (t *Term) RegistrationOpen(at time.Time) bool {
+	return inRange(at, t.RegistrationStart, t.RegistrationEnd)
+}
+
+// AddDropOpen reports whether students may add or drop courses at the given
+// time.
+func (t *Term) AddDropOpen(at time.Time) bool {
+	return inRange(at, t.AddDropStart, t.AddDropEnd)
+}
+
+func inRange(at, start, end time.Time) bool {
+	return !at.Before(start) && at.Before(end)
+}
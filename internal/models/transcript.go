@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Retake policies decide which attempt at a repeated subject counts towards
+// the cumulative GPA.
+const (
+	RetakeBest   = "best"
+	RetakeLatest = "latest"
+)
+
+// TranscriptRecord remembers an issued transcript so that its verification
+// hash can be checked later. Hash is the SHA-256 of Snapshot, the transcript
+// as JSON, so identical transcripts share one record. Snapshot is text rather
+// than jsonb, which would normalise it, so that the stored bytes still hash
+// to Hash.
+type TranscriptRecord struct {
+	ID        uint   `gorm:"primary_key"`
+	Hash      string `gorm:"size:64;not null;uniqueIndex"`
+	StudentID uint   `gorm:"not null;index"`
+	Student   *User  `gorm:"constraint:OnDelete:CASCADE;"`
+	Snapshot  string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+}
@@ -11,4 +11,17 @@ type User struct {
 	Role      *Role
 	CreatedAt time.Time `gorm:"DEFAULT:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time `gorm:"DEFAULT:CURRENT_TIMESTAMP"`
+
+	// EmailVerifiedAt is nil until the user follows the verification link.
+	EmailVerifiedAt *time.Time
+
+	// TOTPSecret holds the authenticator secret once enrollment has started;
+	// two-factor login is only enforced after TOTPEnabledAt is set.
+	TOTPSecret    string `gorm:"size:64"`
+	TOTPEnabledAt *time.Time
+
+	// TokenVersion is bumped to revoke every access token issued to the
+	// user so far. Only UserRepository.BumpTokenVersion writes it, so that
+	// saving a stale copy of the user cannot lower it.
+	TokenVersion int64 `gorm:"not null;default:0;<-:false"`
 }
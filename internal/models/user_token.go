@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken is a single-use, expiring token sent to a user out of band, such
+// as a password reset link. Only the SHA-256 hash of the token is stored.
+type UserToken struct {
+	ID        uint   `gorm:"primary_key"`
+	UserID    uint   `gorm:"index;not null"`
+	User      *User  `gorm:"constraint:OnDelete:CASCADE;"`
+	Purpose   string `gorm:"size:32;index;not null"`
+	TokenHash string `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -2,6 +2,7 @@ package seeder
 
 import (
 	"log"
+	"time"
 
 	"github.com/arman300s/uni-portal/internal/models"
 	"github.com/arman300s/uni-portal/pkg/auth" // for password hashing
@@ -12,7 +13,7 @@ func SeedAdmin(db *gorm.DB) {
 	SeedRoles(db)
 
 	var adminRole models.Role
-	if err := db.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
+	if err := db.Where("name = ?", models.RoleAdmin).First(&adminRole).Error; err != nil {
 		log.Printf("❌ Failed to find admin role: %v\n", err)
 		return
 	}
@@ -29,11 +30,13 @@ func SeedAdmin(db *gorm.DB) {
 		return
 	}
 
+	now := time.Now()
 	adminUser = models.User{
-		Name:     "Super Admin",
-		Email:    "admin@uni-portal.com",
-		Password: hash,
-		RoleID:   &adminRole.ID,
+		Name:            "Super Admin",
+		Email:           "admin@uni-portal.com",
+		Password:        hash,
+		RoleID:          &adminRole.ID,
+		EmailVerifiedAt: &now,
 	}
 
 	if err := db.Create(&adminUser).Error; err != nil {
@@ -0,0 +1,38 @@
+package seeder
+
+import (
+	"log"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+)
+
+// SeedGradeScale installs the default letter grade scale unless one has
+// already been configured.
+func SeedGradeScale(database *gorm.DB) {
+	var count int64
+	if err := database.Model(&models.GradeBand{}).Count(&count).Error; err != nil {
+		log.Printf("❌ Failed to check grade scale: %v\n", err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	bands := []models.GradeBand{
+		{Letter: "A", MinScore: 93, GradePoints: 4.0},
+		{Letter: "A-", MinScore: 90, GradePoints: 3.7},
+		{Letter: "B+", MinScore: 87, GradePoints: 3.3},
+		{Letter: "B", MinScore: 83, GradePoints: 3.0},
+		{Letter: "B-", MinScore: 80, GradePoints: 2.7},
+		{Letter: "C+", MinScore: 77, GradePoints: 2.3},
+		{Letter: "C", MinScore: 73, GradePoints: 2.0},
+		{Letter: "C-", MinScore: 70, GradePoints: 1.7},
+		{Letter: "D+", MinScore: 67, GradePoints: 1.3},
+		{Letter: "D", MinScore: 60, GradePoints: 1.0},
+		{Letter: "F", MinScore: 0, GradePoints: 0.0},
+	}
+	if err := database.Create(&bands).Error; err != nil {
+		log.Printf("❌ Failed to seed grade scale: %v\n", err)
+	}
+}
@@ -0,0 +1,89 @@
+package seeder
+
+import (
+	"log"
+
+	"github.com/arman300s/uni-portal/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type permissionSeed struct {
+	Name        string
+	Description string
+	Roles       []string
+}
+
+// defaultPermissions is the default permission matrix. The admin role is
+// granted every permission and is not listed here.
+var defaultPermissions = []permissionSeed{
+	{models.PermUsersRead, "View user accounts", nil},
+	{models.PermUsersWrite, "Create, update and delete user accounts", nil},
+	{models.PermRolesManage, "Manage roles and their permissions", nil},
+	{models.PermSubjectsRead, "Browse the subject catalog", []string{models.RoleTeacher, models.RoleStudent}},
+	{models.PermSubjectsWrite, "Create, update and delete subjects", nil},
+	{models.PermSubjectsTeach, "Teach subjects", []string{models.RoleTeacher}},
+	{models.PermSubjectsSelf, "Browse the subject catalog with own eligibility", []string{models.RoleStudent}},
+	{models.PermTermsRead, "View academic terms", []string{models.RoleTeacher, models.RoleStudent}},
+	{models.PermTermsWrite, "Create, update and delete academic terms", nil},
+	{models.PermSectionsRead, "View course sections", []string{models.RoleTeacher, models.RoleStudent}},
+	{models.PermSectionsWrite, "Open, update and delete course sections", nil},
+	{models.PermEnrollSelf, "Enroll in and drop course sections", []string{models.RoleStudent}},
+	{models.PermGradesWrite, "Manage assessments and scores of taught sections", []string{models.RoleTeacher}},
+	{models.PermGradesSelf, "View own grades", []string{models.RoleStudent}},
+	{models.PermGradeScale, "Configure the letter grade scale", nil},
+	{models.PermTranscriptsRead, "View and issue any user's transcript", nil},
+	{models.PermTranscriptSelf, "View and download own transcript", []string{models.RoleStudent}},
+	{models.PermAttendanceManage, "Run class sessions and record attendance of taught sections", []string{models.RoleTeacher}},
+	{models.PermAttendanceSelf, "Check in to class sessions and view own attendance", []string{models.RoleStudent}},
+	{models.PermRoomsManage, "Manage buildings and rooms", nil},
+	{models.PermTimetableSelf, "View own weekly class timetable", []string{models.RoleStudent}},
+	{models.PermTimetableTeach, "View own weekly teaching timetable", []string{models.RoleTeacher}},
+	{models.PermAssignmentsManage, "Hand out assignments and review submissions in taught sections", []string{models.RoleTeacher}},
+	{models.PermAssignmentsSelf, "View assignments and upload own submissions", []string{models.RoleStudent}},
+	{models.PermQuizzesManage, "Build quizzes and review attempts in taught sections", []string{models.RoleTeacher}},
+	{models.PermQuizzesSelf, "Take quizzes", []string{models.RoleStudent}},
+}
+
+// SeedPermissions creates missing permissions and grants each new permission
+// to its default roles. Grants of existing permissions are left untouched so
+// that changes made through the API survive restarts. The admin role always
+// holds every permission.
+func SeedPermissions(db *gorm.DB) {
+	roleIDs := map[string]uint{}
+	var roles []models.Role
+	if err := db.Find(&roles).Error; err != nil {
+		log.Printf("❌ Failed to load roles: %v\n", err)
+		return
+	}
+	for _, r := range roles {
+		roleIDs[r.Name] = r.ID
+	}
+
+	for _, seed := range defaultPermissions {
+		perm := models.Permission{Name: seed.Name}
+		res := db.Where(models.Permission{Name: seed.Name}).
+			Attrs(models.Permission{Description: seed.Description}).
+			FirstOrCreate(&perm)
+		if res.Error != nil {
+			log.Printf("❌ Failed to seed permission %s: %v\n", seed.Name, res.Error)
+			continue
+		}
+
+		grantTo := []string{models.RoleAdmin}
+		if res.RowsAffected > 0 {
+			grantTo = append(grantTo, seed.Roles...)
+		}
+
+		for _, name := range grantTo {
+			roleID, ok := roleIDs[name]
+			if !ok {
+				continue
+			}
+			grant := models.RolePermission{RoleID: roleID, PermissionID: perm.ID}
+			if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&grant).Error; err != nil {
+				log.Printf("❌ Failed to grant %s to %s: %v\n", seed.Name, name, err)
+			}
+		}
+	}
+}
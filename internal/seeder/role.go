@@ -7,12 +7,12 @@ import (
 
 func SeedRoles(db *gorm.DB) {
 	roles := []models.Role{
-		{Name: "admin"},
-		{Name: "teacher"},
-		{Name: "student"},
+		{Name: models.RoleAdmin},
+		{Name: models.RoleTeacher},
+		{Name: models.RoleStudent},
 	}
 
 	for _, role := range roles {
-		db.FirstOrCreate(&role, models.Role{Name: role.Name})
+		db.Where(models.Role{Name: role.Name}).Assign(models.Role{System: true}).FirstOrCreate(&role)
 	}
 }
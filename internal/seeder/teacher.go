@@ -2,6 +2,7 @@ package seeder
 
 import (
 	"log"
+	"time"
 
 	"github.com/arman300s/uni-portal/internal/models"
 	"golang.org/x/crypto/bcrypt"
@@ -10,7 +11,7 @@ import (
 
 func SeedTeachers(database *gorm.DB) {
 	var teacherRole models.Role
-	if err := database.First(&teacherRole, "name = ?", "teacher").Error; err != nil {
+	if err := database.First(&teacherRole, "name = ?", models.RoleTeacher).Error; err != nil {
 		log.Println("⚠️ Teacher role not found, skipping teacher seeding")
 		return
 	}
@@ -30,6 +31,8 @@ func SeedTeachers(database *gorm.DB) {
 		password, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
 		t.Password = string(password)
 		t.RoleID = &teacherRole.ID
+		now := time.Now()
+		t.EmailVerifiedAt = &now
 
 		if err := database.Create(&t).Error; err != nil {
 			log.Printf("❌ Failed to seed teacher %s: %v\n", t.Email, err)
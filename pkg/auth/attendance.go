@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PurposeAttendance marks a class-session check-in code. It is not accepted
+// as an access token.
+const PurposeAttendance = "attendance"
+
+// AttendanceClaims identify a class session and the rotation step the code
+// was issued for.
+type AttendanceClaims struct {
+	SessionID uint   `json:"sid"`
+	Step      int64  `json:"step"`
+	Purpose   string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// SignAttendanceCode signs a check-in code with the server key.
+func SignAttendanceCode(sessionID uint, step int64, expiresAt time.Time) (string, error) {
+	return sign(&AttendanceClaims{
+		SessionID: sessionID,
+		Step:      step,
+		Purpose:   PurposeAttendance,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+}
+
+// ParseAttendanceCode validates a code issued by SignAttendanceCode.
+func ParseAttendanceCode(code string) (*AttendanceClaims, error) {
+	token, err := jwt.ParseWithClaims(code, &AttendanceClaims{}, keyFunc,
+		jwt.WithValidMethods(validMethods()))
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*AttendanceClaims)
+	if !ok || !token.Valid || claims.Purpose != PurposeAttendance {
+		return nil, errors.New("invalid attendance code")
+	}
+	return claims, nil
+}
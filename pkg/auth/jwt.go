@@ -9,17 +9,27 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte(getSecret())
+// PurposeMFAPending marks a short-lived token that only proves the password
+// step of a two-step login. It is not accepted as an access token.
+const PurposeMFAPending = "mfa_pending"
+
+const mfaPendingExpiry = 5 * time.Minute
 
 func getSecret() string {
 	s := os.Getenv("JWT_SECRET")
 	if s == "" {
-		s = "dev_secret_change_me"
+		s = devSecret
 	}
 	return s
 }
 
 func getExpiry() time.Duration {
+	if m := os.Getenv("JWT_EXP_MINUTES"); m != "" {
+		if v, err := strconv.Atoi(m); err == nil {
+			return time.Minute * time.Duration(v)
+		}
+	}
+
 	h := os.Getenv("JWT_EXP_HOURS")
 	if h == "" {
 		return time.Hour * 72
@@ -33,30 +43,68 @@ func getExpiry() time.Duration {
 }
 
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID  uint   `json:"user_id"`
+	Version int64  `json:"ver"`
+	MFA     bool   `json:"mfa,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint) (string, error) {
-	exp := time.Now().Add(getExpiry())
-	claims := &Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(exp),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+// GenerateToken issues an access token for the user. version must be the
+// user's current token version so that RevokeUserTokens can invalidate it,
+// and mfa records whether the login was completed with a second factor.
+func GenerateToken(userID uint, version int64, mfa bool) (string, error) {
+	return signClaims(&Claims{UserID: userID, Version: version, MFA: mfa}, getExpiry())
+}
+
+// GenerateMFAPendingToken issues a token that can only be exchanged, together
+// with a second factor, for an access token.
+func GenerateMFAPendingToken(userID uint) (string, error) {
+	return signClaims(&Claims{UserID: userID, Purpose: PurposeMFAPending}, mfaPendingExpiry)
 }
 
+// ParseToken validates an access token.
 func ParseToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return jwtSecret, nil
-	})
+	claims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// ParseMFAPendingToken validates a token issued by GenerateMFAPendingToken.
+func ParseMFAPendingToken(tokenString string) (*Claims, error) {
+	claims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeMFAPending {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func signClaims(claims *Claims, ttl time.Duration) (string, error) {
+	jti, err := RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        jti,
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	return sign(claims)
+}
+
+func parseClaims(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc,
+		jwt.WithValidMethods(validMethods()))
 	if err != nil {
 		return nil, err
 	}
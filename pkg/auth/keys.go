@@ -0,0 +1,313 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const devSecret = "dev_secret_change_me"
+
+var ErrInsecureSecret = errors.New("JWT_SECRET must be set to a non-default value in production")
+
+// keySet holds the key used to sign new tokens and every key accepted when
+// verifying them. Asymmetric keys are identified by the kid header; tokens
+// without a kid are verified with the HMAC secret, if one is accepted.
+type keySet struct {
+	signing    *verificationKey
+	signer     crypto.Signer
+	verify     map[string]*verificationKey
+	hmacSecret []byte
+	// hmacUntil ends the migration window in which HMAC tokens are still
+	// accepted next to an asymmetric signing key.
+	hmacUntil time.Time
+}
+
+// acceptsHMAC reports whether tokens signed with the HMAC secret are valid
+// at t: always while the secret signs new tokens, and only until the end of
+// the migration window once an asymmetric key does.
+func (ks *keySet) acceptsHMAC(t time.Time) bool {
+	return ks.hmacSecret != nil && (ks.signing == nil || t.Before(ks.hmacUntil))
+}
+
+// validMethods lists the signing algorithms tokens may currently use.
+func validMethods() []string {
+	methods := []string{"RS256", "EdDSA"}
+	if getKeys().acceptsHMAC(time.Now()) {
+		methods = append(methods, "HS256")
+	}
+	return methods
+}
+
+type verificationKey struct {
+	kid    string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+var activeKeys atomic.Pointer[keySet]
+
+// Init loads signing and verification keys from the environment:
+//
+//	JWT_SIGNING_KEY_FILE   PEM private key (RSA or Ed25519) used to sign tokens
+//	JWT_SIGNING_KEY_ID     kid of the signing key, derived from the key if empty
+//	JWT_VERIFICATION_KEYS  extra public keys still accepted, as kid=path,kid=path
+//	JWT_SECRET             HMAC secret, used for signing when no key file is set
+//	JWT_HS256_ACCEPT_UNTIL RFC 3339 time until which tokens signed with
+//	                       JWT_SECRET are still accepted after switching to
+//	                       a key file
+//
+// With APP_ENV=production it refuses to run on the built-in dev secret.
+func Init() error {
+	ks, err := loadKeySet()
+	if err != nil {
+		return err
+	}
+	activeKeys.Store(ks)
+	return nil
+}
+
+func getKeys() *keySet {
+	if ks := activeKeys.Load(); ks != nil {
+		return ks
+	}
+	ks := &keySet{verify: map[string]*verificationKey{}, hmacSecret: []byte(getSecret())}
+	activeKeys.CompareAndSwap(nil, ks)
+	return activeKeys.Load()
+}
+
+func loadKeySet() (*keySet, error) {
+	ks := &keySet{verify: map[string]*verificationKey{}}
+
+	secret := os.Getenv("JWT_SECRET")
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		signer, err := readPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("signing key: %w", err)
+		}
+		vk, err := newVerificationKey(os.Getenv("JWT_SIGNING_KEY_ID"), signer.Public())
+		if err != nil {
+			return nil, fmt.Errorf("signing key: %w", err)
+		}
+		ks.signer = signer
+		ks.signing = vk
+		ks.verify[vk.kid] = vk
+	} else if secret == "" {
+		secret = devSecret
+	}
+
+	// After a switch to asymmetric keys, HMAC tokens are only accepted for
+	// an explicit migration window so that the switch does not log everyone
+	// out; a leaked secret must not stay usable forever.
+	if ks.signing == nil {
+		ks.hmacSecret = []byte(secret)
+	} else if until := os.Getenv("JWT_HS256_ACCEPT_UNTIL"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_HS256_ACCEPT_UNTIL %q, want an RFC 3339 time", until)
+		}
+		if secret == "" {
+			return nil, errors.New("JWT_HS256_ACCEPT_UNTIL needs JWT_SECRET")
+		}
+		ks.hmacSecret = []byte(secret)
+		ks.hmacUntil = t
+	}
+
+	for _, entry := range strings.Split(os.Getenv("JWT_VERIFICATION_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid JWT_VERIFICATION_KEYS entry %q, want kid=path", entry)
+		}
+		pub, err := readPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", kid, err)
+		}
+		vk, err := newVerificationKey(kid, pub)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", kid, err)
+		}
+		ks.verify[vk.kid] = vk
+	}
+
+	if os.Getenv("APP_ENV") == "production" && string(ks.hmacSecret) == devSecret {
+		return nil, ErrInsecureSecret
+	}
+	return ks, nil
+}
+
+func newVerificationKey(kid string, pub crypto.PublicKey) (*verificationKey, error) {
+	var method jwt.SigningMethod
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	if kid == "" {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		kid = base64.RawURLEncoding.EncodeToString(sum[:])[:16]
+	}
+	return &verificationKey{kid: kid, method: method, public: pub}, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(block)
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		signer, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	}
+}
+
+// JWK is a single public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every public verification key. The HMAC secret is never
+// published.
+func JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, vk := range getKeys().verify {
+		jwk := JWK{Kid: vk.kid, Use: "sig", Alg: vk.method.Alg()}
+		switch pub := vk.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// keyFunc resolves the verification key for a token from its kid header.
+func keyFunc(t *jwt.Token) (interface{}, error) {
+	ks := getKeys()
+
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok && ks.acceptsHMAC(time.Now()) {
+			return ks.hmacSecret, nil
+		}
+		return nil, errors.New("unexpected signing method")
+	}
+
+	vk, ok := ks.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if t.Method.Alg() != vk.method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return vk.public, nil
+}
+
+// sign signs claims with the active signing key, falling back to HMAC.
+func sign(claims jwt.Claims) (string, error) {
+	ks := getKeys()
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.hmacSecret)
+	}
+
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.kid
+	return token.SignedString(ks.signer)
+}
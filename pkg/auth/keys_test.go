@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// initKeys loads the key set from env, restoring the default afterwards.
+func initKeys(t *testing.T, env map[string]string) error {
+	t.Helper()
+	for _, name := range []string{"JWT_SIGNING_KEY_FILE", "JWT_SIGNING_KEY_ID", "JWT_VERIFICATION_KEYS", "JWT_SECRET", "JWT_HS256_ACCEPT_UNTIL", "APP_ENV"} {
+		t.Setenv(name, env[name])
+	}
+	t.Cleanup(func() { activeKeys.Store(nil) })
+	return Init()
+}
+
+func writeSigningKey(t *testing.T) string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func hmacToken(t *testing.T, secret string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestHMACTokensAfterKeySwitch(t *testing.T) {
+	const secret = "old-shared-secret"
+	keyFile := writeSigningKey(t)
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		accept bool
+	}{
+		{
+			name:   "hmac signing",
+			env:    map[string]string{"JWT_SECRET": secret},
+			accept: true,
+		},
+		{
+			name: "key file without migration window",
+			env:  map[string]string{"JWT_SIGNING_KEY_FILE": keyFile, "JWT_SECRET": secret},
+		},
+		{
+			name:   "key file within migration window",
+			env:    map[string]string{"JWT_SIGNING_KEY_FILE": keyFile, "JWT_SECRET": secret, "JWT_HS256_ACCEPT_UNTIL": time.Now().Add(time.Hour).Format(time.RFC3339)},
+			accept: true,
+		},
+		{
+			name: "key file after migration window",
+			env:  map[string]string{"JWT_SIGNING_KEY_FILE": keyFile, "JWT_SECRET": secret, "JWT_HS256_ACCEPT_UNTIL": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := initKeys(t, tt.env); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ParseToken(hmacToken(t, secret))
+			if accepted := err == nil; accepted != tt.accept {
+				t.Fatalf("HS256 token accepted = %v (err %v), want %v", accepted, err, tt.accept)
+			}
+
+			// Tokens issued with the current key always verify.
+			token, err := GenerateToken(1, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParseToken(token); err != nil {
+				t.Fatalf("ParseToken of a fresh token: %v", err)
+			}
+		})
+	}
+}
+
+func TestInitRejectsBadMigrationWindow(t *testing.T) {
+	keyFile := writeSigningKey(t)
+
+	if err := initKeys(t, map[string]string{"JWT_SIGNING_KEY_FILE": keyFile, "JWT_SECRET": "s", "JWT_HS256_ACCEPT_UNTIL": "next week"}); err == nil {
+		t.Fatal("Init accepted an unparseable JWT_HS256_ACCEPT_UNTIL")
+	}
+	if err := initKeys(t, map[string]string{"JWT_SIGNING_KEY_FILE": keyFile, "JWT_HS256_ACCEPT_UNTIL": time.Now().Add(time.Hour).Format(time.RFC3339)}); err == nil {
+		t.Fatal("Init accepted a migration window without JWT_SECRET")
+	}
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func HashPassword(plain string) (string, error) {
 	b, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
@@ -10,3 +14,16 @@ func HashPassword(plain string) (string, error) {
 func CheckPassword(hash, plain string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
 }
+
+// dummyHash is a hash of the cost HashPassword uses, made on first use.
+var dummyHash = sync.OnceValue(func() []byte {
+	b, _ := bcrypt.GenerateFromPassword([]byte("not a password of anyone"), bcrypt.DefaultCost)
+	return b
+})
+
+// CheckDummyPassword takes as long as CheckPassword on a real hash. Logins
+// for unknown emails call it, so that response times do not tell which
+// emails have an account.
+func CheckDummyPassword(plain string) {
+	_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(plain))
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RefreshExpiry returns how long a freshly issued refresh token stays valid.
+func RefreshExpiry() time.Duration {
+	h := os.Getenv("JWT_REFRESH_EXP_HOURS")
+	if h == "" {
+		return time.Hour * 24 * 30
+	}
+
+	v, err := strconv.Atoi(h)
+	if err != nil {
+		return time.Hour * 24 * 30
+	}
+	return time.Hour * time.Duration(v)
+}
+
+// RandomToken returns a URL-safe random string built from n random bytes.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateRefreshToken returns a new opaque refresh token.
+func GenerateRefreshToken() (string, error) {
+	return RandomToken(32)
+}
+
+// HashToken returns the hex-encoded SHA-256 of an opaque token, which is the
+// form stored in the database.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
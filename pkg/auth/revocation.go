@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+func revokedKey(jti string) string {
+	return fmt.Sprintf("auth:revoked:%s", jti)
+}
+
+func tokenVersionKey(userID uint) string {
+	return fmt.Sprintf("auth:token_version:%d", userID)
+}
+
+// RevokeToken adds a single token id to the revocation list until the token
+// would have expired anyway.
+func RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if cache.RDB == nil || jti == "" {
+		return nil
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return cache.RDB.Set(ctx, revokedKey(jti), 1, ttl).Err()
+}
+
+// VersionStore keeps users' token versions in the database. Redis only
+// caches them, so that a revocation outlives the eviction of its key.
+type VersionStore interface {
+	// TokenVersion returns ErrTokenRevoked for users that no longer exist.
+	TokenVersion(ctx context.Context, userID uint) (int64, error)
+	// BumpTokenVersion raises the user's version past both its stored value
+	// and atLeast, and returns the new version.
+	BumpTokenVersion(ctx context.Context, userID uint, atLeast int64) (int64, error)
+}
+
+var versionStore VersionStore
+
+// SetVersionStore makes the database the source of users' token versions.
+// Without one they only live in Redis.
+func SetVersionStore(store VersionStore) {
+	versionStore = store
+}
+
+// raiseVersionScript caches ARGV[1] as a token version unless a higher one
+// is cached already, so that racing fills and bumps never lower it.
+var raiseVersionScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '-1')
+if tonumber(ARGV[1]) > current then
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// cachedTokenVersion returns the user's token version as cached in Redis,
+// and false when it is not.
+func cachedTokenVersion(ctx context.Context, userID uint) (int64, bool, error) {
+	if cache.RDB == nil {
+		return 0, false, nil
+	}
+	v, err := cache.RDB.Get(ctx, tokenVersionKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	return v, err == nil, err
+}
+
+// TokenVersion returns the user's current token version. Tokens carrying an
+// older version are rejected.
+func TokenVersion(ctx context.Context, userID uint) (int64, error) {
+	v, ok, err := cachedTokenVersion(ctx, userID)
+	if err != nil || ok || versionStore == nil {
+		return v, err
+	}
+	if v, err = versionStore.TokenVersion(ctx, userID); err != nil || cache.RDB == nil {
+		return v, err
+	}
+	return v, raiseVersionScript.Run(ctx, cache.RDB, []string{tokenVersionKey(userID)}, v).Err()
+}
+
+// RevokeUserTokens invalidates every access token issued to the user so far
+// by bumping their token version.
+func RevokeUserTokens(ctx context.Context, userID uint) error {
+	cached, _, err := cachedTokenVersion(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if versionStore == nil {
+		if cache.RDB == nil {
+			return nil
+		}
+		return cache.RDB.Incr(ctx, tokenVersionKey(userID)).Err()
+	}
+
+	// Versions cached before the store existed may be ahead of it.
+	v, err := versionStore.BumpTokenVersion(ctx, userID, cached)
+	if err != nil || cache.RDB == nil {
+		return err
+	}
+	return raiseVersionScript.Run(ctx, cache.RDB, []string{tokenVersionKey(userID)}, v).Err()
+}
+
+// CheckRevocation reports ErrTokenRevoked if the token was logged out or
+// issued before the user's sessions were revoked. Other errors mean that
+// revocation could not be checked.
+func CheckRevocation(ctx context.Context, claims *Claims) error {
+	if cache.RDB != nil {
+		revoked, err := cache.RDB.Exists(ctx, revokedKey(claims.ID)).Result()
+		if err != nil {
+			return err
+		}
+		if revoked > 0 {
+			return ErrTokenRevoked
+		}
+	}
+
+	version, err := TokenVersion(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	if claims.Version < version {
+		return ErrTokenRevoked
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Tags group cached entries so that a write can invalidate every entry
+// derived from the data it touched.
+const (
+	TagUsers    = "users"
+	TagSubjects = "subjects"
+)
+
+// Cache stores opaque values with a TTL. Entries may be labelled with tags
+// when they are set; InvalidateTags drops every entry carrying any of the
+// given tags.
+//
+// Implementations treat their backing store being unavailable as a miss, so
+// callers can always fall back to the source of truth.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	Delete(ctx context.Context, keys ...string) error
+	InvalidateTags(ctx context.Context, tags ...string) error
+
+	// TagVersions snapshots the current versions of tags. An entry stored
+	// with SetVersions under the snapshot is never served once any of the
+	// tags has been invalidated after the snapshot was taken.
+	TagVersions(ctx context.Context, tags ...string) (Versions, error)
+	SetVersions(ctx context.Context, key string, value []byte, ttl time.Duration, versions Versions) error
+}
+
+// Versions is a snapshot of tag versions. The in-process LRU counts
+// invalidations locally and Redis keeps shared counters; the tiered cache
+// records both.
+type Versions struct {
+	tags   []string
+	local  []uint64
+	remote []string
+}
+
+// String identifies the snapshot, so that loads started under different
+// versions are not merged.
+func (v Versions) String() string {
+	var b strings.Builder
+	for i, tag := range v.tags {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(tag)
+		if v.local != nil {
+			b.WriteString(":" + strconv.FormatUint(v.local[i], 10))
+		}
+		if v.remote != nil {
+			b.WriteString("/" + v.remote[i])
+		}
+	}
+	return b.String()
+}
+
+// NewFromEnv builds the cache selected by CACHE_MODE: "redis" (default) uses
+// RDB, "memory" keeps an in-process LRU and "tiered" puts the LRU in front of
+// Redis. CACHE_LRU_SIZE bounds the LRU and CACHE_LOCAL_TTL_SECONDS caps how
+// long the tiered mode serves an entry from memory.
+func NewFromEnv() (Cache, error) {
+	size := envInt("CACHE_LRU_SIZE", 10000)
+	localTTL := time.Duration(envInt("CACHE_LOCAL_TTL_SECONDS", 30)) * time.Second
+
+	switch mode := os.Getenv("CACHE_MODE"); mode {
+	case "", "redis":
+		return NewRedis(RDB), nil
+	case "memory":
+		return NewLRU(size), nil
+	case "tiered":
+		return NewTiered(NewLRU(size), NewRedis(RDB), localTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_MODE %q", mode)
+	}
+}
+
+var loads singleflight.Group
+
+// Remember decodes the JSON value cached under key into dest. On a miss load
+// runs once per key and tag versions for all concurrent callers in this
+// process, and its result is stored for ttl under the given tags. The tag
+// versions are taken before load runs, so a result loaded while one of its
+// tags is invalidated is never served afterwards. A nil Cache disables
+// caching.
+func Remember(ctx context.Context, c Cache, key string, tags []string, ttl time.Duration, dest any, load func(ctx context.Context) (any, error)) error {
+	var (
+		versions Versions
+		store    bool
+	)
+	if c != nil {
+		if data, ok := c.Get(ctx, key); ok && json.Unmarshal(data, dest) == nil {
+			return nil
+		}
+		// Without a snapshot the result is not stored, as it could outlive
+		// an invalidation that lands during the load.
+		var err error
+		versions, err = c.TagVersions(ctx, tags...)
+		store = err == nil
+	}
+
+	data, err, _ := loads.Do(key+"@"+versions.String(), func() (any, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if store {
+			// A failed write only costs a later miss.
+			_ = c.SetVersions(ctx, key, data, ttl, versions)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(name)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
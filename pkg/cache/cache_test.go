@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRemember(t *testing.T) {
+	caches := map[string]func(t *testing.T) Cache{
+		"lru":    func(t *testing.T) Cache { return NewLRU(10) },
+		"redis":  func(t *testing.T) Cache { return NewRedis(newFakeRedis(t)) },
+		"tiered": func(t *testing.T) Cache { return NewTiered(NewLRU(10), NewRedis(newFakeRedis(t)), time.Minute) },
+		"none":   func(t *testing.T) Cache { return nil },
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCache(t)
+			tags := []string{TagUsers}
+
+			loads := 0
+			load := func(value string) func(context.Context) (any, error) {
+				return func(context.Context) (any, error) {
+					loads++
+					return value, nil
+				}
+			}
+			remember := func(load func(context.Context) (any, error)) string {
+				t.Helper()
+				var got string
+				if err := Remember(ctx, c, "key", tags, time.Minute, &got, load); err != nil {
+					t.Fatal(err)
+				}
+				return got
+			}
+
+			if got := remember(load("v1")); got != "v1" {
+				t.Fatalf("first Remember = %q, want v1", got)
+			}
+			want, wantLoads := "v1", 1
+			if c == nil {
+				want, wantLoads = "v2", 2
+			}
+			if got := remember(load("v2")); got != want || loads != wantLoads {
+				t.Fatalf("second Remember = %q after %d loads, want %q after %d", got, loads, want, wantLoads)
+			}
+			if c == nil {
+				return
+			}
+
+			if err := c.InvalidateTags(ctx, TagUsers); err != nil {
+				t.Fatal(err)
+			}
+			if got := remember(load("v3")); got != "v3" {
+				t.Fatalf("Remember after invalidation = %q, want v3", got)
+			}
+		})
+	}
+}
+
+// TestRememberInvalidateDuringLoad checks that a result loaded while its
+// tag is invalidated is not served afterwards: the write that triggered the
+// invalidation may not be part of it.
+func TestRememberInvalidateDuringLoad(t *testing.T) {
+	caches := map[string]func(t *testing.T) Cache{
+		"lru":    func(t *testing.T) Cache { return NewLRU(10) },
+		"redis":  func(t *testing.T) Cache { return NewRedis(newFakeRedis(t)) },
+		"tiered": func(t *testing.T) Cache { return NewTiered(NewLRU(10), NewRedis(newFakeRedis(t)), time.Minute) },
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCache(t)
+			tags := []string{TagUsers, TagSubjects}
+
+			var got string
+			err := Remember(ctx, c, "key", tags, time.Minute, &got, func(ctx context.Context) (any, error) {
+				if err := c.InvalidateTags(ctx, TagSubjects); err != nil {
+					return nil, err
+				}
+				return "stale", nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "stale" {
+				t.Fatalf("Remember = %q, want the loaded value", got)
+			}
+
+			err = Remember(ctx, c, "key", tags, time.Minute, &got, func(context.Context) (any, error) {
+				return "fresh", nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "fresh" {
+				t.Fatalf("Remember = %q, want fresh: a value loaded during an invalidation was served", got)
+			}
+		})
+	}
+}
+
+func TestTieredServesLocalCopy(t *testing.T) {
+	ctx := context.Background()
+	remote := NewRedis(newFakeRedis(t))
+	local := NewLRU(10)
+	c := NewTiered(local, remote, time.Minute)
+
+	if err := remote.Set(ctx, "key", []byte("v"), time.Minute, TagUsers); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.Get(ctx, "key"); !ok || string(got) != "v" {
+		t.Fatalf("Get = %q, %v", got, ok)
+	}
+	if _, ok := local.Get(ctx, "key"); !ok {
+		t.Fatal("remote hit was not copied into the local tier")
+	}
+
+	if err := c.InvalidateTags(ctx, TagUsers); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(ctx, "key"); ok {
+		t.Fatal("entry served after its tag was invalidated")
+	}
+}
+
+func TestTieredPropagatesInvalidations(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeRedis(t)
+	a := NewTiered(NewLRU(10), NewRedis(client), time.Minute)
+	b := NewTiered(NewLRU(10), NewRedis(client), time.Minute)
+
+	if err := b.Set(ctx, "key", []byte("v"), time.Minute, TagUsers); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for both listeners to subscribe before publishing.
+	waitFor(t, func() bool {
+		n, _ := client.PubSubNumSub(ctx, invalidationChannel).Result()
+		return n[invalidationChannel] >= 2
+	})
+	if err := a.InvalidateTags(ctx, TagUsers); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		_, ok := b.local.Get(ctx, "key")
+		return !ok
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,13 @@
+package cache
+
+import "fmt"
+
+// RolePermissionsKey is where the permission names of a role are cached.
+func RolePermissionsKey(roleID uint) string {
+	return fmt.Sprintf("role:%d:permissions", roleID)
+}
+
+// RoleTag labels entries derived from a role's permissions.
+func RoleTag(roleID uint) string {
+	return fmt.Sprintf("role:%d", roleID)
+}
@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process Cache that holds at most a fixed number of entries
+// and evicts the least recently used one when full. Invalidating a tag
+// drops its entries right away and bumps its version, so that results
+// loaded under the old version are not stored.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	tagged   map[string]map[string]struct{}
+	versions map[string]uint64
+	// invalidations counts deletes and tag invalidations.
+	invalidations uint64
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+func NewLRU(capacity int) *LRU {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		tagged:   make(map[string]map[string]struct{}),
+		versions: make(map[string]uint64),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, ttl, tags)
+	return nil
+}
+
+func (c *LRU) TagVersions(_ context.Context, tags ...string) (Versions, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Versions{tags: tags, local: c.tagVersions(tags)}, nil
+}
+
+// SetVersions stores value unless one of the tags has been invalidated
+// since versions was taken.
+func (c *LRU) SetVersions(_ context.Context, key string, value []byte, ttl time.Duration, versions Versions) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.current(versions) {
+		return nil
+	}
+	c.set(key, value, ttl, versions.tags)
+	return nil
+}
+
+// generation returns a counter that moves on with every delete and tag
+// invalidation.
+func (c *LRU) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.invalidations
+}
+
+// setAt stores an entry unless something was deleted or invalidated since
+// generation was read.
+func (c *LRU) setAt(generation uint64, key string, value []byte, ttl time.Duration, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.invalidations == generation {
+		c.set(key, value, ttl, tags)
+	}
+}
+
+// current reports whether versions still matches the tag versions. c.mu
+// must be held.
+func (c *LRU) current(versions Versions) bool {
+	if len(versions.local) != len(versions.tags) {
+		return false
+	}
+	for i, tag := range versions.tags {
+		if c.versions[tag] != versions.local[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// tagVersions returns the versions of tags. c.mu must be held.
+func (c *LRU) tagVersions(tags []string) []uint64 {
+	versions := make([]uint64, len(tags))
+	for i, tag := range tags {
+		versions[i] = c.versions[tag]
+	}
+	return versions
+}
+
+// set stores an entry, evicting the least recently used ones beyond
+// capacity. c.mu must be held.
+func (c *LRU) set(key string, value []byte, ttl time.Duration, tags []string) {
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	e := &lruEntry{key: key, value: value, tags: tags}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for _, tag := range tags {
+		keys, ok := c.tagged[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tagged[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+		}
+	}
+	c.invalidations++
+	return nil
+}
+
+func (c *LRU) InvalidateTags(_ context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tagged[tag] {
+			if el, ok := c.entries[key]; ok {
+				c.remove(el)
+			}
+		}
+		delete(c.tagged, tag)
+		c.versions[tag]++
+	}
+	c.invalidations++
+	return nil
+}
+
+// remove drops el from the list, the key index and the tag index. c.mu must
+// be held.
+func (c *LRU) remove(el *list.Element) {
+	e := c.order.Remove(el).(*lruEntry)
+	delete(c.entries, e.key)
+	for _, tag := range e.tags {
+		if keys, ok := c.tagged[tag]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(c.tagged, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Fatal("least recently used entry was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(ctx, key); !ok {
+			t.Fatalf("entry %q was evicted", key)
+		}
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	c.Set(ctx, "short", []byte("1"), time.Millisecond)
+	c.Set(ctx, "forever", []byte("2"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get(ctx, "short"); ok {
+		t.Fatal("expired entry served")
+	}
+	if _, ok := c.Get(ctx, "forever"); !ok {
+		t.Fatal("entry without TTL expired")
+	}
+}
+
+func TestLRUInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	c.Set(ctx, "users", []byte("1"), 0, TagUsers)
+	c.Set(ctx, "both", []byte("2"), 0, TagUsers, TagSubjects)
+	c.Set(ctx, "subjects", []byte("3"), 0, TagSubjects)
+	c.Set(ctx, "plain", []byte("4"), 0)
+
+	c.InvalidateTags(ctx, TagUsers)
+
+	for key, want := range map[string]bool{"users": false, "both": false, "subjects": true, "plain": true} {
+		if _, ok := c.Get(ctx, key); ok != want {
+			t.Errorf("Get(%q) hit = %v, want %v", key, ok, want)
+		}
+	}
+
+	c.Delete(ctx, "plain")
+	if _, ok := c.Get(ctx, "plain"); ok {
+		t.Fatal("deleted entry served")
+	}
+}
+
+func TestLRUSetVersions(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+
+	versions, _ := c.TagVersions(ctx, TagUsers)
+	c.SetVersions(ctx, "fresh", []byte("1"), 0, versions)
+	if _, ok := c.Get(ctx, "fresh"); !ok {
+		t.Fatal("entry stored under a current snapshot was dropped")
+	}
+
+	c.InvalidateTags(ctx, TagSubjects)
+	c.SetVersions(ctx, "other", []byte("2"), 0, versions)
+	if _, ok := c.Get(ctx, "other"); !ok {
+		t.Fatal("invalidating an unrelated tag dropped the entry")
+	}
+
+	c.InvalidateTags(ctx, TagUsers)
+	c.SetVersions(ctx, "stale", []byte("3"), 0, versions)
+	if _, ok := c.Get(ctx, "stale"); ok {
+		t.Fatal("entry stored under an outdated snapshot was served")
+	}
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Cache backed by a Redis server.
+//
+// Tags are versioned counters. Every entry records the versions of its tags
+// at write time and is treated as a miss once any of them has moved on, so
+// invalidating a tag is a single INCR no matter how many entries carry it.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis wraps client. A nil client yields a cache that never hits.
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool) {
+	value, _, ok := c.get(ctx, key)
+	return value, ok
+}
+
+// get returns a live entry together with its tags.
+func (c *Redis) get(ctx context.Context, key string) ([]byte, []string, bool) {
+	if c.client == nil {
+		return nil, nil, false
+	}
+	raw, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, nil, false
+	}
+
+	tags, versions, value, ok := decodeEntry(raw)
+	if !ok {
+		return nil, nil, false
+	}
+	if len(tags) > 0 {
+		current, err := c.tagVersions(ctx, tags)
+		if err != nil {
+			return nil, nil, false
+		}
+		for i := range tags {
+			if current[i] != versions[i] {
+				return nil, nil, false
+			}
+		}
+	}
+	return value, tags, true
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if c.client == nil {
+		return nil
+	}
+	versions, err := c.tagVersions(ctx, tags)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, key, encodeEntry(tags, versions, value), ttl).Err()
+}
+
+func (c *Redis) TagVersions(ctx context.Context, tags ...string) (Versions, error) {
+	if c.client == nil {
+		return Versions{tags: tags}, nil
+	}
+	versions, err := c.tagVersions(ctx, tags)
+	if err != nil {
+		return Versions{}, err
+	}
+	return Versions{tags: tags, remote: versions}, nil
+}
+
+// SetVersions stores value stamped with the given tag versions, so that it
+// reads as a miss if a tag was invalidated since they were taken.
+func (c *Redis) SetVersions(ctx context.Context, key string, value []byte, ttl time.Duration, versions Versions) error {
+	if c.client == nil || len(versions.remote) != len(versions.tags) {
+		return nil
+	}
+	return c.client.Set(ctx, key, encodeEntry(versions.tags, versions.remote, value), ttl).Err()
+}
+
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if c.client == nil || len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+func (c *Redis) InvalidateTags(ctx context.Context, tags ...string) error {
+	if c.client == nil || len(tags) == 0 {
+		return nil
+	}
+	pipe := c.client.Pipeline()
+	for _, tag := range tags {
+		pipe.Incr(ctx, tagVersionKey(tag))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *Redis) tagVersions(ctx context.Context, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tagVersionKey(tag)
+	}
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			versions[i] = s
+		} else {
+			versions[i] = "0"
+		}
+	}
+	return versions, nil
+}
+
+func tagVersionKey(tag string) string {
+	return "cache:tag:" + tag + ":version"
+}
+
+// encodeEntry prefixes value with a "tag=version;..." header line.
+func encodeEntry(tags, versions []string, value []byte) []byte {
+	var b bytes.Buffer
+	for i, tag := range tags {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(tag)
+		b.WriteByte('=')
+		b.WriteString(versions[i])
+	}
+	b.WriteByte('\n')
+	b.Write(value)
+	return b.Bytes()
+}
+
+func decodeEntry(raw []byte) (tags, versions []string, value []byte, ok bool) {
+	i := bytes.IndexByte(raw, '\n')
+	if i < 0 {
+		return nil, nil, nil, false
+	}
+	header, value := string(raw[:i]), raw[i+1:]
+	if header == "" {
+		return nil, nil, value, true
+	}
+
+	for _, part := range strings.Split(header, ";") {
+		tag, version, found := strings.Cut(part, "=")
+		if !found {
+			return nil, nil, nil, false
+		}
+		if _, err := strconv.ParseInt(version, 10, 64); err != nil {
+			return nil, nil, nil, false
+		}
+		tags = append(tags, tag)
+		versions = append(versions, version)
+	}
+	return tags, versions, value, true
+}
@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis speaks just enough RESP2 for the cache: GET, SET, DEL, MGET,
+// INCR, PUBLISH, PUBSUB NUMSUB and SUBSCRIBE. Expiry is ignored.
+type fakeRedis struct {
+	mu          sync.Mutex
+	data        map[string]string
+	subscribers map[string][]net.Conn
+}
+
+func newFakeRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedis{data: map[string]string{}, subscribers: map[string][]net.Conn{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: ln.Addr().String(), Protocol: 2, DisableIdentity: true})
+	t.Cleanup(func() {
+		client.Close()
+		ln.Close()
+	})
+	return client
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		reply := s.exec(conn, args)
+		s.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedis) exec(conn net.Conn, args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		if v, ok := s.data[args[1]]; ok {
+			return bulk(v)
+		}
+		return "$-1\r\n"
+	case "SET":
+		s.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				delete(s.data, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "MGET":
+		reply := fmt.Sprintf("*%d\r\n", len(args)-1)
+		for _, key := range args[1:] {
+			if v, ok := s.data[key]; ok {
+				reply += bulk(v)
+			} else {
+				reply += "$-1\r\n"
+			}
+		}
+		return reply
+	case "INCR":
+		n, _ := strconv.Atoi(s.data[args[1]])
+		n++
+		s.data[args[1]] = strconv.Itoa(n)
+		return fmt.Sprintf(":%d\r\n", n)
+	case "PUBLISH":
+		subs := s.subscribers[args[1]]
+		for _, sub := range subs {
+			io.WriteString(sub, "*3\r\n"+bulk("message")+bulk(args[1])+bulk(args[2]))
+		}
+		return fmt.Sprintf(":%d\r\n", len(subs))
+	case "PUBSUB":
+		reply := fmt.Sprintf("*%d\r\n", 2*(len(args)-2))
+		for _, channel := range args[2:] {
+			reply += bulk(channel) + fmt.Sprintf(":%d\r\n", len(s.subscribers[channel]))
+		}
+		return reply
+	case "SUBSCRIBE":
+		reply := ""
+		for i, channel := range args[1:] {
+			s.subscribers[channel] = append(s.subscribers[channel], conn)
+			reply += "*3\r\n" + bulk("subscribe") + bulk(channel) + fmt.Sprintf(":%d\r\n", i+1)
+		}
+		return reply
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bad command %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func TestRedisSetGet(t *testing.T) {
+	ctx := context.Background()
+	c := NewRedis(newFakeRedis(t))
+
+	if err := c.Set(ctx, "plain", []byte("v1"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "tagged", []byte("v2"), time.Minute, TagUsers, TagSubjects); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.Get(ctx, "plain"); !ok || string(got) != "v1" {
+		t.Fatalf("Get(plain) = %q, %v", got, ok)
+	}
+	if got, ok := c.Get(ctx, "tagged"); !ok || string(got) != "v2" {
+		t.Fatalf("Get(tagged) = %q, %v", got, ok)
+	}
+
+	if err := c.InvalidateTags(ctx, TagSubjects); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(ctx, "tagged"); ok {
+		t.Fatal("entry served after its tag was invalidated")
+	}
+	if _, ok := c.Get(ctx, "plain"); !ok {
+		t.Fatal("untagged entry dropped by tag invalidation")
+	}
+
+	if err := c.Delete(ctx, "plain"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(ctx, "plain"); ok {
+		t.Fatal("deleted entry served")
+	}
+}
+
+func TestRedisSetVersions(t *testing.T) {
+	ctx := context.Background()
+	c := NewRedis(newFakeRedis(t))
+
+	versions, err := c.TagVersions(ctx, TagUsers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InvalidateTags(ctx, TagUsers); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetVersions(ctx, "k", []byte("stale"), time.Minute, versions); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Fatal("entry stored under an outdated snapshot was served")
+	}
+}
+
+func TestRedisWithoutClientNeverHits(t *testing.T) {
+	ctx := context.Background()
+	c := NewRedis(nil)
+
+	if err := c.Set(ctx, "k", []byte("v"), time.Minute, TagUsers); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Fatal("cache without a client returned a hit")
+	}
+}
+
+func TestDecodeEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		tags     []string
+		versions []string
+		value    string
+		ok       bool
+	}{
+		{name: "untagged", raw: "\nvalue", value: "value", ok: true},
+		{name: "tagged", raw: "users=3;subjects=0\n{\"a\":1}", tags: []string{"users", "subjects"}, versions: []string{"3", "0"}, value: `{"a":1}`, ok: true},
+		{name: "no header", raw: "value"},
+		{name: "bad version", raw: "users=x\nvalue"},
+		{name: "missing version", raw: "users\nvalue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, versions, value, ok := decodeEntry([]byte(tt.raw))
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if fmt.Sprint(tags) != fmt.Sprint(tt.tags) || fmt.Sprint(versions) != fmt.Sprint(tt.versions) || string(value) != tt.value {
+				t.Fatalf("decodeEntry = %v, %v, %q", tags, versions, value)
+			}
+			if tt.tags != nil && string(encodeEntry(tags, versions, value)) != tt.raw {
+				t.Fatalf("encodeEntry does not round-trip %q", tt.raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+const invalidationChannel = "cache:invalidate"
+
+// Tiered serves hot entries from an in-process LRU and falls back to Redis.
+// Deletes and tag invalidations are published on a Redis channel so that the
+// local tier of every API instance drops them too; localTTL bounds how stale
+// an instance can get if it misses such a message.
+type Tiered struct {
+	local    *LRU
+	remote   *Redis
+	localTTL time.Duration
+}
+
+// NewTiered returns a two-tier cache and starts listening for invalidations
+// published by other instances.
+func NewTiered(local *LRU, remote *Redis, localTTL time.Duration) *Tiered {
+	c := &Tiered{local: local, remote: remote, localTTL: localTTL}
+	if remote.client != nil {
+		go c.listen(context.Background())
+	}
+	return c
+}
+
+func (c *Tiered) Get(ctx context.Context, key string) ([]byte, bool) {
+	if value, ok := c.local.Get(ctx, key); ok {
+		return value, true
+	}
+
+	// A delete or invalidation that arrives while Redis is read keeps the
+	// entry out of the local tier.
+	generation := c.local.generation()
+	value, tags, ok := c.remote.get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	c.local.setAt(generation, key, value, c.localTTL, tags)
+	return value, true
+}
+
+func (c *Tiered) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	localTTL := c.localTTL
+	if ttl > 0 && ttl < localTTL {
+		localTTL = ttl
+	}
+	c.local.Set(ctx, key, value, localTTL, tags...)
+	return c.remote.Set(ctx, key, value, ttl, tags...)
+}
+
+func (c *Tiered) TagVersions(ctx context.Context, tags ...string) (Versions, error) {
+	local, _ := c.local.TagVersions(ctx, tags...)
+	remote, err := c.remote.TagVersions(ctx, tags...)
+	if err != nil {
+		return Versions{}, err
+	}
+	return Versions{tags: tags, local: local.local, remote: remote.remote}, nil
+}
+
+func (c *Tiered) SetVersions(ctx context.Context, key string, value []byte, ttl time.Duration, versions Versions) error {
+	localTTL := c.localTTL
+	if ttl > 0 && ttl < localTTL {
+		localTTL = ttl
+	}
+	c.local.SetVersions(ctx, key, value, localTTL, versions)
+	return c.remote.SetVersions(ctx, key, value, ttl, versions)
+}
+
+func (c *Tiered) Delete(ctx context.Context, keys ...string) error {
+	c.local.Delete(ctx, keys...)
+	if err := c.remote.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		c.publish(ctx, "key:"+key)
+	}
+	return nil
+}
+
+func (c *Tiered) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.local.InvalidateTags(ctx, tags...)
+	if err := c.remote.InvalidateTags(ctx, tags...); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		c.publish(ctx, "tag:"+tag)
+	}
+	return nil
+}
+
+func (c *Tiered) publish(ctx context.Context, msg string) {
+	if c.remote.client == nil {
+		return
+	}
+	if err := c.remote.client.Publish(ctx, invalidationChannel, msg).Err(); err != nil {
+		log.Printf("cache: failed to publish invalidation: %v", err)
+	}
+}
+
+func (c *Tiered) listen(ctx context.Context) {
+	sub := c.remote.client.Subscribe(ctx, invalidationChannel)
+	defer sub.Close()
+
+	for msg := range sub.Channel() {
+		switch {
+		case strings.HasPrefix(msg.Payload, "tag:"):
+			c.local.InvalidateTags(ctx, strings.TrimPrefix(msg.Payload, "tag:"))
+		case strings.HasPrefix(msg.Payload, "key:"):
+			c.local.Delete(ctx, strings.TrimPrefix(msg.Payload, "key:"))
+		}
+	}
+}
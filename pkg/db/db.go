@@ -33,6 +33,9 @@ func Connect() {
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Surface constraint violations as gorm.ErrDuplicatedKey and
+		// gorm.ErrForeignKeyViolated instead of driver-specific errors.
+		TranslateError: true,
 	})
 	if err != nil {
 		log.Fatalf("Failed to connect db: %v", err)
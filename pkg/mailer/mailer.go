@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrInvalidHeader is returned for a recipient or subject that would break
+// out of its header line.
+var ErrInvalidHeader = errors.New("mail header contains a line break")
+
+// Message is a plain-text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages. A nil error means the message was handed over to
+// the transport; on an error it was not, and the caller may retry.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewFromEnv builds the mailer selected by MAIL_DRIVER: "smtp" relays through
+// the server configured by the SMTP_* variables (see NewSMTP) and "log"
+// prints whole messages, links included, to the process log for local
+// development. There is no default, as verification and reset emails carry
+// credentials that must neither be dropped nor logged by accident; the log
+// driver is also refused with APP_ENV=production.
+func NewFromEnv() (Mailer, error) {
+	switch driver := os.Getenv("MAIL_DRIVER"); driver {
+	case "smtp":
+		return NewSMTP(SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		})
+	case "log":
+		if os.Getenv("APP_ENV") == "production" {
+			return nil, errors.New("MAIL_DRIVER=log prints credentials and is not allowed in production")
+		}
+		return NewLog(log.Default()), nil
+	case "":
+		return nil, errors.New("MAIL_DRIVER is not set; use smtp, or log for local development")
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", driver)
+	}
+}
+
+// logMailer prints messages instead of sending them.
+type logMailer struct {
+	logger *log.Logger
+}
+
+// NewLog returns a Mailer that prints every message to logger.
+func NewLog(logger *log.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeaders(msg); err != nil {
+		return err
+	}
+	m.logger.Printf("📧 To: %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+func validHeaders(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig describes an SMTP relay. Port defaults to 587. Without a
+// Username no authentication is attempted; with one, PLAIN auth is used,
+// which net/smtp only allows over TLS or to localhost.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// From is the sender, with or without a display name, e.g.
+	// "Uni Portal <no-reply@example.edu>".
+	From string
+}
+
+type smtpMailer struct {
+	cfg  SMTPConfig
+	from *mail.Address
+	auth smtp.Auth
+}
+
+// NewSMTP returns a Mailer that relays through an SMTP server, upgrading the
+// connection with STARTTLS whenever the server offers it.
+func NewSMTP(cfg SMTPConfig) (Mailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("smtp: host is required")
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: invalid from address %q: %w", cfg.From, err)
+	}
+	m := &smtpMailer{cfg: cfg, from: from}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return m, nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeaders(msg); err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid recipient %q: %w", msg.To, err)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(m.cfg.Host, m.cfg.Port))
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Minute)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.format(to, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// format renders msg as a UTF-8 plain-text message with CRLF line endings.
+func (m *smtpMailer) format(to *mail.Address, msg Message) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", m.from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return b.Bytes()
+}
@@ -0,0 +1,149 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP accepts one message without STARTTLS or auth and returns what the
+// client sent.
+func fakeSMTP(t *testing.T) (host, port string, received <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var transcript strings.Builder
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250-fake")
+				reply("250 8BITMIME")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				transcript.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					transcript.WriteString(line)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				out <- transcript.String()
+				return
+			default:
+				reply("502 unknown command")
+			}
+		}
+	}()
+
+	host, port, _ = net.SplitHostPort(ln.Addr().String())
+	return host, port, out
+}
+
+func TestSMTPSend(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+	m, err := NewSMTP(SMTPConfig{Host: host, Port: port, From: "Uni Portal <no-reply@example.edu>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Send(context.Background(), Message{
+		To:      "Aigerim <aigerim@example.edu>",
+		Subject: "Подтвердите адрес",
+		Body:    "Open\nhttp://localhost/verify-email?token=abc\n.\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-received
+	for _, want := range []string{
+		"MAIL FROM:<no-reply@example.edu>",
+		"RCPT TO:<aigerim@example.edu>",
+		"From: \"Uni Portal\" <no-reply@example.edu>\r\n",
+		"To: \"Aigerim\" <aigerim@example.edu>\r\n",
+		"Subject: =?utf-8?q?",
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n",
+		"\r\n\r\nOpen\r\nhttp://localhost/verify-email?token=abc\r\n",
+		// A line holding a single dot is escaped so it does not end the
+		// message early.
+		"\r\n..\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSendRejectsHeaderInjection(t *testing.T) {
+	m, err := NewSMTP(SMTPConfig{Host: "127.0.0.1", Port: "1", From: "no-reply@example.edu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []Message{
+		{To: "a@example.edu\r\nBcc: b@example.edu", Subject: "Hi"},
+		{To: "a@example.edu", Subject: "Hi\r\nBcc: b@example.edu"},
+	} {
+		if err := m.Send(context.Background(), msg); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("Send(%q) = %v, want ErrInvalidHeader", msg, err)
+		}
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		appEnv  string
+		wantErr bool
+	}{
+		{name: "unset", wantErr: true},
+		{name: "unknown", driver: "sendgrid", wantErr: true},
+		{name: "log", driver: "log"},
+		{name: "log in production", driver: "log", appEnv: "production", wantErr: true},
+		{name: "smtp", driver: "smtp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAIL_DRIVER", tt.driver)
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("SMTP_HOST", "smtp.example.edu")
+			t.Setenv("MAIL_FROM", "no-reply@example.edu")
+			_, err := NewFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,14 +2,19 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"github.com/arman300s/uni-portal/pkg/auth"
+	"log"
 	"net/http"
 	"strings"
 )
 
 type ctxKey string
 
-const userIDKey ctxKey = "userID"
+const (
+	userIDKey ctxKey = "userID"
+	claimsKey ctxKey = "claims"
+)
 
 func UserIDFromContext(ctx context.Context) (uint, bool) {
 	v := ctx.Value(userIDKey)
@@ -20,6 +25,12 @@ func UserIDFromContext(ctx context.Context) (uint, bool) {
 	return id, ok
 }
 
+// ClaimsFromContext returns the parsed JWT claims stored by JWTAuth.
+func ClaimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*auth.Claims)
+	return claims, ok
+}
+
 func JWTAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		h := r.Header.Get("Authorization")
@@ -38,7 +49,19 @@ func JWTAuth(next http.Handler) http.Handler {
 			http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
 			return
 		}
+		if err := auth.CheckRevocation(r.Context(), claims); err != nil {
+			if errors.Is(err, auth.ErrTokenRevoked) {
+				http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
+				return
+			}
+			// Tokens are not let through unchecked, nor their users told
+			// to log in again, while Redis or the database is down.
+			log.Printf("auth: checking token revocation: %v", err)
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
 		ctx := context.WithValue(r.Context(), userIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, claimsKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
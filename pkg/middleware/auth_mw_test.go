@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/arman300s/uni-portal/pkg/auth"
+	"github.com/arman300s/uni-portal/pkg/cache"
+)
+
+// versionStore is an in-memory auth.VersionStore.
+type versionStore map[uint]int64
+
+func (s versionStore) TokenVersion(ctx context.Context, userID uint) (int64, error) {
+	v, ok := s[userID]
+	if !ok {
+		return 0, auth.ErrTokenRevoked
+	}
+	return v, nil
+}
+
+func (s versionStore) BumpTokenVersion(ctx context.Context, userID uint, atLeast int64) (int64, error) {
+	s[userID] = max(s[userID], atLeast) + 1
+	return s[userID], nil
+}
+
+func serveAuthenticated(t *testing.T, userID uint, version int64) *httptest.ResponseRecorder {
+	t.Helper()
+	token, err := auth.GenerateToken(userID, version, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/me", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	JWTAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+	return w
+}
+
+func TestJWTAuthChecksStoredTokenVersion(t *testing.T) {
+	store := versionStore{1: 0}
+	auth.SetVersionStore(store)
+	t.Cleanup(func() { auth.SetVersionStore(nil) })
+
+	if w := serveAuthenticated(t, 1, 0); w.Code != http.StatusOK {
+		t.Fatalf("current token: status %d", w.Code)
+	}
+	if err := auth.RevokeUserTokens(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if w := serveAuthenticated(t, 1, 0); w.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked token: status %d, want 401", w.Code)
+	}
+	if w := serveAuthenticated(t, 1, 1); w.Code != http.StatusOK {
+		t.Fatalf("token issued after revoking: status %d", w.Code)
+	}
+	if w := serveAuthenticated(t, 2, 0); w.Code != http.StatusUnauthorized {
+		t.Fatalf("token of a deleted user: status %d, want 401", w.Code)
+	}
+}
+
+func TestJWTAuthWithRedisDown(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	previous := cache.RDB
+	cache.RDB = rdb
+	t.Cleanup(func() { cache.RDB = previous })
+
+	w := serveAuthenticated(t, 1, 0)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503", w.Code)
+	}
+	if body := w.Body.String(); strings.Contains(body, "127.0.0.1") || strings.Contains(body, "invalid token") {
+		t.Fatalf("response leaks the failure: %q", body)
+	}
+}
@@ -3,11 +3,16 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/arman300s/uni-portal/internal/models"
+	"github.com/arman300s/uni-portal/pkg/auth"
+	"github.com/arman300s/uni-portal/pkg/cache"
 	"github.com/arman300s/uni-portal/pkg/db"
 )
 
+const rolePermissionsTTL = 10 * time.Minute
+
 type ctxUserKey string
 
 const userCtxKey ctxUserKey = "user"
@@ -40,6 +45,10 @@ func RequireRole(allowedRoles ...string) func(http.Handler) http.Handler {
 				return
 			}
 			user := userVal.(models.User)
+			if user.Role == nil {
+				http.Error(w, "forbidden: insufficient role", http.StatusForbidden)
+				return
+			}
 
 			for _, role := range allowedRoles {
 				if user.Role.Name == role {
@@ -52,3 +61,113 @@ func RequireRole(allowedRoles ...string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequirePermission only lets users through whose role grants every listed
+// permission. Permission sets are cached in c, which may be nil. It must run
+// after LoadUserMiddleware.
+func RequirePermission(c cache.Cache, perms ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userVal := r.Context().Value(userCtxKey)
+			if userVal == nil {
+				http.Error(w, "user not in context", http.StatusUnauthorized)
+				return
+			}
+			user := userVal.(models.User)
+
+			if user.RoleID == nil {
+				http.Error(w, "forbidden: insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			granted, err := rolePermissions(r.Context(), c, *user.RoleID)
+			if err != nil {
+				http.Error(w, "failed to load permissions", http.StatusInternalServerError)
+				return
+			}
+
+			for _, p := range perms {
+				if _, ok := granted[p]; !ok {
+					http.Error(w, "forbidden: insufficient permissions", http.StatusForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rolePermissions returns the permission names granted to a role, cached
+// under cache.RolePermissionsKey and tagged with cache.RoleTag.
+func rolePermissions(ctx context.Context, c cache.Cache, roleID uint) (map[string]struct{}, error) {
+	var names []string
+	err := cache.Remember(ctx, c, cache.RolePermissionsKey(roleID), []string{cache.RoleTag(roleID)}, rolePermissionsTTL, &names,
+		func(ctx context.Context) (any, error) {
+			var names []string
+			err := db.DB.WithContext(ctx).
+				Model(&models.Permission{}).
+				Joins("JOIN role_permissions rp ON rp.permission_id = permissions.id").
+				Where("rp.role_id = ?", roleID).
+				Pluck("permissions.name", &names).Error
+			return names, err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		granted[n] = struct{}{}
+	}
+	return granted, nil
+}
+
+// RequireVerifiedEmail rejects users that have not verified their email
+// address yet. It must run after LoadUserMiddleware.
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userVal := r.Context().Value(userCtxKey)
+		if userVal == nil {
+			http.Error(w, "user not in context", http.StatusUnauthorized)
+			return
+		}
+		user := userVal.(models.User)
+
+		if user.EmailVerifiedAt == nil {
+			http.Error(w, "forbidden: email not verified", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireMFA rejects users whose role requires two-factor authentication
+// unless the current token was obtained with a second factor. It must run
+// after JWTAuth and LoadUserMiddleware.
+func RequireMFA(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userVal := r.Context().Value(userCtxKey)
+		if userVal == nil {
+			http.Error(w, "user not in context", http.StatusUnauthorized)
+			return
+		}
+		user := userVal.(models.User)
+
+		if user.Role == nil || !auth.MFARequiredForRole(user.Role.Name) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if claims, ok := ClaimsFromContext(r.Context()); ok && claims.MFA {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if user.TOTPEnabledAt == nil {
+			http.Error(w, "forbidden: two-factor enrollment required", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "forbidden: two-factor authentication required", http.StatusForbidden)
+	})
+}
@@ -0,0 +1,436 @@
+// Package pdf writes simple text documents as PDF 1.4 without external
+// dependencies. It supports left- and right-aligned text and straight lines,
+// which is enough for generated reports such as transcripts.
+//
+// Text is drawn with embedded TrueType fonts when the document is given
+// Fonts, and with the standard Helvetica fonts otherwise. The standard fonts
+// are not embedded and only cover WinAnsi (Windows-1252). Text a font cannot
+// show makes Bytes fail with ErrUnsupportedText rather than print a
+// substitute character.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font selects the regular or the bold font.
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+var fontNames = [...]string{Regular: "Helvetica", Bold: "Helvetica-Bold"}
+
+// ErrUnsupportedText is returned by Bytes when the document contains a
+// character its fonts cannot show.
+var ErrUnsupportedText = errors.New("text contains characters the font cannot show")
+
+// Document is a PDF under construction.
+type Document struct {
+	title string
+	fonts *Fonts
+	pages []*Page
+	// used maps the glyphs drawn with each embedded font to the characters
+	// they show.
+	used [2]map[uint16]rune
+	err  error
+}
+
+// Page is a single A4 page. Coordinates are in points from the bottom-left
+// corner.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// New starts a document drawn with fonts, or with the standard fonts when
+// fonts is nil.
+func New(title string, fonts *Fonts) *Document {
+	return &Document{
+		title: title,
+		fonts: fonts,
+		used:  [2]map[uint16]rune{{}, {}},
+	}
+}
+
+// AddPage appends a blank page and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td %s Tj ET\n",
+		font+1, num(size), num(x), num(y), p.doc.show(font, s))
+}
+
+// TextRight draws s so that it ends at x.
+func (p *Page) TextRight(x, y float64, font Font, size float64, s string) {
+	p.Text(x-p.doc.TextWidth(s, font, size), y, font, size, s)
+}
+
+// Line draws a straight line of the given width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+func (d *Document) embedded(font Font) *TrueType {
+	switch {
+	case d.fonts == nil:
+		return nil
+	case font == Bold:
+		return d.fonts.Bold
+	default:
+		return d.fonts.Regular
+	}
+}
+
+// show returns s as a string operand for font and records the glyphs it
+// uses. The first character the font cannot show is kept for Bytes to
+// report.
+func (d *Document) show(font Font, s string) string {
+	tt := d.embedded(font)
+	if tt == nil {
+		b, err := encode(s)
+		if err != nil && d.err == nil {
+			d.err = err
+		}
+		return "(" + escape(b) + ")"
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('<')
+	for _, r := range s {
+		r = blank(r)
+		g, ok := tt.glyph(r)
+		if !ok && d.err == nil {
+			d.err = fmt.Errorf("%w: %q", ErrUnsupportedText, r)
+		}
+		if ok {
+			d.used[font][g] = r
+		}
+		fmt.Fprintf(&sb, "%04X", g)
+	}
+	sb.WriteByte('>')
+	return sb.String()
+}
+
+// TextWidth returns the width of s in points.
+func (d *Document) TextWidth(s string, font Font, size float64) float64 {
+	if tt := d.embedded(font); tt != nil {
+		var units float64
+		for _, r := range s {
+			g, _ := tt.glyph(blank(r))
+			units += tt.width(g)
+		}
+		return units * size / 1000
+	}
+
+	widths := &helveticaWidths
+	if font == Bold {
+		widths = &helveticaBoldWidths
+	}
+	var units int
+	b, _ := encode(s)
+	for _, c := range b {
+		if c >= 32 && c < 127 {
+			units += widths[c-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Truncate shortens s with an ellipsis so that it fits in width points.
+func (d *Document) Truncate(s string, font Font, size, width float64) string {
+	if d.TextWidth(s, font, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if candidate := string(runes) + "..."; d.TextWidth(candidate, font, size) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Bytes serializes the document. It fails with ErrUnsupportedText when any
+// text could not be shown.
+func (d *Document) Bytes() ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are fixed; pages follow as (page, content) pairs, then the
+	// four objects that make up each embedded font.
+	const firstPage = 5
+	firstFontPart := firstPage + 2*len(d.pages)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, f := range []Font{Regular, Bold} {
+		if tt := d.embedded(f); tt != nil {
+			object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+				d.subsetName(f, tt), firstFontPart+4*int(f), firstFontPart+4*int(f)+3))
+		} else {
+			object(fontObject(f))
+		}
+	}
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+	for _, f := range []Font{Regular, Bold} {
+		if tt := d.embedded(f); tt != nil {
+			if err := d.embed(object, f, tt, firstFontPart+4*int(f)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	object(fmt.Sprintf("<< /Title %s /Producer (uni-portal) >>", textString(d.title)))
+	info := len(offsets)
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, info, xref)
+	return buf.Bytes(), nil
+}
+
+// glyphs returns the glyphs drawn with font in ascending order.
+func (d *Document) glyphs(font Font) []uint16 {
+	glyphs := make([]uint16, 0, len(d.used[font]))
+	for g := range d.used[font] {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
+
+// subsetName prefixes the font name with the six-letter tag PDF requires for
+// subset fonts, derived from the glyphs the subset contains.
+func (d *Document) subsetName(font Font, tt *TrueType) string {
+	h := fnv.New32a()
+	fmt.Fprint(h, tt.name, d.glyphs(font))
+	sum := h.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	return string(tag) + "+" + tt.name
+}
+
+// embed writes the descendant CIDFont, its descriptor, the subset font file
+// and the ToUnicode map of an embedded font, numbered from first.
+func (d *Document) embed(object func(string), font Font, tt *TrueType, first int) error {
+	name := d.subsetName(font, tt)
+	glyphs := d.glyphs(font)
+
+	widths := make([]string, len(glyphs))
+	for i, g := range glyphs {
+		widths[i] = fmt.Sprintf("%d [%s]", g, num(tt.width(g)))
+	}
+	object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %s /W [%s] /CIDToGIDMap /Identity >>",
+		name, first+1, num(tt.width(0)), strings.Join(widths, " ")))
+
+	flags := 32 // nonsymbolic
+	if tt.italic != 0 {
+		flags |= 64
+	}
+	object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%d %d %d %d] /ItalicAngle %s /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, flags, tt.scale(tt.bbox[0]), tt.scale(tt.bbox[1]), tt.scale(tt.bbox[2]), tt.scale(tt.bbox[3]),
+		num(tt.italic), tt.scale(tt.ascent), tt.scale(tt.descent), tt.scale(tt.capHeight), first+2))
+
+	file := tt.subset(d.used[font])
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(file); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	object(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+		compressed.Len(), len(file), compressed.String()))
+
+	cmap := toUnicode(glyphs, d.used[font])
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(cmap), cmap))
+	return nil
+}
+
+// toUnicode writes the CMap that lets readers copy and search text drawn
+// with glyph IDs.
+func toUnicode(glyphs []uint16, chars map[uint16]rune) string {
+	var sb strings.Builder
+	sb.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// A bfchar block holds at most 100 entries.
+	for start := 0; start < len(glyphs); start += 100 {
+		block := glyphs[start:min(start+100, len(glyphs))]
+		fmt.Fprintf(&sb, "%d beginbfchar\n", len(block))
+		for _, g := range block {
+			fmt.Fprintf(&sb, "<%04X> <", g)
+			for _, u := range utf16.Encode([]rune{chars[g]}) {
+				fmt.Fprintf(&sb, "%04X", u)
+			}
+			sb.WriteString(">\n")
+		}
+		sb.WriteString("endbfchar\n")
+	}
+	sb.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return sb.String()
+}
+
+// textString encodes s for the document information dictionary: as a
+// literal string when it is ASCII, otherwise as UTF-16 with a byte order
+// mark.
+func textString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return "(" + escape([]byte(s)) + ")"
+	}
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	sb.WriteByte('>')
+	return sb.String()
+}
+
+// blank turns the control characters escape replaces into spaces.
+func blank(r rune) rune {
+	switch r {
+	case '\n', '\r', '\t':
+		return ' '
+	}
+	return r
+}
+
+func fontObject(f Font) string {
+	return fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fontNames[f])
+}
+
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		return "0"
+	}
+	return s
+}
+
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '\\', '(', ')':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n', '\r', '\t':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// winAnsiExtras maps the characters that Windows-1252 places in 0x80-0x9F.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converts s to WinAnsi. Characters outside it become '?' and are
+// reported as ErrUnsupportedText.
+func encode(s string) ([]byte, error) {
+	var err error
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+				if err == nil {
+					err = fmt.Errorf("%w: %q", ErrUnsupportedText, r)
+				}
+			}
+		}
+	}
+	return out, err
+}
+
+// Advance widths of the printable ASCII characters (32-126) in 1/1000 em,
+// from the Adobe font metrics of the standard fonts.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
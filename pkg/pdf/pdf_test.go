@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+const dejaVuSans = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+func loadTestFont(t *testing.T) *TrueType {
+	t.Helper()
+	font, err := loadTrueType(dejaVuSans)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("DejaVu Sans is not installed")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return font
+}
+
+func render(fonts *Fonts, s string) ([]byte, error) {
+	doc := New("Transcript - "+s, fonts)
+	doc.AddPage().Text(50, 800, Regular, 10, s)
+	return doc.Bytes()
+}
+
+func TestStandardFonts(t *testing.T) {
+	tests := []struct {
+		text    string
+		wantErr bool
+	}{
+		{"Zoë Müller – 5 €", false},
+		{"Айгерим Қасымова", true},
+		{"Kate 漢", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			out, err := render(nil, tt.text)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedText) {
+					t.Fatalf("err %v, want ErrUnsupportedText", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Contains(out, []byte("/Helvetica")) {
+				t.Fatal("standard font missing")
+			}
+		})
+	}
+}
+
+func TestEmbeddedFont(t *testing.T) {
+	font := loadTestFont(t)
+	fonts := &Fonts{Regular: font, Bold: font}
+
+	if _, err := render(fonts, "Kate 漢"); !errors.Is(err, ErrUnsupportedText) {
+		t.Fatalf("character missing from the font: err %v, want ErrUnsupportedText", err)
+	}
+
+	out, err := render(fonts, "Айгерим Қасымова")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The ToUnicode map sends the glyph of А back to U+0410.
+	a, _ := font.glyph('А')
+	for _, want := range []string{"/Subtype /Type0", "/CIDFontType2", "/Identity-H", fmt.Sprintf("<%04X> <0410>", a)} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("PDF lacks %q", want)
+		}
+	}
+	if !bytes.Contains(out, []byte("/Title <FEFF")) {
+		t.Error("title is not encoded as UTF-16")
+	}
+
+	subset, err := ParseTrueType(fontFile(t, out))
+	if err != nil {
+		t.Fatalf("embedded subset does not parse: %v", err)
+	}
+	for _, r := range "АйгеримҚасымова" {
+		g, ok := subset.glyph(r)
+		if !ok || len(subset.glyphData(g)) == 0 {
+			t.Errorf("subset lacks the outline of %q", r)
+		}
+	}
+	if g, _ := subset.glyph('Z'); len(subset.glyphData(g)) != 0 {
+		t.Error("subset keeps the outline of an unused glyph")
+	}
+	if full, sub := len(font.tables["glyf"]), len(subset.tables["glyf"]); sub >= full/10 {
+		t.Errorf("subset glyf is %d of %d bytes", sub, full)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	doc := New("", nil)
+	if got := doc.TextWidth("Hi", Regular, 10); got != 7.22+2.22 {
+		t.Errorf("Helvetica width %v, want 9.44", got)
+	}
+	if got := doc.Truncate("Mathematical Analysis", Regular, 10, 60); got != "Mathemati..." {
+		t.Errorf("Truncate = %q", got)
+	}
+
+	font := loadTestFont(t)
+	doc = New("", &Fonts{Regular: font, Bold: font})
+	g, _ := font.glyph('Қ')
+	if got, want := doc.TextWidth("ҚҚ", Regular, 10), 2*font.width(g)*10/1000; got != want {
+		t.Errorf("embedded width %v, want %v", got, want)
+	}
+}
+
+var fontFileStream = regexp.MustCompile(`(?s)/Length (\d+) /Length1 \d+ /Filter /FlateDecode >>\nstream\n`)
+
+// fontFile extracts and inflates the first embedded font file.
+func fontFile(t *testing.T, pdf []byte) []byte {
+	t.Helper()
+	m := fontFileStream.FindSubmatchIndex(pdf)
+	if m == nil {
+		t.Fatal("PDF embeds no font file")
+	}
+	length, _ := strconv.Atoi(string(pdf[m[2]:m[3]]))
+	zr, err := zlib.NewReader(bytes.NewReader(pdf[m[1] : m[1]+length]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,468 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// TrueType is a parsed TrueType font. Documents embed the glyphs they use,
+// so that text in any script the font covers can be shown.
+type TrueType struct {
+	name       string
+	tables     map[string][]byte
+	unitsPerEm float64
+	bbox       [4]int16
+	ascent     int16
+	descent    int16
+	capHeight  int16
+	italic     float64
+	advances   []uint16
+	glyphs     map[rune]uint16
+	longLoca   bool
+}
+
+// Fonts are the TrueType fonts a Document draws its text with.
+type Fonts struct {
+	Regular *TrueType
+	Bold    *TrueType
+}
+
+// FontsFromEnv loads the TrueType font named by PDF_FONT_FILE and, for bold
+// text, PDF_BOLD_FONT_FILE, which defaults to the regular font. It returns
+// nil, selecting the standard fonts, when PDF_FONT_FILE is unset.
+func FontsFromEnv() (*Fonts, error) {
+	regularFile := os.Getenv("PDF_FONT_FILE")
+	if regularFile == "" {
+		return nil, nil
+	}
+	regular, err := loadTrueType(regularFile)
+	if err != nil {
+		return nil, err
+	}
+	fonts := &Fonts{Regular: regular, Bold: regular}
+	if boldFile := os.Getenv("PDF_BOLD_FONT_FILE"); boldFile != "" {
+		if fonts.Bold, err = loadTrueType(boldFile); err != nil {
+			return nil, err
+		}
+	}
+	return fonts, nil
+}
+
+func loadTrueType(path string) (*TrueType, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	font, err := ParseTrueType(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return font, nil
+}
+
+var errMalformedFont = errors.New("malformed TrueType font")
+
+// ParseTrueType reads a TrueType font file. Fonts with PostScript (CFF)
+// outlines and font collections are not supported.
+func ParseTrueType(data []byte) (*TrueType, error) {
+	if len(data) < 12 {
+		return nil, errMalformedFont
+	}
+	if v := binary.BigEndian.Uint32(data); v != 0x00010000 && v != 0x74727565 { // "true"
+		return nil, errors.New("not a TrueType font; fonts with PostScript outlines are not supported")
+	}
+
+	f := &TrueType{tables: map[string][]byte{}}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*numTables {
+		return nil, errMalformedFont
+	}
+	for i := 0; i < numTables; i++ {
+		rec := data[12+16*i:]
+		offset, length := binary.BigEndian.Uint32(rec[8:]), binary.BigEndian.Uint32(rec[12:])
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return nil, errMalformedFont
+		}
+		f.tables[string(rec[:4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf", "cmap"} {
+		if f.tables[tag] == nil {
+			return nil, fmt.Errorf("TrueType font lacks the %s table", tag)
+		}
+	}
+
+	head := f.tables["head"]
+	if len(head) < 54 {
+		return nil, errMalformedFont
+	}
+	f.unitsPerEm = float64(binary.BigEndian.Uint16(head[18:]))
+	if f.unitsPerEm == 0 {
+		return nil, errMalformedFont
+	}
+	for i := range f.bbox {
+		f.bbox[i] = int16(binary.BigEndian.Uint16(head[36+2*i:]))
+	}
+	f.longLoca = binary.BigEndian.Uint16(head[50:]) != 0
+
+	hhea := f.tables["hhea"]
+	maxp := f.tables["maxp"]
+	if len(hhea) < 36 || len(maxp) < 6 {
+		return nil, errMalformedFont
+	}
+	f.ascent = int16(binary.BigEndian.Uint16(hhea[4:]))
+	f.descent = int16(binary.BigEndian.Uint16(hhea[6:]))
+	f.capHeight = f.ascent
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+
+	hmtx := f.tables["hmtx"]
+	if numMetrics == 0 || numMetrics > numGlyphs || len(hmtx) < 4*numMetrics {
+		return nil, errMalformedFont
+	}
+	f.advances = make([]uint16, numGlyphs)
+	for g := range f.advances {
+		// Glyphs past the last metric repeat its advance.
+		f.advances[g] = binary.BigEndian.Uint16(hmtx[4*min(g, numMetrics-1):])
+	}
+
+	locaSize := 2
+	if f.longLoca {
+		locaSize = 4
+	}
+	if len(f.tables["loca"]) < locaSize*(numGlyphs+1) {
+		return nil, errMalformedFont
+	}
+
+	if os2 := f.tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		f.capHeight = int16(binary.BigEndian.Uint16(os2[88:]))
+	}
+	if post := f.tables["post"]; len(post) >= 8 {
+		f.italic = float64(int32(binary.BigEndian.Uint32(post[4:]))) / 65536
+	}
+	f.name = postScriptName(f.tables["name"])
+
+	var err error
+	if f.glyphs, err = parseCmap(f.tables["cmap"], numGlyphs); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// glyph returns the glyph that shows r, if the font has one.
+func (f *TrueType) glyph(r rune) (uint16, bool) {
+	g, ok := f.glyphs[r]
+	return g, ok
+}
+
+// width returns the advance of glyph g in 1/1000 em.
+func (f *TrueType) width(g uint16) float64 {
+	return float64(f.advances[g]) * 1000 / f.unitsPerEm
+}
+
+func (f *TrueType) scale(v int16) int {
+	return int(float64(v) * 1000 / f.unitsPerEm)
+}
+
+// parseCmap maps Unicode code points to glyphs, preferring the full-range
+// format 12 subtable over the BMP-only format 4 one.
+func parseCmap(cmap []byte, numGlyphs int) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errMalformedFont
+	}
+	var bmp, full []byte
+	n := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < n; i++ {
+		rec := cmap[4+8*i:]
+		if len(rec) < 8 {
+			return nil, errMalformedFont
+		}
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		offset := binary.BigEndian.Uint32(rec[4:])
+		if platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+		if uint64(offset)+4 > uint64(len(cmap)) {
+			return nil, errMalformedFont
+		}
+		sub := cmap[offset:]
+		switch binary.BigEndian.Uint16(sub) {
+		case 4:
+			bmp = sub
+		case 12:
+			full = sub
+		}
+	}
+
+	glyphs := map[rune]uint16{}
+	add := func(r rune, g uint32) {
+		if g != 0 && g < uint32(numGlyphs) {
+			glyphs[r] = uint16(g)
+		}
+	}
+	switch {
+	case full != nil:
+		if len(full) < 16 {
+			return nil, errMalformedFont
+		}
+		groups := int(binary.BigEndian.Uint32(full[12:]))
+		if len(full) < 16+12*groups {
+			return nil, errMalformedFont
+		}
+		for i := 0; i < groups; i++ {
+			group := full[16+12*i:]
+			start, end := binary.BigEndian.Uint32(group), binary.BigEndian.Uint32(group[4:])
+			first := binary.BigEndian.Uint32(group[8:])
+			if end > 0x10FFFF || start > end {
+				return nil, errMalformedFont
+			}
+			for c := start; c <= end; c++ {
+				add(rune(c), first+c-start)
+			}
+		}
+	case bmp != nil:
+		if len(bmp) < 14 {
+			return nil, errMalformedFont
+		}
+		segs := int(binary.BigEndian.Uint16(bmp[6:])) / 2
+		if len(bmp) < 16+8*segs {
+			return nil, errMalformedFont
+		}
+		u16 := func(off int) uint16 { return binary.BigEndian.Uint16(bmp[off:]) }
+		for i := 0; i < segs; i++ {
+			end, start := u16(14+2*i), u16(16+2*segs+2*i)
+			delta := u16(16 + 4*segs + 2*i)
+			rangeAt := 16 + 6*segs + 2*i
+			rangeOffset := int(u16(rangeAt))
+			for c := uint32(start); c <= uint32(end) && c != 0xFFFF; c++ {
+				if rangeOffset == 0 {
+					add(rune(c), uint32(uint16(c)+delta))
+					continue
+				}
+				at := rangeAt + rangeOffset + 2*int(c-uint32(start))
+				if at+2 > len(bmp) {
+					return nil, errMalformedFont
+				}
+				if g := u16(at); g != 0 {
+					add(rune(c), uint32(g+delta))
+				}
+			}
+		}
+	default:
+		return nil, errors.New("TrueType font has no Unicode character map")
+	}
+	return glyphs, nil
+}
+
+// postScriptName returns name ID 6 of the name table, which PDF uses as the
+// font's BaseFont, reduced to characters that are safe in a PDF name.
+func postScriptName(table []byte) string {
+	const fallback = "EmbeddedFont"
+	if len(table) < 6 {
+		return fallback
+	}
+	count := int(binary.BigEndian.Uint16(table[2:]))
+	storage := int(binary.BigEndian.Uint16(table[4:]))
+	for i := 0; i < count; i++ {
+		rec := table[6+12*i:]
+		if len(rec) < 12 || binary.BigEndian.Uint16(rec[6:]) != 6 {
+			continue
+		}
+		platform := binary.BigEndian.Uint16(rec)
+		length, offset := int(binary.BigEndian.Uint16(rec[8:])), int(binary.BigEndian.Uint16(rec[10:]))
+		if storage+offset+length > len(table) {
+			continue
+		}
+		raw := table[storage+offset : storage+offset+length]
+
+		var name string
+		if platform == 0 || platform == 3 {
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			name = string(utf16.Decode(units))
+		} else {
+			name = string(raw)
+		}
+		name = strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+				return r
+			}
+			return -1
+		}, name)
+		if name != "" {
+			return name
+		}
+	}
+	return fallback
+}
+
+// glyphData returns the outline of glyph g, which is empty for blank
+// glyphs.
+func (f *TrueType) glyphData(g uint16) []byte {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+	var start, end uint32
+	if f.longLoca {
+		start, end = binary.BigEndian.Uint32(loca[4*int(g):]), binary.BigEndian.Uint32(loca[4*int(g)+4:])
+	} else {
+		start, end = 2*uint32(binary.BigEndian.Uint16(loca[2*int(g):])), 2*uint32(binary.BigEndian.Uint16(loca[2*int(g)+2:]))
+	}
+	if start >= end || end > uint32(len(glyf)) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// subset returns a copy of the font file in which every glyph outside used
+// is empty. Glyph IDs are kept, so text can address glyphs directly. Only the
+// tables PDF readers need for an embedded TrueType font, and the character
+// map, are written.
+func (f *TrueType) subset(used map[uint16]rune) []byte {
+	numGlyphs := len(f.advances)
+	// Glyph 0 is the fallback glyph; composite glyphs need their components.
+	keep := map[int]bool{}
+	var visit func(g int)
+	visit = func(g int) {
+		if keep[g] || g >= numGlyphs {
+			return
+		}
+		keep[g] = true
+		for _, c := range components(f.glyphData(uint16(g))) {
+			visit(int(c))
+		}
+	}
+	visit(0)
+	for g := range used {
+		visit(int(g))
+	}
+
+	var newGlyf []byte
+	newLoca := make([]byte, 4*(numGlyphs+1))
+	for g := 0; g < numGlyphs; g++ {
+		binary.BigEndian.PutUint32(newLoca[4*g:], uint32(len(newGlyf)))
+		if keep[g] {
+			newGlyf = append(newGlyf, f.glyphData(uint16(g))...)
+			for len(newGlyf)%4 != 0 {
+				newGlyf = append(newGlyf, 0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[4*numGlyphs:], uint32(len(newGlyf)))
+
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0) // checkSumAdjustment, set below
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"head": head, "loca": newLoca, "glyf": newGlyf}
+	for _, tag := range []string{"cmap", "hhea", "maxp", "hmtx", "cvt ", "fpgm", "prep"} {
+		if t, ok := f.tables[tag]; ok {
+			tables[tag] = t
+		}
+	}
+	font := writeFontFile(tables)
+
+	headOffset := binary.BigEndian.Uint32(font[12+16*tableIndex(tables, "head")+8:])
+	binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-checksum(font))
+	return font
+}
+
+// components returns the glyphs a composite glyph is built from.
+func components(glyph []byte) []uint16 {
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+	const (
+		argsAreWords  = 0x0001
+		haveScale     = 0x0008
+		moreToCome    = 0x0020
+		haveXYScale   = 0x0040
+		haveTwoByTwo  = 0x0080
+		componentHead = 4
+	)
+	var glyphs []uint16
+	for at := 10; at+componentHead <= len(glyph); {
+		flags := binary.BigEndian.Uint16(glyph[at:])
+		glyphs = append(glyphs, binary.BigEndian.Uint16(glyph[at+2:]))
+		at += componentHead
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreToCome == 0 {
+			break
+		}
+	}
+	return glyphs
+}
+
+func tableIndex(tables map[string][]byte, tag string) int {
+	i := 0
+	for t := range tables {
+		if t < tag {
+			i++
+		}
+	}
+	return i
+}
+
+// writeFontFile lays tables out as a font file with a sorted table
+// directory, every table padded to four bytes.
+func writeFontFile(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	n := len(tags)
+	searchRange, selector := 1, 0
+	for searchRange*2 <= n {
+		searchRange *= 2
+		selector++
+	}
+	searchRange *= 16
+
+	out := make([]byte, 12+16*n)
+	binary.BigEndian.PutUint32(out, 0x00010000)
+	binary.BigEndian.PutUint16(out[4:], uint16(n))
+	binary.BigEndian.PutUint16(out[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(out[8:], uint16(selector))
+	binary.BigEndian.PutUint16(out[10:], uint16(16*n-searchRange))
+	for i, tag := range tags {
+		table := tables[tag]
+		rec := out[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], checksum(table))
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(out)))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(table)))
+		out = append(out, table...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+	return out
+}
+
+func checksum(b []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(b); i += 4 {
+		var word [4]byte
+		copy(word[:], b[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
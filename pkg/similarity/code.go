@@ -0,0 +1,173 @@
+package similarity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// kgramSize is how many consecutive tokens make a k-gram.
+	kgramSize = 8
+	// winnowWindow is how many consecutive k-grams one fingerprint is
+	// picked from. Any shared run of winnowWindow+kgramSize-1 tokens is
+	// guaranteed to share a fingerprint.
+	winnowWindow = 4
+)
+
+// keywords are kept as they are when normalizing code; every other
+// identifier becomes the same token. The list covers the common keywords
+// and built-in types of C-like languages, Python, Go, Java and JavaScript.
+var keywords = func() map[string]bool {
+	m := map[string]bool{}
+	for _, k := range strings.Fields(`
+		abstract and as assert async await bool boolean break byte case catch chan char class const
+		continue def default defer del delete do double elif else enum except extends false final
+		finally float for foreach from func function global go goto if implements import in instanceof
+		int interface is lambda let long map new nil none nonlocal not null or package pass private
+		protected public raise range return select self short signed sizeof static string struct super
+		switch template this throw throws true try type typedef union unsigned using var void volatile
+		while with yield`) {
+		m[k] = true
+	}
+	return m
+}()
+
+// codeToken is a normalized token and where it is in the source.
+type codeToken struct {
+	text string
+	span Span
+}
+
+// NewCode fingerprints source code. Comments, layout, identifier names and
+// literal values are ignored, so renaming variables or reformatting does
+// not change the fingerprints.
+func NewCode(src string) *Document {
+	tokens := tokenizeCode(src)
+	doc := &Document{kind: KindCode, fingerprints: map[uint64]struct{}{}}
+	if len(tokens) == 0 {
+		return doc
+	}
+
+	k := min(kgramSize, len(tokens))
+	texts := make([]string, k)
+	for i := 0; i+k <= len(tokens); i++ {
+		for j := range texts {
+			texts[j] = tokens[i+j].text
+		}
+		doc.grams = append(doc.grams, gram{
+			hash: hashTokens(texts),
+			span: Span{Start: tokens[i].span.Start, End: tokens[i+k-1].span.End},
+		})
+	}
+
+	// Winnowing keeps the smallest hash of every window of k-grams, the
+	// rightmost one on ties, which gives position-independent fingerprints
+	// with a guaranteed density.
+	w := min(winnowWindow, len(doc.grams))
+	for i := 0; i+w <= len(doc.grams); i++ {
+		best := i
+		for j := i + 1; j < i+w; j++ {
+			if doc.grams[j].hash <= doc.grams[best].hash {
+				best = j
+			}
+		}
+		doc.fingerprints[doc.grams[best].hash] = struct{}{}
+	}
+	return doc
+}
+
+// tokenizeCode splits source code into normalized tokens: keywords as they
+// are, "I" for identifiers, "N" for numbers, "S" for string literals and
+// one token for each punctuation character. Comments starting with //, #,
+// or /* are skipped.
+func tokenizeCode(src string) []codeToken {
+	var tokens []codeToken
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case strings.HasPrefix(src[i:], "//") || r == '#':
+			i = skipLine(src, i)
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+				i += 2 + end + 2
+			} else {
+				i = len(src)
+			}
+			continue
+		case strings.HasPrefix(src[i:], `"""`) || strings.HasPrefix(src[i:], "'''"):
+			if end := strings.Index(src[i+3:], src[i:i+3]); end >= 0 {
+				i += 3 + end + 3
+			} else {
+				i = len(src)
+			}
+			tokens = append(tokens, codeToken{text: "S", span: Span{Start: start, End: i}})
+			continue
+		case r == '"' || r == '\'' || r == '`':
+			i = skipString(src, i, byte(r))
+			tokens = append(tokens, codeToken{text: "S", span: Span{Start: start, End: i}})
+			continue
+		case unicode.IsDigit(r):
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.') {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, codeToken{text: "N", span: Span{Start: start, End: i}})
+			continue
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$') {
+					break
+				}
+				i += size
+			}
+			text := "I"
+			if word := strings.ToLower(src[start:i]); keywords[word] {
+				text = word
+			}
+			tokens = append(tokens, codeToken{text: text, span: Span{Start: start, End: i}})
+			continue
+		}
+		i += size
+		tokens = append(tokens, codeToken{text: src[start:i], span: Span{Start: start, End: i}})
+	}
+	return tokens
+}
+
+// skipLine returns the index of the newline ending the line at i, or the
+// end of src.
+func skipLine(src string, i int) int {
+	if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(src)
+}
+
+// skipString returns the index just past the string literal starting at
+// i. Quotes other than backticks end at the end of the line.
+func skipString(src string, i int, quote byte) int {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		case quote:
+			return j + 1
+		}
+	}
+	return len(src)
+}
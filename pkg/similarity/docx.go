@@ -0,0 +1,75 @@
+package similarity
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// maxDocxXML caps how much of a .docx body is read, guarding against zip
+// bombs.
+const maxDocxXML = 32 << 20
+
+// ExtractDocx returns the text of a Word .docx file, one line per
+// paragraph.
+func ExtractDocx(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	var body *zip.File
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			body = f
+			break
+		}
+	}
+	if body == nil {
+		return "", errors.New("similarity: not a .docx file")
+	}
+
+	rc, err := body.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	var text strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxDocxXML))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
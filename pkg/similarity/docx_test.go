@@ -0,0 +1,64 @@
+package similarity
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractDocx(t *testing.T) {
+	const document = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:body>
+    <w:p><w:r><w:t>First </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>paragraph</w:t></w:r></w:p>
+    <w:p><w:r><w:t>Name</w:t><w:tab/><w:t>Value</w:t><w:br/><w:t>next line</w:t></w:r></w:p>
+    <w:p><w:r><w:instrText>PAGE</w:instrText></w:r></w:p>
+  </w:body>
+</w:document>`
+
+	data := zipFiles(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml":   document,
+	})
+	got, err := ExtractDocx(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "First paragraph\nName\tValue\nnext line\n\n"; got != want {
+		t.Fatalf("ExtractDocx = %q, want %q", got, want)
+	}
+}
+
+func TestExtractDocxRejectsOtherFiles(t *testing.T) {
+	tests := map[string][]byte{
+		"not a zip":        []byte("plain text"),
+		"zip without body": zipFiles(t, map[string]string{"content.xml": "<office/>"}),
+		"broken xml":       zipFiles(t, map[string]string{"word/document.xml": "<w:document><w:p>"}),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ExtractDocx(data); err == nil {
+				t.Fatal("ExtractDocx succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Package similarity finds passages shared by two documents. Prose is
+// compared with word shingles and MinHash; source code is compared with
+// winnowed fingerprints of normalized tokens, so renaming identifiers or
+// reformatting does not hide copied code.
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// Span is a byte range [Start, End) of a document's text.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Match is a passage found in both documents, at A in the first and at B in
+// the second.
+type Match struct {
+	A Span
+	B Span
+}
+
+// Kind tells how a document was fingerprinted.
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindCode
+)
+
+const (
+	// maxPositions caps how many places in the other document a shingle or
+	// k-gram is looked up at. Boilerplate that repeats throughout a
+	// document would otherwise make Matches quadratic.
+	maxPositions = 16
+	// maxMatchWork bounds the hash comparisons of one Matches call; passages
+	// past the point where it runs out are not reported.
+	maxMatchWork = 1 << 22
+)
+
+// gram is one shingle of words or k-gram of tokens: its hash and where it
+// is in the text.
+type gram struct {
+	hash uint64
+	span Span
+}
+
+// Document is a fingerprinted text. Documents of different kinds cannot be
+// compared.
+type Document struct {
+	kind  Kind
+	grams []gram
+	// signature is the MinHash signature of a text document.
+	signature []uint64
+	// fingerprints are the winnowed k-gram hashes of a code document.
+	fingerprints map[uint64]struct{}
+}
+
+// Kind returns how the document was fingerprinted.
+func (d *Document) Kind() Kind {
+	return d.kind
+}
+
+// Empty reports whether the document has nothing to compare, for example
+// because it is too short.
+func (d *Document) Empty() bool {
+	return len(d.grams) == 0
+}
+
+// Join combines documents fingerprinted from the parts of one text, where
+// docs[i] was made from the part starting at offsets[i]. Since every part
+// was fingerprinted on its own, no shingle or k-gram spans two parts. The
+// signature of joined text is exact: the minimum of a MinHash function over
+// a union is the smaller of its minimums over the parts. Documents not of
+// kind are left out.
+func Join(kind Kind, docs []*Document, offsets []int) *Document {
+	joined := &Document{kind: kind}
+	if kind == KindCode {
+		joined.fingerprints = map[uint64]struct{}{}
+	}
+	for i, doc := range docs {
+		if doc.kind != kind || doc.Empty() {
+			continue
+		}
+		for _, g := range doc.grams {
+			g.span.Start += offsets[i]
+			g.span.End += offsets[i]
+			joined.grams = append(joined.grams, g)
+		}
+		for h := range doc.fingerprints {
+			joined.fingerprints[h] = struct{}{}
+		}
+		if kind != KindText {
+			continue
+		}
+		if joined.signature == nil {
+			joined.signature = append([]uint64(nil), doc.signature...)
+			continue
+		}
+		for j, h := range doc.signature {
+			joined.signature[j] = min(joined.signature[j], h)
+		}
+	}
+	return joined
+}
+
+// Similarity scores how much two documents share, from 0 to 1. For text it
+// is the MinHash estimate of the Jaccard similarity of the word shingles;
+// for code it is the share of the smaller document's fingerprints that are
+// found in the other, so that copied code padded with extra code still
+// scores high.
+func (d *Document) Similarity(other *Document) float64 {
+	if d.kind != other.kind || d.Empty() || other.Empty() {
+		return 0
+	}
+	if d.kind == KindText {
+		same := 0
+		for i := range d.signature {
+			if d.signature[i] == other.signature[i] {
+				same++
+			}
+		}
+		return float64(same) / float64(len(d.signature))
+	}
+
+	small, large := d.fingerprints, other.fingerprints
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	if len(small) == 0 {
+		return 0
+	}
+	shared := 0
+	for h := range small {
+		if _, ok := large[h]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(small))
+}
+
+// Matches returns the passages the documents share, longest runs of shared
+// shingles or k-grams first merged into one match each, in the order they
+// appear in d. Runs are only looked for at the first maxPositions places a
+// shingle occurs in other, and the search stops after maxMatchWork
+// comparisons, so the cost stays linear in the size of the documents.
+func (d *Document) Matches(other *Document) []Match {
+	if d.kind != other.kind {
+		return nil
+	}
+
+	positions := make(map[uint64][]int, len(other.grams))
+	for j, g := range other.grams {
+		if len(positions[g.hash]) < maxPositions {
+			positions[g.hash] = append(positions[g.hash], j)
+		}
+	}
+
+	var matches []Match
+	work := 0
+	for i := 0; i < len(d.grams) && work < maxMatchWork; {
+		bestJ, bestLen := -1, 0
+		for _, j := range positions[d.grams[i].hash] {
+			n := 0
+			for i+n < len(d.grams) && j+n < len(other.grams) && d.grams[i+n].hash == other.grams[j+n].hash {
+				n++
+			}
+			work += n + 1
+			if n > bestLen {
+				bestJ, bestLen = j, n
+			}
+		}
+		if bestLen == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, Match{
+			A: Span{Start: d.grams[i].span.Start, End: d.grams[i+bestLen-1].span.End},
+			B: Span{Start: other.grams[bestJ].span.Start, End: other.grams[bestJ+bestLen-1].span.End},
+		})
+		i += bestLen
+	}
+	return mergeMatches(matches)
+}
+
+// mergeMatches joins matches that overlap in both documents, which happens
+// when a run is broken by a single differing shingle.
+func mergeMatches(matches []Match) []Match {
+	if len(matches) < 2 {
+		return matches
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].A.Start < matches[j].A.Start })
+
+	merged := matches[:1]
+	for _, m := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if m.A.Start <= last.A.End && m.B.Start <= last.B.End && m.B.End >= last.B.Start {
+			last.A.End = max(last.A.End, m.A.End)
+			last.B.Start = min(last.B.Start, m.B.Start)
+			last.B.End = max(last.B.End, m.B.End)
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+func hashTokens(tokens []string) uint64 {
+	h := fnv.New64a()
+	for _, t := range tokens {
+		h.Write([]byte(t))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
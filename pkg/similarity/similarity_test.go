@@ -0,0 +1,311 @@
+package similarity
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const pythonSource = `# Sum the even squares below n.
+def even_squares(n):
+    total = 0
+    for i in range(n):
+        if i % 2 == 0:
+            total += i * i
+    return total
+
+def main():
+    print(even_squares(100))
+`
+
+const goSource = `package stack
+
+// Stack is a LIFO queue of ints.
+type Stack struct {
+	items []int
+}
+
+func (s *Stack) Push(v int) {
+	s.items = append(s.items, v)
+}
+
+func (s *Stack) Pop() (int, bool) {
+	if len(s.items) == 0 {
+		return 0, false
+	}
+	v := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return v, true
+}
+`
+
+const essay = `The industrial revolution transformed the economies of Europe during the
+eighteenth and nineteenth centuries. Steam power allowed factories to be built
+far from rivers, and railways carried goods and workers across whole countries
+in a matter of days rather than weeks. Cities grew quickly as people left the
+countryside in search of work, which brought crowded housing and new public
+health problems that governments were slow to address.`
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     *Document
+		min, max float64
+	}{
+		{
+			name: "identical code",
+			a:    NewCode(pythonSource),
+			b:    NewCode(pythonSource),
+			min:  1, max: 1,
+		},
+		{
+			name: "renamed identifiers",
+			a:    NewCode(pythonSource),
+			b: NewCode(renameIdentifiers(pythonSource, map[string]string{
+				"even_squares": "sq_sum", "total": "acc", "i": "k", "main": "run", "n": "limit",
+			})),
+			min: 1, max: 1,
+		},
+		{
+			name: "reformatted code without comments",
+			a:    NewCode(goSource),
+			b: NewCode(`package stack
+type Stack struct { items []int }
+func (s *Stack) Push(v int) { s.items = append(s.items, v) }
+func (s *Stack) Pop() (int, bool) {
+	if len(s.items) == 0 { return 0, false }
+	v := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return v, true
+}`),
+			min: 1, max: 1,
+		},
+		{
+			name: "unrelated code",
+			a:    NewCode(pythonSource),
+			b:    NewCode(goSource),
+			min:  0, max: 0.1,
+		},
+		{
+			name: "identical text",
+			a:    NewText(essay),
+			b:    NewText(essay),
+			min:  1, max: 1,
+		},
+		{
+			name: "text differing in case and spacing",
+			a:    NewText(essay),
+			b:    NewText(strings.ToUpper(strings.Join(strings.Fields(essay), "  "))),
+			min:  1, max: 1,
+		},
+		{
+			name: "unrelated text",
+			a:    NewText(essay),
+			b: NewText(`Photosynthesis converts light energy into chemical energy stored
+in glucose. Chlorophyll in the chloroplasts absorbs mostly blue and red light,
+and the oxygen released as a by-product comes from splitting water molecules.`),
+			min: 0, max: 0.05,
+		},
+		{
+			name: "text against code",
+			a:    NewText(essay),
+			b:    NewCode(goSource),
+			min:  0, max: 0,
+		},
+		{
+			name: "empty document",
+			a:    NewText(""),
+			b:    NewText(essay),
+			min:  0, max: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.a.Similarity(tt.b)
+			if got < tt.min || got > tt.max {
+				t.Fatalf("Similarity = %.3f, want between %.2f and %.2f", got, tt.min, tt.max)
+			}
+			if back := tt.b.Similarity(tt.a); back != got {
+				t.Fatalf("Similarity is not symmetric: %.3f and %.3f", got, back)
+			}
+		})
+	}
+}
+
+// renameIdentifiers replaces whole identifiers of src.
+func renameIdentifiers(src string, names map[string]string) string {
+	return regexp.MustCompile(`\b[A-Za-z_]\w*\b`).ReplaceAllStringFunc(src, func(name string) string {
+		if renamed, ok := names[name]; ok {
+			return renamed
+		}
+		return name
+	})
+}
+
+func TestMatchSpans(t *testing.T) {
+	shared := "steam power allowed factories to be built far from rivers"
+	tests := []struct {
+		name  string
+		a, b  string
+		code  bool
+		wantA string
+		wantB string
+	}{
+		{
+			name:  "copied sentence",
+			a:     "My own introduction comes first. " + shared + ". My own conclusion.",
+			b:     "Something else entirely is written here, then " + shared + " and more.",
+			wantA: shared,
+			wantB: shared,
+		},
+		{
+			name:  "copied sentence with different case",
+			a:     "Intro words here: " + strings.ToUpper(shared) + "!",
+			b:     shared,
+			wantA: strings.ToUpper(shared),
+			wantB: shared,
+		},
+		{
+			name:  "copied function with renamed identifiers",
+			a:     "x = 1\n" + pythonSource,
+			b:     renameIdentifiers(pythonSource, map[string]string{"total": "acc"}) + "\nprint('done')\n",
+			code:  true,
+			wantA: strings.TrimSpace(pythonSource[strings.Index(pythonSource, "def"):]),
+			wantB: strings.TrimSpace(renameIdentifiers(pythonSource, map[string]string{"total": "acc"})[strings.Index(pythonSource, "def"):]),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newDoc := NewText
+			if tt.code {
+				newDoc = NewCode
+			}
+			matches := newDoc(tt.a).Matches(newDoc(tt.b))
+			if len(matches) != 1 {
+				t.Fatalf("got %d matches, want 1: %+v", len(matches), matches)
+			}
+			m := matches[0]
+			if got := tt.a[m.A.Start:m.A.End]; got != tt.wantA {
+				t.Errorf("span in a = %q, want %q", got, tt.wantA)
+			}
+			if got := tt.b[m.B.Start:m.B.End]; got != tt.wantB {
+				t.Errorf("span in b = %q, want %q", got, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestMatchesNothingShared(t *testing.T) {
+	if matches := NewText(essay).Matches(NewText("A short unrelated note about the weather today.")); len(matches) != 0 {
+		t.Fatalf("got matches %+v, want none", matches)
+	}
+	if matches := NewText(essay).Matches(NewCode(goSource)); matches != nil {
+		t.Fatalf("documents of different kinds matched: %+v", matches)
+	}
+}
+
+// TestMatchesRepetitiveInput guards against quadratic matching: documents
+// made of one repeated shingle used to compare every pair of positions.
+func TestMatchesRepetitiveInput(t *testing.T) {
+	a := NewText(strings.Repeat("lorem ipsum ", 50000) + "tail of a")
+	b := NewText(strings.Repeat("ipsum lorem ", 50000) + "tail of b")
+
+	start := time.Now()
+	matches := a.Matches(b)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Matches took %v", elapsed)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no matches in documents that share almost everything")
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   Kind
+		files  []string
+		other  string
+		newDoc func(string) *Document
+	}{
+		{
+			name: "text",
+			kind: KindText,
+			// The last words of one file and the first of the next read
+			// like a sentence that another submission contains.
+			files:  []string{"The first file talks about how steam power allowed\n", "factories to be built far from rivers."},
+			other:  "In short, steam power allowed factories to be built far from rivers.",
+			newDoc: NewText,
+		},
+		{
+			name:   "code",
+			kind:   KindCode,
+			files:  []string{pythonSource[:strings.Index(pythonSource, "for i")], pythonSource[strings.Index(pythonSource, "for i"):]},
+			other:  pythonSource,
+			newDoc: NewCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boundary := len(tt.files[0])
+			spansBoundary := func(matches []Match) bool {
+				for _, m := range matches {
+					if m.A.Start < boundary && m.A.End > boundary {
+						return true
+					}
+				}
+				return false
+			}
+			other := tt.newDoc(tt.other)
+
+			if !spansBoundary(tt.newDoc(tt.files[0] + tt.files[1]).Matches(other)) {
+				t.Fatal("the files do not form a passage across the boundary when fingerprinted together")
+			}
+			joined := Join(tt.kind, []*Document{tt.newDoc(tt.files[0]), tt.newDoc(tt.files[1])}, []int{0, boundary})
+			matches := joined.Matches(other)
+			if len(matches) == 0 {
+				t.Fatal("no matches within the files")
+			}
+			if spansBoundary(matches) {
+				t.Errorf("a match spans two files: %+v", matches)
+			}
+		})
+	}
+}
+
+func TestJoinSignature(t *testing.T) {
+	// Joining the parts of a text gives the signature of their shingles
+	// together, whatever the order.
+	a, b := NewText(essay[:len(essay)/2]), NewText(essay[len(essay)/2:])
+	ab, ba := Join(KindText, []*Document{a, b}, []int{0, 0}), Join(KindText, []*Document{b, a}, []int{0, 0})
+	if got := ab.Similarity(ba); got != 1 {
+		t.Errorf("similarity of the same parts joined in another order = %v, want 1", got)
+	}
+	if got := ab.Similarity(a); got <= 0.3 || got >= 0.7 {
+		t.Errorf("similarity of a joined text to its first half = %v, want about 0.5", got)
+	}
+	if Join(KindText, nil, nil).Similarity(a) != 0 {
+		t.Error("an empty join is similar to a text")
+	}
+}
+
+func TestMergeMatches(t *testing.T) {
+	matches := mergeMatches([]Match{
+		{A: Span{30, 40}, B: Span{130, 140}},
+		{A: Span{0, 10}, B: Span{100, 110}},
+		{A: Span{8, 20}, B: Span{108, 120}},
+	})
+	want := []Match{
+		{A: Span{0, 20}, B: Span{100, 120}},
+		{A: Span{30, 40}, B: Span{130, 140}},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("mergeMatches = %+v, want %+v", matches, want)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Fatalf("mergeMatches = %+v, want %+v", matches, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package similarity
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// shingleSize is how many consecutive words make a shingle. Five words
+	// rarely repeat by chance but survive light editing around them.
+	shingleSize = 5
+	// signatureSize is the number of MinHash functions; the similarity
+	// estimate is off by about 1/sqrt(signatureSize).
+	signatureSize = 128
+)
+
+// seeds perturb a shingle hash into the signatureSize hash functions.
+var seeds = func() []uint64 {
+	s := make([]uint64, signatureSize)
+	for i := range s {
+		s[i] = mix(uint64(i) + 1)
+	}
+	return s
+}()
+
+type word struct {
+	text string
+	span Span
+}
+
+// NewText fingerprints prose. Case, punctuation and spacing are ignored.
+func NewText(text string) *Document {
+	words := splitWords(text)
+	doc := &Document{kind: KindText}
+	if len(words) == 0 {
+		return doc
+	}
+
+	k := min(shingleSize, len(words))
+	tokens := make([]string, k)
+	for i := 0; i+k <= len(words); i++ {
+		for j := range tokens {
+			tokens[j] = words[i+j].text
+		}
+		doc.grams = append(doc.grams, gram{
+			hash: hashTokens(tokens),
+			span: Span{Start: words[i].span.Start, End: words[i+k-1].span.End},
+		})
+	}
+
+	doc.signature = make([]uint64, signatureSize)
+	for i := range doc.signature {
+		doc.signature[i] = math.MaxUint64
+	}
+	for _, g := range doc.grams {
+		for i, seed := range seeds {
+			if h := mix(g.hash ^ seed); h < doc.signature[i] {
+				doc.signature[i] = h
+			}
+		}
+	}
+	return doc
+}
+
+// splitWords returns the lower-cased runs of letters and digits in text.
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	for i := 0; i <= len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if i < len(text) && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if start < 0 {
+				start = i
+			}
+			i += size
+			continue
+		}
+		if start >= 0 {
+			words = append(words, word{text: strings.ToLower(text[start:i]), span: Span{Start: start, End: i}})
+			start = -1
+		}
+		if i == len(text) {
+			break
+		}
+		i += size
+	}
+	return words
+}
+
+// mix is the splitmix64 finalizer, used to derive independent hash
+// functions from one shingle hash.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "uploads")
+	store, err := NewLocal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := "submissions/ab/abcdef"
+	if err := store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "submissions", "ab", "abcdef")); err != nil {
+		t.Fatalf("blob not written below the root: %v", err)
+	}
+	if ok, err := store.Exists(ctx, key); err != nil || !ok {
+		t.Fatalf("Exists = %v, %v", ok, err)
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+
+	if err := store.Put(ctx, key, strings.NewReader("replaced"), 8, ""); err != nil {
+		t.Fatalf("Put over an existing blob: %v", err)
+	}
+	rc, _ = store.Get(ctx, key)
+	got, _ = io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "replaced" {
+		t.Fatalf("Get after replacing = %q", got)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if ok, err := store.Exists(ctx, key); err != nil || ok {
+		t.Fatalf("Exists after Delete = %v, %v", ok, err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing blob: %v", err)
+	}
+}
+
+func TestLocalShortBody(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(ctx, "a/b", strings.NewReader("abc"), 10, ""); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Put with a short body: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if ok, _ := store.Exists(ctx, "a/b"); ok {
+		t.Fatal("partial blob left in place")
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "a"))
+	if len(entries) != 0 {
+		t.Fatalf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestLocalRejectsInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../escape", "a/../../b", "a//b", "a/./b", `a\b`} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q): err = %v, want an invalid key error", key, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "eu-central-1"
+	testBucket    = "uploads"
+)
+
+var authorizationPattern = regexp.MustCompile(
+	`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=([a-z0-9;-]+), Signature=([0-9a-f]{64})$`)
+
+// fakeS3 is a path-style bucket that rejects requests whose SigV4 signature
+// it cannot reproduce.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verify(r); err != nil {
+		f.t.Logf("rejected %s %s: %v", r.Method, r.URL.Path, err)
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(body)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verify recomputes the signature from the request as received.
+func (f *fakeS3) verify(r *http.Request) error {
+	m := authorizationPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if m == nil {
+		return errors.New("malformed Authorization header")
+	}
+	accessKey, day, region, signedHeaders, signature := m[1], m[2], m[3], m[4], m[5]
+	if accessKey != testAccessKey || region != testRegion {
+		return errors.New("unexpected credential scope")
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, day) {
+		return errors.New("credential date does not match X-Amz-Date")
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return errors.New("missing X-Amz-Content-Sha256")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		canonicalHeaders.String() + "\n" + signedHeaders + "\n" + payloadHash
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + day + "/" + region + "/s3/aws4_request\n" +
+		hex.EncodeToString(requestHash[:])
+
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := mac(mac(mac(mac([]byte("AWS4"+testSecretKey), day), region), "s3"), "aws4_request")
+	if !hmac.Equal([]byte(hex.EncodeToString(mac(key, stringToSign))), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func newTestS3(t *testing.T, secret string) (*S3, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3(S3Config{
+		Endpoint:        server.URL,
+		Region:          testRegion,
+		Bucket:          testBucket,
+		AccessKeyID:     testAccessKey,
+		SecretAccessKey: secret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return time.Date(2024, 3, 1, 23, 59, 30, 0, time.UTC) }
+	return store, fake
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+	store, fake := newTestS3(t, testSecretKey)
+
+	keys := []string{"submissions/ab/abcdef", "reports/week 1/notes (final).txt", "empty"}
+	for _, key := range keys {
+		body := []byte("contents of " + key)
+		if key == "empty" {
+			body = nil
+		}
+		if err := store.Put(ctx, key, bytes.NewReader(body), int64(len(body)), "text/plain"); err != nil {
+			t.Fatalf("Put(%q): %v", key, err)
+		}
+		if got := fake.types[key]; got != "text/plain" {
+			t.Errorf("Put(%q) content type = %q", key, got)
+		}
+
+		ok, err := store.Exists(ctx, key)
+		if err != nil || !ok {
+			t.Fatalf("Exists(%q) = %v, %v", key, ok, err)
+		}
+		rc, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", key, err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil || !bytes.Equal(got, body) {
+			t.Fatalf("Get(%q) = %q, %v; want %q", key, got, err, body)
+		}
+	}
+
+	if err := store.Delete(ctx, keys[0]); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, keys[0]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if ok, err := store.Exists(ctx, keys[0]); err != nil || ok {
+		t.Fatalf("Exists after Delete = %v, %v", ok, err)
+	}
+	if err := store.Delete(ctx, keys[0]); err != nil {
+		t.Fatalf("Delete of a missing object: %v", err)
+	}
+}
+
+func TestS3RejectedSignature(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestS3(t, "not-the-secret")
+
+	err := store.Put(ctx, "k", strings.NewReader("x"), 1, "")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Put with a wrong secret: err = %v, want a 403", err)
+	}
+	if _, err := store.Get(ctx, "k"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get with a wrong secret: err = %v, want a 403", err)
+	}
+}
+
+func TestNewS3(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  S3Config
+		ok   bool
+	}{
+		{name: "valid", cfg: S3Config{Endpoint: "http://minio:9000/", Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s"}, ok: true},
+		{name: "no bucket", cfg: S3Config{Endpoint: "http://minio:9000", AccessKeyID: "a", SecretAccessKey: "s"}},
+		{name: "no credentials", cfg: S3Config{Endpoint: "http://minio:9000", Bucket: "b"}},
+		{name: "bad scheme", cfg: S3Config{Endpoint: "ftp://minio", Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s"}},
+		{name: "no host", cfg: S3Config{Endpoint: "minio:9000", Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewS3(tt.cfg)
+			if (err == nil) != tt.ok {
+				t.Fatalf("NewS3 err = %v, want ok = %v", err, tt.ok)
+			}
+			if tt.ok && store.cfg.Region != "us-east-1" {
+				t.Fatalf("default region = %q", store.cfg.Region)
+			}
+		})
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := map[string]string{
+		"/bucket/a/b.txt":     "/bucket/a/b.txt",
+		"/bucket/week 1/x+y":  "/bucket/week%201/x%2By",
+		"/bucket/ü~_-.":       "/bucket/%C3%BC~_-.",
+		"/bucket/(final).pdf": "/bucket/%28final%29.pdf",
+	}
+	for path, want := range tests {
+		if got := escapePath(path); got != want {
+			t.Errorf("escapePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
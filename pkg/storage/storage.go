@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned when no blob is stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps opaque blobs under slash-separated keys such as
+// "submissions/ab/abcdef". Blobs are written whole and never modified in
+// place; writing an existing key replaces the blob.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFromEnv builds the store selected by STORAGE_DRIVER: "local" (default)
+// keeps blobs under STORAGE_LOCAL_DIR and "s3" talks to any S3-compatible
+// service, such as MinIO, configured by the S3_* variables (see NewS3).
+func NewFromEnv() (BlobStore, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "data/uploads"
+		}
+		return NewLocal(dir)
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			VirtualHosted:   os.Getenv("S3_VIRTUAL_HOSTED") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+}
+
+// validKey rejects keys that could escape the store's namespace.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
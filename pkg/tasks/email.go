@@ -3,9 +3,19 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/arman300s/uni-portal/pkg/mailer"
 )
 
-const TypeSendWelcomeEmail = "send_welcome_email"
+const (
+	TypeSendWelcomeEmail       = "send_welcome_email"
+	TypeRequestPasswordReset   = "request_password_reset"
+	TypeSendVerificationEmail  = "send_verification_email"
+	TypeSendWaitlistPromotion  = "send_waitlist_promotion_email"
+)
 
 type SendWelcomeEmailPayload struct {
 	UserID uint
@@ -13,10 +23,90 @@ type SendWelcomeEmailPayload struct {
 	Name   string
 }
 
-func ExecuteSendWelcomeEmail(ctx context.Context, payload SendWelcomeEmailPayload) error {
-	//Just printing to the console, later will change to SendGrid for real email sending
-	fmt.Printf("📧 Sending welcome email to %s (%s)\n", payload.Name, payload.Email)
+// RequestPasswordResetPayload carries an address someone asked to reset the
+// password of. It is queued whether or not the address is registered; the
+// worker looks the account up.
+type RequestPasswordResetPayload struct {
+	Email string
+}
+
+// SendPasswordResetEmailPayload carries the plain reset token into the email;
+// only its hash is persisted in the database.
+type SendPasswordResetEmailPayload struct {
+	UserID    uint
+	Email     string
+	Name      string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// SendVerificationEmailPayload carries the plain verification token to the
+// worker; only its hash is persisted in the database.
+type SendVerificationEmailPayload struct {
+	UserID    uint
+	Email     string
+	Name      string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// SendWaitlistPromotionPayload tells a student that a seat opened up and
+// they were moved from the waitlist into the section.
+type SendWaitlistPromotionPayload struct {
+	UserID       uint
+	Email        string
+	Name         string
+	EnrollmentID uint
+	SubjectName  string
+	SectionCode  string
+	TermName     string
+}
+
+func ExecuteSendWelcomeEmail(ctx context.Context, m mailer.Mailer, payload SendWelcomeEmailPayload) error {
+	return m.Send(ctx, mailer.Message{
+		To:      payload.Email,
+		Subject: "Welcome to Uni Portal",
+		Body: fmt.Sprintf("Hi %s,\n\nyour Uni Portal account is ready. Sign in at %s.\n",
+			payload.Name, appURL()),
+	})
+}
+
+func ExecuteSendPasswordResetEmail(ctx context.Context, m mailer.Mailer, payload SendPasswordResetEmailPayload) error {
+	link := fmt.Sprintf("%s/password/reset?token=%s", appURL(), payload.Token)
+	return m.Send(ctx, mailer.Message{
+		To:      payload.Email,
+		Subject: "Reset your Uni Portal password",
+		Body: fmt.Sprintf("Hi %s,\n\nsomeone asked to reset the password of your Uni Portal account. "+
+			"To choose a new one, open\n\n%s\n\nThe link works once and expires on %s. "+
+			"If you did not ask for this, ignore this email; your password stays the same.\n",
+			payload.Name, link, payload.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+}
+
+func ExecuteSendVerificationEmail(ctx context.Context, m mailer.Mailer, payload SendVerificationEmailPayload) error {
+	link := fmt.Sprintf("%s/verify-email?token=%s", appURL(), payload.Token)
+	return m.Send(ctx, mailer.Message{
+		To:      payload.Email,
+		Subject: "Verify your Uni Portal email address",
+		Body: fmt.Sprintf("Hi %s,\n\nplease confirm this email address by opening\n\n%s\n\n"+
+			"The link expires on %s.\n",
+			payload.Name, link, payload.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+}
+
+func ExecuteSendWaitlistPromotion(ctx context.Context, m mailer.Mailer, payload SendWaitlistPromotionPayload) error {
+	return m.Send(ctx, mailer.Message{
+		To:      payload.Email,
+		Subject: fmt.Sprintf("You are now enrolled in %s", payload.SubjectName),
+		Body: fmt.Sprintf("Hi %s,\n\na seat opened up and you were moved from the waitlist into %s section %s (%s).\n",
+			payload.Name, payload.SubjectName, payload.SectionCode, payload.TermName),
+	})
+}
 
-	// TODO: integrate real SMTP later
-	return nil
+// appURL is where links in emails point.
+func appURL() string {
+	if u := os.Getenv("APP_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return "http://localhost:8079"
 }
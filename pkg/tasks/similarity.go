@@ -0,0 +1,12 @@
+package tasks
+
+const TypeCheckSimilarity = "check_submission_similarity"
+
+// CheckSimilarityPayload asks the worker to compare the submissions of an
+// assignment. Scheduled checks are enqueued for the deadline and skipped if
+// the deadline has since moved later, since another check was scheduled
+// for the new one.
+type CheckSimilarityPayload struct {
+	AssignmentID uint
+	Scheduled    bool
+}